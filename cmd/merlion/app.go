@@ -1,22 +1,27 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"slices"
+	"strings"
 
+	"merlion/cmd/merlion/parser"
 	"merlion/cmd/merlion/vault"
 	"merlion/internal/config"
 	"merlion/internal/context"
+	"merlion/internal/controls"
 	"merlion/internal/vault/cloud"
 	"merlion/internal/ui"
+	NotesUI "merlion/internal/ui/notes"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
 )
 
-func startTUI(flags ...string) {
+func startTUI(args *parser.Args) int {
 	// Setup profiling in dev env
 	if os.Getenv("APP_ENV") == "dev" {
 		log.Warn("Enabling pprof for profiling")
@@ -27,23 +32,30 @@ func startTUI(flags ...string) {
 
 	// Initialize theme manager
 	options := []context.ContextOption{}
-	if slices.Contains(flags, "--compact") {
+	if args.Bool("compact") {
 		options = append(options, context.WithCompactViewStart(true))
 	}
-	if slices.Contains(flags, "--no-save") {
+	if args.Bool("no-save") {
 		options = append(options, context.WithSaveOnChange(false))
 	}
-	if slices.Contains(flags, "--local") {
+	if args.Bool("local") {
 		options = append(options, context.WithLocalFirst(true))
-	} else if slices.Contains(flags, "--remote") {
+	} else if args.Bool("remote") {
 		options = append(options, context.WithLocalFirst(false))
 	}
-	if slices.Contains(flags, "--favorites") {
+	if args.Bool("favorites") {
 		options = append(options, context.WithFavorityOpen())
 	}
-	if slices.Contains(flags, "--work-logs") {
+	if args.Bool("work-logs") {
 		options = append(options, context.WithWorkLogOpen())
 	}
+	if name, ok := args.Lookup("tab"); ok {
+		names := NotesUI.TabNames(config.Load())
+		if !slices.ContainsFunc(names, func(tab string) bool { return strings.EqualFold(tab, name) }) {
+			return args.UsageError("unknown tab %q, expected one of: %s", name, strings.Join(names, ", "))
+		}
+		options = append(options, context.WithFirstTab(name))
+	}
 
 	// App CTX
 	ctx, err := context.NewContext(options...)
@@ -63,10 +75,14 @@ func startTUI(flags ...string) {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	if err := loadKeys(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
 	if cfg.Vaults == nil || len(cfg.Vaults) == 0 {
-		ok := vault.ChooseVault()
-		if ok != 0 {
-			os.Exit(1)
+		if code := vault.ChooseVault(); code != 0 {
+			return code
 		}
 	}
 
@@ -84,4 +100,32 @@ func startTUI(flags ...string) {
 	if _, err := p.Run(); err != nil {
 		log.Fatalf("Error running program: %v", err)
 	}
+	return 0
+}
+
+// loadKeys applies the key bindings of the config, refusing keys bound to several actions
+func loadKeys(cfg *config.UserConfig) error {
+	if err := controls.Load(cfg.KeyPreset, cfg.Keys); err != nil {
+		return fmt.Errorf("invalid key bindings in ~/.config/merlion/config.json: %w", err)
+	}
+	conflicts := controls.Keys.Conflicts()
+	if len(conflicts) == 0 {
+		return nil
+	}
+	lines := []string{}
+	for _, conflict := range conflicts {
+		lines = append(lines, "  "+conflict.String())
+	}
+	return fmt.Errorf("conflicting key bindings, change \"keys\" in ~/.config/merlion/config.json:\n%s", strings.Join(lines, "\n"))
+}
+
+// completeTabs completes the names of the built-in and user defined tabs
+func completeTabs(args *parser.Args, prefix string) []string {
+	result := []string{}
+	for _, name := range NotesUI.TabNames(config.Load()) {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			result = append(result, name)
+		}
+	}
+	return result
 }
@@ -3,7 +3,7 @@ package export
 
 import (
 	"fmt"
-	"os"
+	"strings"
 
 	"merlion/cmd/merlion/parser"
 	"merlion/internal/model"
@@ -11,7 +11,6 @@ import (
 	"merlion/internal/vault/cloud"
 	"merlion/internal/vault/files"
 	"merlion/internal/vault/sqlite"
-	"merlion/internal/utils"
 
 	"github.com/charmbracelet/log"
 )
@@ -50,62 +49,75 @@ func initCloudClient() (*cloud.Client, error) {
 	return cloudClient, nil
 }
 
-func printHelp(invalidArgs bool) {
-	if invalidArgs {
-		fmt.Println("Invalid arguments")
-	}
-
-	fmt.Println("Usage: merlion export <from-provider> <to-provider>")
-	fmt.Println("Provider: sqlite, file <obsidian-vault-path>, cloud")
-	fmt.Println("  - sqlite: export to a local SQLite database")
-	fmt.Println("  - file <obsidian-vault-path>: export to a local Obsidian vault")
-	fmt.Println("  - cloud: export to a cloud storage provider")
-	fmt.Println("Examples:")
-	fmt.Println("  merlion export sqlite files ~/notes")
-	fmt.Println("  merlion export sqlite cloud")
-
-	if invalidArgs {
-		os.Exit(1)
-	}
-	os.Exit(0)
+var providers = []string{"sqlite", "file", "cloud"}
+
+// Cmd exports all the notes of a store to another one
+var Cmd = &parser.Command{
+	Name:        "export",
+	Usage:       "<from-provider> <to-provider>",
+	Description: "Export the SQLite database to a Obsidian vault.",
+	Long: `Providers:
+  - sqlite: export to a local SQLite database
+  - file <obsidian-vault-path>: export to a local Obsidian vault
+  - cloud: export to a cloud storage provider
+
+Examples:
+  merlion export sqlite file ~/notes
+  merlion export sqlite cloud`,
+	Complete: func(args *parser.Args, prefix string) []string {
+		if n := len(args.Positionals); n > 0 && args.Positionals[n-1] == "file" {
+			// Path of the vault, completed by the shell
+			return nil
+		}
+		return providers
+	},
+	Run: run,
 }
 
-func parseStore(args []string) (vault.Store, []string) {
-	arg, args := parser.GetArg(args, printHelp)
-	switch arg {
+// parseStore returns the store described by the first arguments, and the remaining ones
+func parseStore(args []string) (vault.Store, []string, error) {
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("missing provider")
+	}
+	switch args[0] {
 	case "sqlite":
 		client, err := initSqliteDB()
 		if err != nil {
 			log.Fatalf("Failed to init SQLite client: %v", err)
 		}
-		return client, args
+		return client, args[1:], nil
 	case "file":
-		arg, args = parser.GetArg(args, printHelp)
-		client, err := initFileClient(arg)
+		if len(args) < 2 {
+			return nil, nil, fmt.Errorf("missing path of the Obsidian vault")
+		}
+		client, err := initFileClient(args[1])
 		if err != nil {
 			log.Fatalf("Failed to init file client: %v", err)
 		}
-		return client, args
+		return client, args[2:], nil
 	case "cloud":
 		client, err := initCloudClient()
 		if err != nil {
 			log.Fatalf("Failed to init cloud client: %v", err)
 		}
-		return client, args
+		return client, args[1:], nil
 	default:
-		printHelp(true)
+		return nil, nil, fmt.Errorf("unknown provider %s, expected one of: %s", args[0], strings.Join(providers, ", "))
 	}
-	return nil, nil
 }
 
-func Cmd(args ...string) int {
-	if len(args) == 0 || utils.Contains(args, "--help") || utils.Contains(args, "-h") {
-		printHelp(false)
+func run(args *parser.Args) int {
+	fromStore, rest, err := parseStore(args.Positionals)
+	if err != nil {
+		return args.UsageError("%v", err)
+	}
+	toStore, rest, err := parseStore(rest)
+	if err != nil {
+		return args.UsageError("%v", err)
+	}
+	if len(rest) > 0 {
+		return args.UsageError("unexpected argument %s", rest[0])
 	}
-
-	var fromStore, toStore vault.Store = nil, nil
-	fromStore, args = parseStore(args)
-	toStore, _ = parseStore(args)
 
 	fmt.Printf("Exporting %s -> %s\n", fromStore.Name(), toStore.Name())
 
@@ -2,11 +2,19 @@ package logout
 
 import (
 	"fmt"
+	"merlion/cmd/merlion/parser"
 	"merlion/internal/config"
 	"merlion/internal/vault/cloud"
 )
 
-func Cmd(args ...string) int {
+// Cmd removes the cloud credentials and vault
+var Cmd = &parser.Command{
+	Name:        "logout",
+	Description: "Removed the cached credentials and Cloud Vault",
+	Run:         run,
+}
+
+func run(args *parser.Args) int {
 	credMgr, err := cloud.NewCredentialsManager()
 	if err != nil {
 		fmt.Printf("Failed to initialize credentials manager: %v", err)
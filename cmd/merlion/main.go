@@ -4,73 +4,71 @@ import (
 	"fmt"
 	_ "net/http/pprof"
 	"os"
-	"strings"
 
 	"merlion/cmd/merlion/export"
 	"merlion/cmd/merlion/logout"
+	"merlion/cmd/merlion/notes"
 	"merlion/cmd/merlion/parser"
+	"merlion/cmd/merlion/recovery"
+	"merlion/cmd/merlion/rpc"
+	"merlion/cmd/merlion/theme"
+	"merlion/cmd/merlion/today"
 	"merlion/cmd/merlion/vault"
 	version "merlion/cmd/merlion/version"
 	"merlion/internal/utils"
 )
 
-type Command struct {
-	name        string
-	description string
-	run         func(args ...string) int
-}
-
-var COMMANDS []Command
+var root = &parser.Command{
+	Name: "merlion",
+	Description: `Merlion - TUI Note-taking application
+Run ` + "`merlion`" + ` without args to start in TUI mode, ` + "`merlion <command> --help`" + ` for more info on a command`,
+	Long: `For remote storage capability, create an account at:
+    https://note.merlion.dev/login
 
-func helpCmd(args ...string) int {
-	fmt.Println("Merlion - Help")
-	fmt.Println("")
-	fmt.Println("TUI Note-taking application")
-	fmt.Println("Run `merlion` without args to start in TUI mode")
-	fmt.Println("For remote storage capability, create an account at:")
-	fmt.Println("    https://note.merlion.dev/login")
-	fmt.Println("\nAvailable commands (run `merlion <command> --help` for more info):")
-	for _, cmd := range COMMANDS {
-		fmt.Printf("  %-10s %s\n", cmd.name, cmd.description)
-	}
-	fmt.Println("\nAvailable flags for TUI:")
-	fmt.Println("  --compact         Start in compact mode")
-	fmt.Println("  --no-save         Disable autosave of config")
-	fmt.Println("  --local           Show local notes when opening the app")
-	fmt.Println("  --remote          Show remote notes when opening the app")
-	fmt.Println("  --favorites       Show favorites tab when opening the app")
-	fmt.Println("  --work-logs       Show work logs tab when opening the app")
-	return 0
+Shell completion: ` + "`merlion completion --help`",
+	Flags: []parser.Flag{
+		{Name: "compact", Usage: "Start in compact mode"},
+		{Name: "no-save", Usage: "Disable autosave of config"},
+		{Name: "local", Usage: "Show local notes when opening the app"},
+		{Name: "remote", Usage: "Show remote notes when opening the app"},
+		{Name: "favorites", Usage: "Show favorites tab when opening the app"},
+		{Name: "work-logs", Usage: "Show work logs tab when opening the app"},
+		{Name: "tab", Value: "<name>", Usage: "Show this tab when opening the app", Complete: completeTabs},
+	},
+	Run: startTUI,
 }
 
 func init() {
-	COMMANDS = []Command{
-		{
-			name:        "help",
-			description: "Show help information",
-			run:         helpCmd,
-		},
-		{
-			name:        "vault",
-			description: "Create a new note vault storage, switch between them using `)`",
-			run:         vault.Cmd,
-		},
-		{
-			name:        "logout",
-			description: "Removed the cached credentials and Cloud Vault",
-			run:         logout.Cmd,
-		},
-		{
-			name:        "version",
-			description: "Show version information",
-			run:         version.Cmd,
-		},
+	root.Subcommands = []*parser.Command{
 		{
-			name:        "export",
-			description: "Export the SQLite database to a Obsidian vault.",
-			run:         export.Cmd,
+			Name:        "help",
+			Description: "Show help information",
+			Run: func(args *parser.Args) int {
+				root.PrintHelp(os.Stdout)
+				return parser.ExitOK
+			},
 		},
+		vault.Cmd,
+		logout.Cmd,
+		version.Cmd,
+		notes.ListCmd,
+		notes.CatCmd,
+		notes.NewCmd,
+		notes.EditCmd,
+		notes.RemoveCmd,
+		notes.MoveCmd,
+		notes.TagCmd,
+		notes.FavCmd,
+		notes.WorkLogCmd,
+		notes.CaptureCmd,
+		today.Cmd,
+		today.YesterdayCmd,
+		export.Cmd,
+		rpc.Cmd,
+		theme.Cmd,
+		recovery.Cmd,
 	}
+	root.Subcommands = append(root.Subcommands, parser.CompletionCommands(root)...)
 }
 
 func main() {
@@ -91,31 +89,5 @@ func main() {
 		}
 	}()
 
-	flags, commands := parser.SplitCmdsAndFlags(os.Args[1:])
-
-	// Handle --help flag
-	for _, flag := range flags {
-		if flag == "--help" {
-			commands = append(commands, "help")
-		}
-	}
-
-	// Handle commands
-	if len(commands) > 0 {
-		command := commands[0]
-		args := commands[1:]
-
-		for _, cmd := range COMMANDS {
-			if strings.EqualFold(cmd.name, command) {
-				os.Exit(cmd.run(args...))
-			}
-		}
-
-		fmt.Printf("Unknown command: %s\n", command)
-		helpCmd()
-		os.Exit(1)
-	}
-
-	// Default: start TUI
-	startTUI(flags...)
+	os.Exit(parser.Execute(root, os.Args[1:]))
 }
@@ -0,0 +1,95 @@
+package notes
+
+import (
+	"strings"
+	"time"
+
+	"merlion/cmd/merlion/parser"
+	"merlion/internal/capture"
+	"merlion/internal/config"
+)
+
+// CaptureCmd saves stdin, or a message, in a new inbox note or in an existing note
+var CaptureCmd = &parser.Command{
+	Name:        "capture",
+	Usage:       "[<note>]",
+	Description: "Capture stdin or a message in a new inbox note, or append it to a note",
+	Flags: withCommonFlags(
+		parser.Flag{Name: "message", Short: "m", Value: "<text>", Usage: "Text to capture, instead of stdin"},
+		parser.Flag{Name: "prepend", Usage: "Insert at the top of the note instead of the bottom"},
+		parser.Flag{Name: "timestamp", Usage: "Add a date and time header before the text"},
+		parser.Flag{Name: "code", Usage: "Wrap the text in a fenced code block"},
+		parser.Flag{Name: "lang", Value: "<lang>", Usage: "Language of the code block, implies --code"},
+		parser.Flag{Name: "tags", Value: "<a,b>", Usage: "Tags to add to the note"},
+		parser.Flag{Name: "title", Value: "<title>", Usage: "Title of the new inbox note (default: Capture <date time>)"},
+	),
+	Complete: completeFirstNote,
+	Long: `Without <note>, a new note tagged with the inbox tag is created.
+The inbox tag is configured with captureInbox in ~/.config/merlion/config.json (default: inbox)
+
+Examples:
+  kubectl logs api | tail -50 | merlion capture Incidents --lang log --timestamp
+  merlion capture -m "Try the new cache layer"
+  merlion capture "Reading list" --prepend -m "https://go.dev/blog"
+
+` + exitCodesHelp,
+	Run: runCapture,
+}
+
+func runCapture(args *parser.Args) int {
+	if len(args.Positionals) > 1 {
+		return args.UsageError("expected at most one note, quote titles with spaces")
+	}
+	if _, ok := args.Lookup("title"); ok && len(args.Positionals) > 0 {
+		return args.UsageError("--title is only used to create an inbox note")
+	}
+
+	text, ok := args.Lookup("message")
+	if !ok {
+		if !stdinIsPiped() {
+			return args.UsageError("nothing to capture, pipe some text or use -m")
+		}
+		content, err := readStdin()
+		if err != nil {
+			return fail(err)
+		}
+		text = content
+	}
+	if strings.TrimSpace(text) == "" {
+		return args.UsageError("nothing to capture, the text is empty")
+	}
+
+	opts := capture.Options{
+		Timestamp: args.Bool("timestamp"),
+		Prepend:   args.Bool("prepend"),
+	}
+	lang, hasLang := args.Lookup("lang")
+	opts.Code = lang
+	opts.IsCode = args.Bool("code") || hasLang
+	if tags, ok := args.Lookup("tags"); ok {
+		opts.Tags = strings.Split(tags, ",")
+	}
+
+	manager, err := LoadManager(args)
+	if err != nil {
+		return fail(err)
+	}
+
+	if len(args.Positionals) == 0 {
+		note, err := capture.New(manager, config.Load(), args.String("title"), text, opts, time.Now())
+		if err != nil {
+			return fail(err)
+		}
+		return printResult(args, note, "Captured in '%s'", note.Title)
+	}
+
+	note, err := resolveNote(manager, args.Positionals[0])
+	if err != nil {
+		return fail(err)
+	}
+	updated, err := capture.Add(manager, note, text, opts, time.Now())
+	if err != nil {
+		return fail(err)
+	}
+	return printResult(args, updated, "Captured in '%s'", updated.Title)
+}
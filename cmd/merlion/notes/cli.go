@@ -0,0 +1,159 @@
+// Package notes implements the scriptable commands to manage notes without the TUI
+// (ls, cat, new, edit, rm, mv, tag, fav, worklog, capture)
+//
+// Exit codes:
+//   - 0: success
+//   - 1: error
+//   - 2: invalid usage
+//   - 3: note not found
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"merlion/cmd/merlion/parser"
+	"merlion/internal/config"
+	"merlion/internal/model"
+	"merlion/internal/vault"
+	"merlion/internal/vault/clientError"
+	"merlion/internal/vault/cloud"
+)
+
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitNotFound = 3
+)
+
+const (
+	vaultFlag = "vault"
+	jsonFlag  = "json"
+)
+
+// commonFlags are accepted by every command
+var commonFlags = []parser.Flag{
+	{Name: vaultFlag, Value: "<name>", Usage: "Use the vault with this name (default: first vault of the config)", Complete: CompleteVaults},
+	{Name: jsonFlag, Usage: "Print the result as JSON"},
+}
+
+const exitCodesHelp = "Exit codes: 0 success, 1 error, 2 invalid usage, 3 note not found"
+
+// withCommonFlags returns the flags of a command followed by the common ones
+func withCommonFlags(flags ...parser.Flag) []parser.Flag {
+	return append(flags, commonFlags...)
+}
+
+// fail prints the error and returns the matching exit code
+func fail(err error) int {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if errors.Is(err, clientError.ErrNoteNotFound) {
+		return ExitNotFound
+	}
+	return ExitError
+}
+
+// LoadManager returns a manager with its notes loaded, on the vault named by the `--vault` flag
+// or the first configured vault
+func LoadManager(args *parser.Args) (*vault.Manager, error) {
+	cfg := config.Load()
+	if len(cfg.Vaults) == 0 {
+		return nil, fmt.Errorf("no vault configured, run `merlion vault` first")
+	}
+	credMgr, err := cloud.NewCredentialsManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credentials manager: %w", err)
+	}
+	manager := vault.NewManager(cfg, credMgr, false)
+	if name, ok := args.Lookup(vaultFlag); ok {
+		if err := manager.UseStore(name); err != nil {
+			return nil, fmt.Errorf("%w, available vaults: %s", err, strings.Join(manager.StoreNames(), ", "))
+		}
+	}
+	if _, err := manager.ListNoteMetadata(); err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	return manager, nil
+}
+
+// resolveNote finds a note by ID or by title, and returns it with its content
+func resolveNote(manager *vault.Manager, ref string) (*model.Note, error) {
+	note := manager.SearchByID(ref)
+	if note == nil {
+		note = manager.SearchByTitle(ref)
+	}
+	if note == nil {
+		return nil, fmt.Errorf("%w: %s", clientError.ErrNoteNotFound, ref)
+	}
+	return manager.GetFullNote(note.NoteID)
+}
+
+func printJSON(v any) int {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fail(fmt.Errorf("failed to encode JSON: %w", err))
+	}
+	return ExitOK
+}
+
+// printResult prints the note as JSON if asked, the message otherwise
+func printResult(args *parser.Args, note *model.Note, msg string, a ...any) int {
+	if args.Bool(jsonFlag) {
+		return printJSON(note)
+	}
+	fmt.Printf(msg+"\n", a...)
+	return ExitOK
+}
+
+// stdinIsPiped reports if the content can be read from stdin
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+func readStdin() (string, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return string(data), nil
+}
+
+// CompleteVaults completes the names of the configured vaults
+func CompleteVaults(args *parser.Args, prefix string) []string {
+	names := []string{}
+	for _, vault := range config.Load().Vaults {
+		names = append(names, vault.Name)
+	}
+	return names
+}
+
+// CompleteNotes completes the titles of the notes of the vault
+func CompleteNotes(args *parser.Args, prefix string) []string {
+	manager, err := LoadManager(args)
+	if err != nil {
+		return nil
+	}
+	titles := make([]string, 0, len(manager.Notes))
+	for _, note := range manager.Notes {
+		titles = append(titles, note.Title)
+	}
+	return titles
+}
+
+// completeFirstNote completes the titles of the notes for the first positional argument only
+func completeFirstNote(args *parser.Args, prefix string) []string {
+	if len(args.Positionals) > 0 {
+		return nil
+	}
+	return CompleteNotes(args, prefix)
+}
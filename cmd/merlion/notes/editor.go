@@ -0,0 +1,58 @@
+package notes
+
+import (
+	"fmt"
+	"os"
+
+	"merlion/internal/config"
+	"merlion/internal/edit"
+	"merlion/internal/model"
+	"merlion/internal/vault"
+
+	"github.com/charmbracelet/x/editor"
+)
+
+const recoverHint = "the edit is kept, run `merlion recover` to apply it later"
+
+// EditInEditor opens the note in $EDITOR and saves it when the content changed, merged with the changes made meanwhile.
+// The edit is recorded until it is saved, see `merlion recover`
+func EditInEditor(manager *vault.Manager, note *model.Note) error {
+	session, err := edit.Begin(manager, note.NoteID)
+	if err != nil {
+		return err
+	}
+	text := session.BaseContent()
+	header := config.Load().EditorFrontMatter
+	if header {
+		text = edit.WithHeader(session.Header(), text)
+	}
+	if err := session.Record(manager.Name, header, text); err != nil {
+		return fmt.Errorf("could not record the edit: %w", err)
+	}
+
+	cmd, err := editor.Cmd("Note", session.DraftPath())
+	if err != nil {
+		session.Close()
+		return fmt.Errorf("failed to create editor command: %w", err)
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w, %s", err, recoverHint)
+	}
+
+	newContent, err := os.ReadFile(session.DraftPath())
+	if err != nil {
+		return fmt.Errorf("failed to read edited content: %w, %s", err, recoverHint)
+	}
+	result, err := session.SaveDraft(manager, string(newContent))
+	if err != nil {
+		return fmt.Errorf("failed to save the edited content: %w, %s", err, recoverHint)
+	}
+	switch result.Outcome {
+	case edit.Conflict:
+		return fmt.Errorf("'%s' changed while you were editing it, %s", note.Title, recoverHint)
+	case edit.Deleted:
+		return fmt.Errorf("'%s' was deleted while you were editing it, %s", note.Title, recoverHint)
+	}
+	return session.Close()
+}
@@ -0,0 +1,194 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"merlion/cmd/merlion/parser"
+	"merlion/internal/model"
+	"merlion/internal/query"
+	"merlion/internal/sorting"
+)
+
+// ListCmd lists the notes of the vault
+var ListCmd = &parser.Command{
+	Name:        "ls",
+	Description: "List the notes of a vault",
+	Flags: withCommonFlags(
+		parser.Flag{Name: "query", Short: "q", Value: "<query>", Usage: "Only the notes matching the query, see below"},
+		parser.Flag{Name: "tag", Value: "<tag>", Usage: "Only the notes with this tag, or one of its sub tags", Complete: completeTags},
+		parser.Flag{Name: "fav", Usage: "Only the favorite notes"},
+		parser.Flag{Name: "worklog", Usage: "Only the work logs"},
+		parser.Flag{Name: "sort", Value: "<order>", Usage: "updated, created, title or property:<name>, with an optional :asc or :desc", Complete: completeSort},
+		parser.Flag{Name: "pin-fav", Usage: "List the favorite notes first"},
+	),
+	Long: `Print one title per line, followed by the tags
+The notes are sorted by update date, newest first, unless --sort is given
+
+` + queryHelp + `
+
+Examples:
+  merlion ls --tag work --json | jq '.[].title'
+  merlion ls -q 'tag:infra -tag:archived updated:<7d'
+  merlion ls --sort title
+  merlion ls --sort property:priority:desc --pin-fav
+
+` + exitCodesHelp,
+	Run: runList,
+}
+
+// CatCmd prints the content of a note
+var CatCmd = &parser.Command{
+	Name:        "cat",
+	Usage:       "<note>",
+	Description: "Print the content of a note, found by ID or title",
+	Flags:       withCommonFlags(),
+	Long:        exitCodesHelp,
+	Complete:    completeFirstNote,
+	Run:         runCat,
+}
+
+func runList(args *parser.Args) int {
+	if len(args.Positionals) > 0 {
+		return args.UsageError("unexpected argument %s", args.Positionals[0])
+	}
+	manager, err := LoadManager(args)
+	if err != nil {
+		return fail(err)
+	}
+
+	order := sorting.Default("")
+	if value, ok := args.Lookup("sort"); ok {
+		if order, err = sorting.Parse(value); err != nil {
+			return args.UsageError("%v", err)
+		}
+	}
+	order.PinFavorites = args.Bool("pin-fav")
+
+	candidates := manager.Notes
+	if text, ok := args.Lookup("query"); ok && strings.TrimSpace(text) != "" {
+		q, err := query.Parse(text, time.Now())
+		if err != nil {
+			return queryError(text, err)
+		}
+		if candidates, err = manager.Query(q); err != nil {
+			return fail(err)
+		}
+	}
+
+	tag, filterTag := args.Lookup("tag")
+	notes := []model.Note{}
+	for _, note := range candidates {
+		if filterTag && !query.HasTag(note, tag) {
+			continue
+		}
+		if args.Bool("fav") && !note.IsFavorite {
+			continue
+		}
+		if args.Bool("worklog") && !note.IsWorkLog {
+			continue
+		}
+		// Listing only exposes the metadata
+		note.Content = nil
+		notes = append(notes, note)
+	}
+	sorting.Sort(notes, order)
+
+	if args.Bool(jsonFlag) {
+		return printJSON(notes)
+	}
+	for _, note := range notes {
+		if len(note.Tags) > 0 {
+			fmt.Printf("%s\t%s\n", note.Title, strings.Join(note.Tags, ","))
+		} else {
+			fmt.Println(note.Title)
+		}
+	}
+	return ExitOK
+}
+
+func runCat(args *parser.Args) int {
+	if len(args.Positionals) != 1 {
+		return args.UsageError("expected one note")
+	}
+
+	manager, err := LoadManager(args)
+	if err != nil {
+		return fail(err)
+	}
+	note, err := resolveNote(manager, args.Positionals[0])
+	if err != nil {
+		return fail(err)
+	}
+
+	if args.Bool(jsonFlag) {
+		return printJSON(note)
+	}
+	if note.Content != nil {
+		fmt.Print(*note.Content)
+	}
+	return ExitOK
+}
+
+// completeTags completes the tags used in the vault
+func completeTags(args *parser.Args, prefix string) []string {
+	manager, err := LoadManager(args)
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, note := range manager.Notes {
+		for _, tag := range note.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// completeSort completes the sort fields, and the directions after a field
+func completeSort(args *parser.Args, prefix string) []string {
+	candidates := []string{}
+	for _, field := range sorting.Fields {
+		if field == sorting.ByProperty {
+			candidates = append(candidates, field+":")
+			continue
+		}
+		candidates = append(candidates, field, field+":asc", field+":desc")
+	}
+	result := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+const queryHelp = `Query: space separated terms, all of them must match, prefix a term with - to exclude it
+  word "exact phrase"       In the title, the aliases or the content
+  title:<text>              In the title
+  content:<text>            In the content
+  tag:<tag>                 The tag, or one of its nested tags
+  is:fav|worklog|public|untagged
+  created:<date>            On the day, or with <, <=, > or >=
+  updated:<date>            Dates are YYYY-MM-DD, today, yesterday or ages like 12h, 7d, 2w, 3m, 1y
+                            e.g. updated:<7d for the notes updated less than 7 days ago`
+
+// queryError prints the query with the invalid part underlined
+func queryError(text string, err error) int {
+	fmt.Fprintf(os.Stderr, "Error: invalid query: %v\n", err)
+	var parseErr *query.Error
+	if errors.As(err, &parseErr) {
+		fmt.Fprintf(os.Stderr, "  %s\n  %s\n", text, parseErr.Pointer())
+	}
+	return ExitUsage
+}
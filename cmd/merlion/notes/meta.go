@@ -0,0 +1,169 @@
+package notes
+
+import (
+	"strings"
+
+	"merlion/cmd/merlion/parser"
+	"merlion/internal/model"
+)
+
+// TagCmd adds or removes tags of a note
+var TagCmd = &parser.Command{
+	Name:        "tag",
+	Description: "Add or remove tags of a note",
+	Subcommands: []*parser.Command{
+		{
+			Name:        "add",
+			Usage:       "<note> <tag>...",
+			Description: "Add tags to a note",
+			Flags:       withCommonFlags(),
+			Complete:    completeNoteThenTags,
+			Long: `Examples:
+  merlion tag add "Sprint planning" work/sprint meeting
+
+` + exitCodesHelp,
+			Run: func(args *parser.Args) int {
+				return runTag(args, addTags)
+			},
+		},
+		{
+			Name:        "remove",
+			Usage:       "<note> <tag>...",
+			Description: "Remove tags of a note",
+			Flags:       withCommonFlags(),
+			Complete:    completeNoteThenTags,
+			Long:        exitCodesHelp,
+			Run: func(args *parser.Args) int {
+				return runTag(args, removeTags)
+			},
+		},
+	},
+}
+
+// FavCmd marks a note as favorite
+var FavCmd = &parser.Command{
+	Name:        "fav",
+	Usage:       "<note>",
+	Description: "Mark a note as favorite (--off to unmark)",
+	Flags:       withCommonFlags(parser.Flag{Name: "off", Usage: "Unmark the note"}),
+	Complete:    completeFirstNote,
+	Long:        exitCodesHelp,
+	Run: func(args *parser.Args) int {
+		return setFlag(args, "favorite", func(req *model.CreateNoteRequest, value bool) {
+			req.IsFavorite = &value
+		})
+	},
+}
+
+// WorkLogCmd marks a note as work log
+var WorkLogCmd = &parser.Command{
+	Name:        "worklog",
+	Usage:       "<note>",
+	Description: "Mark a note as work log (--off to unmark)",
+	Flags:       withCommonFlags(parser.Flag{Name: "off", Usage: "Unmark the note"}),
+	Complete:    completeFirstNote,
+	Long:        exitCodesHelp,
+	Run: func(args *parser.Args) int {
+		return setFlag(args, "work log", func(req *model.CreateNoteRequest, value bool) {
+			req.IsWorkLog = &value
+		})
+	},
+}
+
+func runTag(args *parser.Args, apply func(tags []string, changed []string) []string) int {
+	if len(args.Positionals) < 2 {
+		return args.UsageError("expected a note and at least one tag")
+	}
+	ref, tags := args.Positionals[0], args.Positionals[1:]
+
+	manager, err := LoadManager(args)
+	if err != nil {
+		return fail(err)
+	}
+	note, err := resolveNote(manager, ref)
+	if err != nil {
+		return fail(err)
+	}
+
+	req := note.ToCreateRequest()
+	req.Tags = apply(note.Tags, tags)
+	updated, err := manager.UpdateNote(note.NoteID, req)
+	if err != nil {
+		return fail(err)
+	}
+	return printResult(args, updated, "Tags of '%s': %s", updated.Title, strings.Join(updated.Tags, ", "))
+}
+
+func setFlag(args *parser.Args, name string, set func(*model.CreateNoteRequest, bool)) int {
+	if len(args.Positionals) != 1 {
+		return args.UsageError("expected one note")
+	}
+
+	manager, err := LoadManager(args)
+	if err != nil {
+		return fail(err)
+	}
+	note, err := resolveNote(manager, args.Positionals[0])
+	if err != nil {
+		return fail(err)
+	}
+
+	value := !args.Bool("off")
+	req := note.ToCreateRequest()
+	set(&req, value)
+	updated, err := manager.UpdateNote(note.NoteID, req)
+	if err != nil {
+		return fail(err)
+	}
+	if value {
+		return printResult(args, updated, "'%s' marked as %s", updated.Title, name)
+	}
+	return printResult(args, updated, "'%s' unmarked as %s", updated.Title, name)
+}
+
+// completeNoteThenTags completes a note title, then the tags of the vault
+func completeNoteThenTags(args *parser.Args, prefix string) []string {
+	if len(args.Positionals) == 0 {
+		return CompleteNotes(args, prefix)
+	}
+	return completeTags(args, prefix)
+}
+
+// addTags appends the new tags, skipping the empty ones and the ones already present
+func addTags(tags []string, newTags []string) []string {
+	result := append([]string{}, tags...)
+	for _, tag := range newTags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		exists := false
+		for _, existing := range result {
+			if strings.EqualFold(existing, tag) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func removeTags(tags []string, toRemove []string) []string {
+	result := []string{}
+	for _, tag := range tags {
+		keep := true
+		for _, remove := range toRemove {
+			if strings.EqualFold(tag, strings.TrimSpace(remove)) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package notes
+
+import (
+	"strings"
+	"time"
+
+	"merlion/cmd/merlion/parser"
+	"merlion/internal/model"
+	"merlion/internal/templates"
+)
+
+// NewCmd creates a note
+var NewCmd = &parser.Command{
+	Name:        "new",
+	Usage:       "<title>",
+	Description: "Create a new note, optionally from a template, the content is read from stdin when piped",
+	Flags: withCommonFlags(
+		parser.Flag{Name: "template", Value: "<name>", Usage: "Create the note from a template of the vault", Complete: completeTemplates},
+		parser.Flag{Name: "tags", Value: "<a,b>", Usage: "Tags of the note"},
+		parser.Flag{Name: "fav", Usage: "Mark the note as favorite"},
+		parser.Flag{Name: "worklog", Usage: "Mark the note as work log"},
+	),
+	Long: `Templates are markdown files, located in:
+  - files: the Obsidian templates folder of the vault (default: Templates)
+  - sqlite & cloud: ~/.config/merlion/templates/<vault name>
+Supported variables: {{title}}, {{date}}, {{date:2006-01-02}}, {{time}}, {{time:15:04}}
+
+Examples:
+  merlion new "Sprint planning" --template meeting
+  kubectl get pods | merlion new "Pods" --tags infra
+
+` + exitCodesHelp,
+	Run: runNew,
+}
+
+func runNew(args *parser.Args) int {
+	title := strings.Join(args.Positionals, " ")
+
+	manager, err := LoadManager(args)
+	if err != nil {
+		return fail(err)
+	}
+
+	req := model.CreateNoteRequest{Title: title}
+	if templateName, ok := args.Lookup("template"); ok {
+		dir, err := manager.TemplatesDir()
+		if err != nil {
+			return fail(err)
+		}
+		template, err := templates.Load(dir, templateName)
+		if err != nil {
+			return fail(err)
+		}
+		req = template.Apply(title, time.Now())
+	}
+
+	if strings.TrimSpace(req.Title) == "" {
+		return args.UsageError("a title is required")
+	}
+
+	if stdinIsPiped() {
+		content, err := readStdin()
+		if err != nil {
+			return fail(err)
+		}
+		if req.Content != nil && *req.Content != "" {
+			content = *req.Content + "\n" + content
+		}
+		req.Content = &content
+	}
+	if tags, ok := args.Lookup("tags"); ok {
+		req.Tags = addTags(req.Tags, strings.Split(tags, ","))
+	}
+	if args.Bool("fav") {
+		isFavorite := true
+		req.IsFavorite = &isFavorite
+	}
+	if args.Bool("worklog") {
+		isWorkLog := true
+		req.IsWorkLog = &isWorkLog
+	}
+
+	note, err := manager.CreateNote(req)
+	if err != nil {
+		return fail(err)
+	}
+	return printResult(args, note, "Created note '%s' in %s", note.Title, manager.Name)
+}
+
+// completeTemplates completes the names of the templates of the vault
+func completeTemplates(args *parser.Args, prefix string) []string {
+	manager, err := LoadManager(args)
+	if err != nil {
+		return nil
+	}
+	dir, err := manager.TemplatesDir()
+	if err != nil {
+		return nil
+	}
+	list, err := templates.List(dir)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(list))
+	for _, template := range list {
+		names = append(names, template.Name)
+	}
+	return names
+}
@@ -0,0 +1,177 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+
+	"merlion/cmd/merlion/parser"
+	"merlion/internal/links"
+	"merlion/internal/model"
+)
+
+// EditCmd edits the content of a note
+var EditCmd = &parser.Command{
+	Name:        "edit",
+	Usage:       "<note>",
+	Description: "Edit a note in $EDITOR, or replace its content with stdin when piped",
+	Flags:       withCommonFlags(),
+	Complete:    completeFirstNote,
+	Long: `Examples:
+  merlion edit "Sprint planning"
+  cat draft.md | merlion edit "Sprint planning"
+
+` + exitCodesHelp,
+	Run: runEdit,
+}
+
+// RemoveCmd deletes notes
+var RemoveCmd = &parser.Command{
+	Name:        "rm",
+	Usage:       "<note>...",
+	Description: "Delete notes, found by ID or title (files are moved to the trash)",
+	Flags:       withCommonFlags(),
+	Complete:    CompleteNotes,
+	Long:        exitCodesHelp,
+	Run:         runRemove,
+}
+
+// MoveCmd renames a note
+var MoveCmd = &parser.Command{
+	Name:        "mv",
+	Usage:       "<note> <new title>",
+	Description: "Rename a note, and update the wiki links pointing to it",
+	Flags:       withCommonFlags(parser.Flag{Name: "dry-run", Usage: "Print the links to update, without renaming"}),
+	Complete:    completeFirstNote,
+	Long: `The [[Title]], [[Title#Heading]] and [[Title|Alias]] links of the vault are rewritten with the rename,
+and the rename is reverted if a note can't be updated. Links using an alias of the note are kept.
+
+` + exitCodesHelp,
+	Run: runMove,
+}
+
+func runEdit(args *parser.Args) int {
+	refs := args.Positionals
+	if len(refs) != 1 {
+		return args.UsageError("expected one note")
+	}
+
+	manager, err := LoadManager(args)
+	if err != nil {
+		return fail(err)
+	}
+	note, err := resolveNote(manager, refs[0])
+	if err != nil {
+		return fail(err)
+	}
+
+	if !stdinIsPiped() {
+		if err := EditInEditor(manager, note); err != nil {
+			return fail(err)
+		}
+		return ExitOK
+	}
+
+	content, err := readStdin()
+	if err != nil {
+		return fail(err)
+	}
+	req := note.ToCreateRequest()
+	req.Content = &content
+	updated, err := manager.UpdateNote(note.NoteID, req)
+	if err != nil {
+		return fail(err)
+	}
+	return printResult(args, updated, "Updated '%s'", updated.Title)
+}
+
+func runRemove(args *parser.Args) int {
+	refs := args.Positionals
+	if len(refs) == 0 {
+		return args.UsageError("expected at least one note")
+	}
+
+	manager, err := LoadManager(args)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Resolve everything first, nothing is deleted on a typo
+	ids := make([]string, 0, len(refs))
+	titles := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		note, err := resolveNote(manager, ref)
+		if err != nil {
+			return fail(err)
+		}
+		ids = append(ids, note.NoteID)
+		titles = append(titles, note.Title)
+	}
+
+	for i, id := range ids {
+		if err := manager.DeleteNote(id); err != nil {
+			return fail(fmt.Errorf("failed to delete '%s': %w", titles[i], err))
+		}
+		if !args.Bool(jsonFlag) {
+			fmt.Printf("Deleted '%s'\n", titles[i])
+		}
+	}
+	if args.Bool(jsonFlag) {
+		return printJSON(map[string][]string{"deleted": ids})
+	}
+	return ExitOK
+}
+
+func runMove(args *parser.Args) int {
+	refs := args.Positionals
+	if len(refs) < 2 {
+		return args.UsageError("expected a note and its new title")
+	}
+	newTitle := strings.Join(refs[1:], " ")
+
+	manager, err := LoadManager(args)
+	if err != nil {
+		return fail(err)
+	}
+	note, err := resolveNote(manager, refs[0])
+	if err != nil {
+		return fail(err)
+	}
+	rename, err := links.PlanRename(manager, note.NoteID, newTitle)
+	if err != nil {
+		return fail(err)
+	}
+
+	if args.Bool("dry-run") {
+		if args.Bool(jsonFlag) {
+			return printJSON(rename)
+		}
+		fmt.Printf("Would rename '%s' -> '%s', updating %s\n", rename.OldTitle, rename.NewTitle, rename.Summary())
+		printEdits(rename)
+		return ExitOK
+	}
+
+	updated, err := rename.Apply(manager)
+	if err != nil {
+		return fail(err)
+	}
+	if args.Bool(jsonFlag) {
+		// The fields of the note, as before the link rewriting, with the edits
+		return printJSON(struct {
+			*model.Note
+			Edits []links.Edit `json:"edits"`
+		}{updated, rename.Edits})
+	}
+	fmt.Printf("Renamed '%s' -> '%s', updated %s\n", rename.OldTitle, updated.Title, rename.Summary())
+	printEdits(rename)
+	return ExitOK
+}
+
+// printEdits prints the rewritten links of each note
+func printEdits(rename *links.Rename) {
+	for _, edit := range rename.Edits {
+		fmt.Printf("  %s\n", edit.Note.Title)
+		for i, link := range edit.Links {
+			fmt.Printf("    %d: %s -> %s\n", link.Line, edit.OldLinks[i], edit.NewLinks[i])
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Hidden command called by the completion scripts: `merlion __complete <shell> <words>... <current word>`
+const completeCmd = "__complete"
+
+const bashScript = `# bash completion for merlion, add to ~/.bashrc:
+#   source <(merlion completion bash)
+_merlion_completion() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    local candidates
+    candidates=$(merlion __complete bash "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)
+    COMPREPLY=()
+    local candidate
+    for candidate in $candidates; do
+        COMPREPLY+=("$(printf '%q' "$candidate")")
+    done
+    if [[ ${#COMPREPLY[@]} -eq 1 && ${COMPREPLY[0]} == *= ]]; then
+        compopt -o nospace
+    fi
+}
+complete -F _merlion_completion merlion
+`
+
+const zshScript = `#compdef merlion
+# zsh completion for merlion, add to ~/.zshrc:
+#   source <(merlion completion zsh)
+_merlion() {
+    local -a candidates
+    candidates=("${(@f)$(merlion __complete zsh "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    local -a flags values
+    local candidate
+    for candidate in $candidates; do
+        [[ -z $candidate ]] && continue
+        if [[ $candidate == *= ]]; then
+            flags+=("$candidate")
+        else
+            values+=("$candidate")
+        fi
+    done
+    (( ${#flags} )) && compadd -S '' -- $flags
+    (( ${#values} )) && compadd -- $values
+}
+if [[ $funcstack[1] == _merlion ]]; then
+    _merlion "$@"
+else
+    compdef _merlion merlion
+fi
+`
+
+const fishScript = `# fish completion for merlion, add to ~/.config/fish/config.fish:
+#   merlion completion fish | source
+function __merlion_complete
+    set -l words (commandline -opc)[2..-1]
+    merlion __complete fish $words (commandline -ct) 2>/dev/null
+end
+complete -c merlion -f -a '(__merlion_complete)'
+`
+
+// CompletionCommands returns the `completion` command printing the scripts,
+// and the hidden command used by the scripts to complete the command line
+func CompletionCommands(root *Command) []*Command {
+	shells := []string{"bash", "zsh", "fish"}
+	completion := &Command{
+		Name:        "completion",
+		Usage:       "<bash|zsh|fish>",
+		Description: "Print the shell completion script",
+		Long: `Examples:
+  source <(merlion completion bash)   # ~/.bashrc
+  source <(merlion completion zsh)    # ~/.zshrc
+  merlion completion fish | source    # ~/.config/fish/config.fish`,
+		Complete: func(args *Args, prefix string) []string {
+			if len(args.Positionals) > 0 {
+				return nil
+			}
+			return shells
+		},
+		Run: func(args *Args) int {
+			if len(args.Positionals) != 1 {
+				return args.UsageError("expected a shell: %s", strings.Join(shells, ", "))
+			}
+			switch args.Positionals[0] {
+			case "bash":
+				os.Stdout.WriteString(bashScript)
+			case "zsh":
+				os.Stdout.WriteString(zshScript)
+			case "fish":
+				os.Stdout.WriteString(fishScript)
+			default:
+				return args.UsageError("unknown shell %s, expected one of: %s", args.Positionals[0], strings.Join(shells, ", "))
+			}
+			return ExitOK
+		},
+	}
+
+	complete := &Command{
+		Name:    completeCmd,
+		Hidden:  true,
+		RawArgs: true, // Everything after the shell name are words of the command line, flags included
+		Run: func(args *Args) int {
+			if len(args.Positionals) == 0 {
+				return ExitUsage
+			}
+			for _, candidate := range Complete(root, args.Positionals[0], args.Positionals[1:]) {
+				fmt.Println(candidate)
+			}
+			return ExitOK
+		},
+	}
+	return []*Command{completion, complete}
+}
+
+// Complete returns the candidates for the last word of the command line
+// For bash, `--flag=value` is split in three words by the shell, values are returned without the flag
+func Complete(root *Command, shell string, words []string) []string {
+	root.link()
+	if shell == "bash" {
+		words = joinAssignments(words)
+	}
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+	previous := words[:len(words)-1]
+
+	// Find the command being completed
+	cmd := root
+	i := 0
+	for ; i < len(previous) && len(cmd.Subcommands) > 0 && !strings.HasPrefix(previous[i], "-"); i++ {
+		sub := cmd.findSubcommand(previous[i])
+		if sub == nil {
+			return nil
+		}
+		cmd = sub
+	}
+	args, _ := cmd.Parse(previous[i:])
+
+	// Value of a flag: `--vault=Glo` or `--vault Glo`
+	if strings.HasPrefix(current, "--") && strings.Contains(current, "=") {
+		name, prefix, _ := strings.Cut(strings.TrimPrefix(current, "--"), "=")
+		flag := cmd.findFlag(name)
+		if flag == nil || flag.Complete == nil {
+			return nil
+		}
+		values := filterPrefix(flag.Complete(args, prefix), prefix)
+		if shell != "bash" {
+			for j := range values {
+				values[j] = "--" + name + "=" + values[j]
+			}
+		}
+		return values
+	}
+	if len(previous) > i {
+		last := previous[len(previous)-1]
+		if flag := cmd.findFlag(strings.TrimPrefix(last, "--")); strings.HasPrefix(last, "--") && flag != nil && !flag.isBool() {
+			if flag.Complete == nil {
+				return nil
+			}
+			return filterPrefix(flag.Complete(args, current), current)
+		}
+	}
+
+	// Flags
+	if strings.HasPrefix(current, "-") {
+		candidates := []string{"--help"}
+		for _, flag := range cmd.Flags {
+			if flag.isBool() {
+				candidates = append(candidates, "--"+flag.Name)
+			} else {
+				candidates = append(candidates, "--"+flag.Name+"=")
+			}
+		}
+		return filterPrefix(candidates, current)
+	}
+
+	// Sub commands or positional arguments
+	if len(cmd.Subcommands) > 0 {
+		var candidates []string
+		for _, sub := range cmd.Subcommands {
+			if !sub.Hidden {
+				candidates = append(candidates, sub.Name)
+			}
+		}
+		return filterPrefix(candidates, current)
+	}
+	if cmd.Complete != nil {
+		return filterPrefix(cmd.Complete(args, current), current)
+	}
+	return nil
+}
+
+// joinAssignments merges the `--flag`, `=`, `value` words split by bash
+func joinAssignments(words []string) []string {
+	var result []string
+	for i := 0; i < len(words); i++ {
+		if words[i] == "=" && len(result) > 0 {
+			result[len(result)-1] += "="
+			if i+1 < len(words) {
+				i++
+				result[len(result)-1] += words[i]
+			}
+			continue
+		}
+		result = append(result, words[i])
+	}
+	return result
+}
+
+func filterPrefix(candidates []string, prefix string) []string {
+	var result []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(prefix)) {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
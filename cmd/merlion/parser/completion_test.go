@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name  string
+		shell string
+		words []string
+		want  []string
+	}{
+		{name: "no word", shell: "fish", words: nil, want: []string{"capture", "run"}},
+		{name: "sub commands", shell: "fish", words: []string{"c"}, want: []string{"capture"}},
+		{name: "hidden command", shell: "fish", words: []string{"hid"}, want: nil},
+		{name: "unknown command", shell: "fish", words: []string{"unknown", ""}, want: nil},
+		{
+			name:  "flags",
+			shell: "zsh",
+			words: []string{"capture", "--"},
+			want:  []string{"--help", "--message=", "--prepend", "--vault="},
+		},
+		{name: "flags with a prefix", shell: "zsh", words: []string{"capture", "--p"}, want: []string{"--prepend"}},
+		{name: "positional", shell: "zsh", words: []string{"capture", "i"}, want: []string{"Inbox", "Ideas"}},
+		{name: "second positional", shell: "zsh", words: []string{"capture", "Inbox", ""}, want: nil},
+		{name: "after a boolean flag", shell: "zsh", words: []string{"capture", "--prepend", "r"}, want: []string{"Roadmap"}},
+		{name: "value in the next word", shell: "fish", words: []string{"capture", "--vault", "g"}, want: []string{"Global", "Git"}},
+		{name: "value without completion", shell: "fish", words: []string{"capture", "--message", ""}, want: nil},
+		{
+			name:  "value after =",
+			shell: "zsh",
+			words: []string{"capture", "--vault=gl"},
+			want:  []string{"--vault=Global"},
+		},
+		{
+			name:  "bash splits the value after =",
+			shell: "bash",
+			words: []string{"capture", "--vault", "=", "gl"},
+			want:  []string{"Global"},
+		},
+		{
+			name:  "bash with an empty value",
+			shell: "bash",
+			words: []string{"capture", "--vault", "="},
+			want:  []string{"Global", "Git", "obsidian"},
+		},
+		{
+			name:  "bash after a complete assignment",
+			shell: "bash",
+			words: []string{"capture", "--vault", "=", "Git", "r"},
+			want:  []string{"Roadmap"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Complete(testCommand(), tt.shell, tt.words); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Complete(%s, %q) = %q, expected %q", tt.shell, tt.words, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinAssignments(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{"list", "--vault", "=", "Global"}, []string{"list", "--vault=Global"}},
+		{[]string{"list", "--vault", "="}, []string{"list", "--vault="}},
+		{[]string{"list", "--vault", "=", "Global", "--sort", "=", "title"}, []string{"list", "--vault=Global", "--sort=title"}},
+		{[]string{"=", "list"}, []string{"=", "list"}},
+		{[]string{"list", "a=b"}, []string{"list", "a=b"}},
+	}
+	for _, tt := range tests {
+		if got := joinAssignments(tt.words); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("joinAssignments(%q) = %q, expected %q", tt.words, got, tt.want)
+		}
+	}
+}
@@ -1,30 +1,234 @@
 // Package parser contains the logic to parse the command line arguments
+// Commands declare their flags, the help and the shell completions are generated from them
 package parser
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/log"
 )
 
-func GetArg(args []string, helpFunc func(bool)) (string, []string) {
-	if len(args) == 0 {
-		helpFunc(true)
+const (
+	ExitOK    = 0
+	ExitUsage = 2
+)
+
+// CompleteFunc returns the candidates to complete the current word
+// args contains what was parsed on the command line so far
+type CompleteFunc func(args *Args, prefix string) []string
+
+// Flag is a `--name` boolean flag, or a `--name=<value>` flag when Value is set
+type Flag struct {
+	Name     string
+	Short    string // Optional one letter alias, e.g. `-m`
+	Value    string // Placeholder of the value in the help, empty for boolean flags
+	Usage    string
+	Complete CompleteFunc
+}
+
+func (f Flag) isBool() bool {
+	return f.Value == ""
+}
+
+type Command struct {
+	Name        string
+	Usage       string // Synopsis of the positional arguments, e.g. `<note> <tag>...`
+	Description string
+	Long        string // Printed after the flags in the help (examples, ...)
+	Flags       []Flag
+	Subcommands []*Command
+	Complete    CompleteFunc // Completion of the positional arguments
+	Run         func(args *Args) int
+	Hidden      bool
+	RawArgs     bool // Flags aren't parsed, all the arguments are positionals
+
+	parent *Command
+}
+
+// Args are the parsed arguments of a command
+type Args struct {
+	Positionals []string
+	Help        bool
+	values      map[string]string
+	command     *Command
+}
+
+// Bool returns true if the boolean flag was set
+func (a *Args) Bool(name string) bool {
+	value, ok := a.values[name]
+	return ok && value != "false"
+}
+
+// String returns the value of the flag, empty if not set
+func (a *Args) String(name string) string {
+	return a.values[name]
+}
+
+// Lookup returns the value of the flag, and if it was set
+func (a *Args) Lookup(name string) (string, bool) {
+	value, ok := a.values[name]
+	return value, ok
+}
+
+// UsageError prints the error followed by the help of the command
+func (a *Args) UsageError(format string, v ...any) int {
+	fmt.Fprintf(os.Stderr, "Error: "+format+"\n\n", v...)
+	a.command.PrintHelp(os.Stderr)
+	return ExitUsage
+}
+
+// Path returns the full name of the command, e.g. `merlion tag add`
+func (c *Command) Path() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.Path() + " " + c.Name
+}
+
+func (c *Command) findSubcommand(name string) *Command {
+	for _, sub := range c.Subcommands {
+		if strings.EqualFold(sub.Name, name) {
+			return sub
+		}
 	}
-	currArg := args[0]
-	args = args[1:]
-	log.Debugf("currArg: %s", currArg)
-	return currArg, args
+	return nil
 }
 
-// ParseArgs separates flags from commands and returns both
-func SplitCmdsAndFlags(args []string) (flags []string, commands []string) {
-	for _, arg := range args {
-		if strings.HasPrefix(arg, "--") {
-			flags = append(flags, arg)
-		} else {
-			commands = append(commands, arg)
+func (c *Command) findFlag(name string) *Flag {
+	for i := range c.Flags {
+		if c.Flags[i].Name == name || (c.Flags[i].Short != "" && c.Flags[i].Short == name) {
+			return &c.Flags[i]
 		}
 	}
-	return flags, commands
+	return nil
+}
+
+// link sets the parent of all the sub commands
+func (c *Command) link() {
+	for _, sub := range c.Subcommands {
+		sub.parent = c
+		sub.link()
+	}
+}
+
+// Parse the flags and the positional arguments of the command
+func (c *Command) Parse(argv []string) (*Args, error) {
+	args := &Args{values: make(map[string]string), command: c}
+	if c.RawArgs {
+		args.Positionals = argv
+		return args, nil
+	}
+	for i := 0; i < len(argv); i++ {
+		arg := argv[i]
+		switch {
+		case arg == "--":
+			args.Positionals = append(args.Positionals, argv[i+1:]...)
+			return args, nil
+		case arg == "--help" || arg == "-h":
+			args.Help = true
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			flag := c.findFlag(name)
+			long := strings.HasPrefix(arg, "--")
+			if flag == nil || (long && flag.Name != name) || (!long && flag.Short != name) {
+				return args, fmt.Errorf("unknown flag %s", strings.SplitN(arg, "=", 2)[0])
+			}
+			name = flag.Name
+			if flag.isBool() {
+				if !hasValue {
+					value = "true"
+				} else if value != "true" && value != "false" {
+					return args, fmt.Errorf("flag --%s expects no value", name)
+				}
+			} else if !hasValue {
+				if i+1 >= len(argv) {
+					return args, fmt.Errorf("flag --%s expects a value %s", name, flag.Value)
+				}
+				i++
+				value = argv[i]
+			}
+			args.values[name] = value
+		default:
+			args.Positionals = append(args.Positionals, arg)
+		}
+	}
+	return args, nil
+}
+
+// Execute finds the command to run, parses its arguments and runs it
+func Execute(root *Command, argv []string) int {
+	root.link()
+	cmd := root
+	for len(argv) > 0 && len(cmd.Subcommands) > 0 && !strings.HasPrefix(argv[0], "-") {
+		sub := cmd.findSubcommand(argv[0])
+		if sub == nil {
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", argv[0])
+			cmd.PrintHelp(os.Stderr)
+			return ExitUsage
+		}
+		cmd = sub
+		argv = argv[1:]
+	}
+
+	args, err := cmd.Parse(argv)
+	log.Debug("Parsed arguments", "command", cmd.Path(), "args", args.Positionals, "flags", args.values)
+	if args.Help {
+		cmd.PrintHelp(os.Stdout)
+		return ExitOK
+	}
+	if err != nil {
+		return args.UsageError("%v", err)
+	}
+	if cmd.Run == nil {
+		return args.UsageError("missing command")
+	}
+	return cmd.Run(args)
+}
+
+// PrintHelp writes the help of the command, generated from its definition
+func (c *Command) PrintHelp(w io.Writer) {
+	usage := "Usage: " + c.Path()
+	if len(c.Subcommands) > 0 {
+		usage += " <command>"
+	}
+	if len(c.Flags) > 0 {
+		usage += " [flags]"
+	}
+	if c.Usage != "" {
+		usage += " " + c.Usage
+	}
+	fmt.Fprintln(w, usage)
+	if c.Description != "" {
+		fmt.Fprintln(w, c.Description)
+	}
+
+	if len(c.Subcommands) > 0 {
+		fmt.Fprintln(w, "\nCommands:")
+		for _, sub := range c.Subcommands {
+			if !sub.Hidden {
+				fmt.Fprintf(w, "  %-12s %s\n", sub.Name, sub.Description)
+			}
+		}
+	}
+
+	fmt.Fprintln(w, "\nFlags:")
+	for _, flag := range c.Flags {
+		name := "--" + flag.Name
+		if flag.Short != "" {
+			name = "-" + flag.Short + ", " + name
+		}
+		if !flag.isBool() {
+			name += "=" + flag.Value
+		}
+		fmt.Fprintf(w, "  %-24s %s\n", name, flag.Usage)
+	}
+	fmt.Fprintf(w, "  %-24s %s\n", "-h, --help", "Show this help")
+
+	if c.Long != "" {
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, strings.TrimSpace(c.Long))
+	}
 }
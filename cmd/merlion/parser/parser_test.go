@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testCommand() *Command {
+	root := &Command{
+		Name: "merlion",
+		Subcommands: []*Command{
+			{
+				Name: "capture",
+				Flags: []Flag{
+					{Name: "message", Short: "m", Value: "<text>"},
+					{Name: "prepend"},
+					{Name: "vault", Value: "<name>", Complete: func(args *Args, prefix string) []string {
+						return []string{"Global", "Git", "obsidian"}
+					}},
+				},
+				Complete: func(args *Args, prefix string) []string {
+					if len(args.Positionals) > 0 {
+						return nil
+					}
+					return []string{"Inbox", "Ideas", "Roadmap"}
+				},
+			},
+			{Name: "run", RawArgs: true},
+			{Name: "hidden", Hidden: true},
+		},
+	}
+	root.link()
+	return root
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		argv        []string
+		positionals []string
+		values      map[string]string
+		help        bool
+		err         string
+	}{
+		{name: "empty", argv: nil, values: map[string]string{}},
+		{
+			name:        "positionals",
+			argv:        []string{"Inbox", "-"},
+			positionals: []string{"Inbox", "-"},
+			values:      map[string]string{},
+		},
+		{
+			name:   "value in the next word",
+			argv:   []string{"--message", "hello world"},
+			values: map[string]string{"message": "hello world"},
+		},
+		{
+			name:   "value after =",
+			argv:   []string{"--message=a=b", "--vault="},
+			values: map[string]string{"message": "a=b", "vault": ""},
+		},
+		{
+			name:        "short flag",
+			argv:        []string{"-m", "hello", "Inbox"},
+			positionals: []string{"Inbox"},
+			values:      map[string]string{"message": "hello"},
+		},
+		{
+			name:   "short flag with =",
+			argv:   []string{"-m=hello"},
+			values: map[string]string{"message": "hello"},
+		},
+		{
+			name:   "boolean flags",
+			argv:   []string{"--prepend"},
+			values: map[string]string{"prepend": "true"},
+		},
+		{
+			name:   "boolean flag set to false",
+			argv:   []string{"--prepend=false"},
+			values: map[string]string{"prepend": "false"},
+		},
+		{
+			name:   "help",
+			argv:   []string{"-h", "--prepend"},
+			values: map[string]string{"prepend": "true"},
+			help:   true,
+		},
+		{
+			name:        "end of the flags",
+			argv:        []string{"--prepend", "--", "--message", "-m"},
+			positionals: []string{"--message", "-m"},
+			values:      map[string]string{"prepend": "true"},
+		},
+		{name: "unknown flag", argv: []string{"--unknown=1"}, err: "unknown flag --unknown"},
+		{name: "short name written long", argv: []string{"--m", "hello"}, err: "unknown flag --m"},
+		{name: "long name written short", argv: []string{"-message", "hello"}, err: "unknown flag -message"},
+		{name: "missing value", argv: []string{"--message"}, err: "flag --message expects a value <text>"},
+		{name: "value of a boolean flag", argv: []string{"--prepend=yes"}, err: "flag --prepend expects no value"},
+	}
+	capture := testCommand().findSubcommand("capture")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := capture.Parse(tt.argv)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("Parse(%q) error = %v, expected %q", tt.argv, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.argv, err)
+			}
+			if !reflect.DeepEqual(args.Positionals, tt.positionals) {
+				t.Errorf("Positionals = %q, expected %q", args.Positionals, tt.positionals)
+			}
+			if !reflect.DeepEqual(args.values, tt.values) {
+				t.Errorf("values = %v, expected %v", args.values, tt.values)
+			}
+			if args.Help != tt.help {
+				t.Errorf("Help = %v, expected %v", args.Help, tt.help)
+			}
+		})
+	}
+}
+
+func TestParseRawArgs(t *testing.T) {
+	argv := []string{"bash", "capture", "--unknown", "-m"}
+	args, err := testCommand().findSubcommand("run").Parse(argv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(args.Positionals, argv) {
+		t.Errorf("Positionals = %q, expected %q", args.Positionals, argv)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	args, err := testCommand().findSubcommand("capture").Parse([]string{"--prepend=false", "-m", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args.Bool("prepend") {
+		t.Errorf("Bool(prepend) = true for --prepend=false")
+	}
+	if value, ok := args.Lookup("message"); !ok || value != "" {
+		t.Errorf("Lookup(message) = %q, %v, expected an empty value which is set", value, ok)
+	}
+	if _, ok := args.Lookup("vault"); ok || args.String("vault") != "" {
+		t.Errorf("vault is set without the flag")
+	}
+}
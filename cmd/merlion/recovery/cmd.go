@@ -0,0 +1,271 @@
+// Package recovery implements `merlion recover`, to apply or discard the edits interrupted by a crash
+package recovery
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"merlion/cmd/merlion/parser"
+	"merlion/internal/config"
+	"merlion/internal/edit"
+	"merlion/internal/merge"
+	"merlion/internal/vault"
+	"merlion/internal/vault/cloud"
+)
+
+const long = `The edits are recorded in the data directory (e.g. ~/.local/share/merlion/sessions) until they are saved
+or discarded. Merlion offers to re-apply the interrupted edits of a vault when it opens it.
+
+Examples:
+  merlion recover                       # List the interrupted edits
+  merlion recover diff 20260102-1504    # The changes of an edit, by ID or its start
+  merlion recover apply --copy <id>     # Save an edit conflicting with the changes made since as a new note`
+
+// Cmd lists the interrupted edits
+var Cmd = &parser.Command{
+	Name:        "recover",
+	Description: "List, diff, apply or discard the edits interrupted by a crash",
+	Long:        long,
+	Run:         runList,
+	Subcommands: []*parser.Command{
+		{
+			Name:        "list",
+			Description: "List the interrupted edits, the oldest first",
+			Run:         runList,
+		},
+		{
+			Name:        "diff",
+			Usage:       "<id>",
+			Description: "Show the changes of an edit, from the note it started from",
+			Complete:    completeIDs,
+			Run:         runDiff,
+		},
+		{
+			Name:        "apply",
+			Usage:       "<id>...",
+			Description: "Save the edits, merged with the changes made since",
+			Flags: []parser.Flag{
+				{Name: "force", Usage: "Overwrite the changes made since when they conflict"},
+				{Name: "copy", Usage: "Save the conflicting edits, or the edits of deleted notes, as new notes"},
+			},
+			Complete: completeIDs,
+			Run:      runApply,
+		},
+		{
+			Name:        "discard",
+			Usage:       "<id>...",
+			Description: "Delete the edits",
+			Flags: []parser.Flag{
+				{Name: "all", Usage: "Delete all the interrupted edits"},
+			},
+			Complete: completeIDs,
+			Run:      runDiscard,
+		},
+	},
+}
+
+func fail(err error) int {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return 1
+}
+
+func runList(args *parser.Args) int {
+	if len(args.Positionals) > 0 {
+		return args.UsageError("unexpected argument %s", args.Positionals[0])
+	}
+	entries, err := edit.Pending()
+	if err != nil {
+		return fail(err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No interrupted edit")
+		return 0
+	}
+	for _, entry := range entries {
+		state := ""
+		if entry.Running() {
+			state = " (being edited)"
+		}
+		fmt.Printf("%s  %s  %s/%s%s\n", entry.ID, entry.StartedAt.Format("2006-01-02 15:04"), entry.Vault, entry.Base.Title, state)
+	}
+	return 0
+}
+
+func runDiff(args *parser.Args) int {
+	if len(args.Positionals) != 1 {
+		return args.UsageError("expected the ID of an edit")
+	}
+	entry, err := edit.FindEntry(args.Positionals[0])
+	if err != nil {
+		return fail(err)
+	}
+	draft, err := entry.Draft()
+	if err != nil {
+		return fail(err)
+	}
+	base := ""
+	if entry.Base.Content != nil {
+		base = *entry.Base.Content
+	}
+	if entry.Header {
+		base = edit.WithHeader(edit.HeaderOf(entry.Base), base)
+	}
+
+	fmt.Printf("--- %s/%s, %s\n", entry.Vault, entry.Base.Title, entry.Base.UpdatedAt.Format("2006-01-02 15:04"))
+	fmt.Printf("+++ edit %s, %s\n", entry.ID, entry.StartedAt.Format("2006-01-02 15:04"))
+	fmt.Print(merge.Diff(base, draft, 3))
+	return 0
+}
+
+func runApply(args *parser.Args) int {
+	if len(args.Positionals) == 0 {
+		return args.UsageError("expected the ID of an edit")
+	}
+	force, asCopy := args.Bool("force"), args.Bool("copy")
+	if force && asCopy {
+		return args.UsageError("--force and --copy can't be used together")
+	}
+	entries, err := findEntries(args.Positionals)
+	if err != nil {
+		return fail(err)
+	}
+
+	code := 0
+	for _, entry := range entries {
+		if err := apply(entry, force, asCopy); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", entry.ID, err)
+			code = 1
+		}
+	}
+	return code
+}
+
+// apply saves the edit, the conflicts are resolved by the flags
+func apply(entry edit.Entry, force bool, asCopy bool) error {
+	manager, err := loadManager(entry.Vault)
+	if err != nil {
+		return err
+	}
+	session, result, err := edit.Apply(manager, entry)
+	if err != nil {
+		return err
+	}
+
+	switch result.Outcome {
+	case edit.Unchanged:
+		fmt.Printf("'%s' already had these changes\n", entry.Base.Title)
+		return nil
+	case edit.Saved:
+		fmt.Printf("Saved '%s'\n", result.Note.Title)
+		return nil
+	case edit.Merged:
+		fmt.Printf("Saved '%s', merged with the changes made since\n", result.Note.Title)
+		return nil
+	}
+
+	problem := "conflicts with the changes made since"
+	if result.Outcome == edit.Deleted {
+		problem = "the note was deleted since"
+	}
+	switch {
+	case asCopy:
+		note, err := session.SaveAsCopy(manager, result.Mine)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Saved as '%s', %s\n", note.Title, problem)
+	case force && result.Outcome == edit.Conflict:
+		note, err := session.KeepMine(manager, result.Mine)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Saved '%s', overwriting the changes made since\n", note.Title)
+	case result.Outcome == edit.Deleted:
+		return fmt.Errorf("%s, apply it with --copy to save it as a new note", problem)
+	default:
+		return fmt.Errorf("%s, see `merlion recover diff %s`, then apply it with --force or --copy", problem, entry.ID)
+	}
+	return session.Close()
+}
+
+func runDiscard(args *parser.Args) int {
+	var entries []edit.Entry
+	var err error
+	switch {
+	case args.Bool("all") && len(args.Positionals) > 0:
+		return args.UsageError("expected the IDs of the edits or --all")
+	case args.Bool("all"):
+		entries, err = edit.Pending()
+	case len(args.Positionals) == 0:
+		return args.UsageError("expected the IDs of the edits or --all")
+	default:
+		entries, err = findEntries(args.Positionals)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	code := 0
+	for _, entry := range entries {
+		if entry.Running() {
+			fmt.Fprintf(os.Stderr, "Error: %s is being edited, skipped\n", entry.ID)
+			code = 1
+			continue
+		}
+		if err := entry.Discard(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", entry.ID, err)
+			code = 1
+			continue
+		}
+		fmt.Printf("Discarded the edit of '%s'\n", entry.Base.Title)
+	}
+	return code
+}
+
+// findEntries resolves all the IDs first, nothing is applied or discarded on a typo
+func findEntries(ids []string) ([]edit.Entry, error) {
+	entries := []edit.Entry{}
+	for _, id := range ids {
+		entry, err := edit.FindEntry(id)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Running() {
+			return nil, fmt.Errorf("%s is being edited by merlion (pid %d)", entry.ID, entry.PID)
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
+
+// loadManager returns a manager on the vault of the edit, with its notes loaded
+func loadManager(vaultName string) (*vault.Manager, error) {
+	credMgr, err := cloud.NewCredentialsManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credentials manager: %w", err)
+	}
+	manager := vault.NewManager(config.Load(), credMgr, false)
+	if err := manager.UseStore(vaultName); err != nil {
+		return nil, err
+	}
+	if _, err := manager.ListNoteMetadata(); err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	return manager, nil
+}
+
+// completeIDs completes the IDs of the interrupted edits
+func completeIDs(args *parser.Args, prefix string) []string {
+	entries, err := edit.Pending()
+	if err != nil {
+		return nil
+	}
+	result := []string{}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.ID, prefix) {
+			result = append(result, entry.ID)
+		}
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+// Package rpc implements `merlion rpc`, the JSON-RPC server used by the editor integrations
+package rpc
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"merlion/cmd/merlion/notes"
+	"merlion/cmd/merlion/parser"
+	"merlion/internal/rpc"
+)
+
+// Cmd serves the JSON-RPC 2.0 API over stdin and stdout
+var Cmd = &parser.Command{
+	Name:        "rpc",
+	Description: "Serve a JSON-RPC 2.0 API over stdin/stdout for editor integrations",
+	Flags: []parser.Flag{
+		{Name: "vault", Value: "<name>", Usage: "Use the vault with this name (default: first vault of the config)", Complete: notes.CompleteVaults},
+		{Name: "watch", Value: "<duration>", Usage: "Interval between the checks of external changes, 0 to disable (default: 2s)"},
+		{Name: "cloud-watch", Value: "<duration>", Usage: "Interval between the checks of a Cloud vault, each one is a request to the API, 0 to disable (default: 1m)"},
+	},
+	Long: `One JSON message per line, the methods and their schema are documented in docs/rpc.md
+
+Example:
+  echo '{"jsonrpc":"2.0","id":1,"method":"complete","params":{"prefix":"meet"}}' | merlion rpc`,
+	Run: run,
+}
+
+func run(args *parser.Args) int {
+	if len(args.Positionals) > 0 {
+		return args.UsageError("unexpected argument %s", args.Positionals[0])
+	}
+	watch := 2 * time.Second
+	if value, ok := args.Lookup("watch"); ok {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return args.UsageError("invalid --watch duration: %s", value)
+		}
+		watch = duration
+	}
+	cloudWatch := rpc.DefaultCloudWatchInterval
+	if value, ok := args.Lookup("cloud-watch"); ok {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return args.UsageError("invalid --cloud-watch duration: %s", value)
+		}
+		cloudWatch = duration
+	}
+
+	manager, err := notes.LoadManager(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return notes.ExitError
+	}
+
+	server := rpc.NewServer(manager)
+	server.WatchInterval = watch
+	server.CloudWatchInterval = cloudWatch
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return notes.ExitError
+	}
+	return notes.ExitOK
+}
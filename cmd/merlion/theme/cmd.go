@@ -0,0 +1,179 @@
+// Package theme implements `merlion theme`, to list the built-in themes and the ones of ~/.config/merlion/themes
+package theme
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"merlion/cmd/merlion/parser"
+	"merlion/internal/styles"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/latentdream/merlion/lib/glamour"
+)
+
+const long = `Themes are loaded from ~/.config/merlion/themes/*.json and *.toml, and selected with ctrl+t.
+A theme file sets the colors of a built-in base theme, and optionally the markdown style:
+  name = "dracula"              # The file name by default
+  base = "gruvbox"              # Built-in theme of the missing colors, neotokyo by default
+  markdownStyle = "dracula"     # Glamour style: dark, light, dracula, tokyo-night, ...
+  codeTheme = "dracula"         # Chroma style of the code blocks
+
+  [colors]                      # background, foreground, primary, secondary, tertiary, border, muted, ...
+  primary = "#bd93f9"
+
+  [markdown.h1]                 # Overrides of the glamour style, with its JSON keys
+  prefix = "# "`
+
+// Cmd lists and previews the themes
+var Cmd = &parser.Command{
+	Name:        "theme",
+	Description: "List and preview the themes",
+	Long:        long,
+	Subcommands: []*parser.Command{
+		{
+			Name:        "list",
+			Description: "List the themes, the current one is marked with *, and report the invalid theme files",
+			Run:         runList,
+		},
+		{
+			Name:        "preview",
+			Usage:       "[<theme>]",
+			Description: "Render a sample note with a theme, the current one by default",
+			Complete:    completeThemes,
+			Run:         runPreview,
+		},
+	},
+}
+
+func runList(args *parser.Args) int {
+	tm, err := styles.NewThemeManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize the theme manager: %v\n", err)
+		return 1
+	}
+	for _, theme := range tm.Themes() {
+		current := " "
+		if theme.Name == tm.Current().Name {
+			current = "*"
+		}
+		source := "built-in"
+		if theme.Path != "" {
+			source = theme.Path
+		}
+		fmt.Printf("%s %-16s %s\n", current, theme.Name, source)
+	}
+	for _, err := range tm.ThemeErrors {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	if len(tm.ThemeErrors) > 0 {
+		return 1
+	}
+	return 0
+}
+
+const sample = `# Heading
+
+Some **bold**, *italic* and ` + "`inline code`" + `, a [link](https://example.com) and a [[Wiki Link]].
+
+## List
+
+- First item
+- [x] Done task
+- [ ] Open task
+
+> A quote
+
+` + "```go" + `
+// Comment
+func hello(name string) string {
+	return fmt.Sprintf("Hello %s", name)
+}
+` + "```" + `
+
+| Name | Value |
+|------|-------|
+| a    | 1     |
+`
+
+func runPreview(args *parser.Args) int {
+	if len(args.Positionals) > 1 {
+		return args.UsageError("expected at most one theme")
+	}
+	tm, err := styles.NewThemeManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize the theme manager: %v\n", err)
+		return 1
+	}
+	// Preview without changing the theme of the config
+	tm.SetSaveOnChange(false)
+	if len(args.Positionals) == 1 {
+		if err := tm.SetTheme(args.Positionals[0]); err != nil {
+			for _, err := range tm.ThemeErrors {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			return args.UsageError("unknown theme %q, expected one of: %s", args.Positionals[0], strings.Join(themeNames(tm), ", "))
+		}
+	}
+
+	theme := tm.Current()
+	swatches := []string{}
+	for _, color := range []struct {
+		name  string
+		color lipgloss.Color
+	}{
+		{"primary", theme.Primary},
+		{"secondary", theme.Secondary},
+		{"tertiary", theme.Tertiary},
+		{"success", theme.Success},
+		{"warning", theme.Warning},
+		{"error", theme.Error},
+		{"muted", theme.MutedColor},
+	} {
+		swatches = append(swatches, lipgloss.NewStyle().Foreground(theme.Background).Background(color.color).Padding(0, 1).Render(color.name))
+	}
+	st := tm.Styles()
+	fmt.Println(st.Title.Render(theme.Name) + " " + strings.Join(swatches, " "))
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStyles(tm.GetRendererStyle()),
+		glamour.WithWordWrap(80),
+	)
+	if err == nil {
+		var rendered string
+		if rendered, err = renderer.Render(sample); err == nil {
+			fmt.Print(rendered)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: rendering the sample: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func themeNames(tm *styles.ThemeManager) []string {
+	names := []string{}
+	for _, theme := range tm.Themes() {
+		names = append(names, theme.Name)
+	}
+	return names
+}
+
+func completeThemes(args *parser.Args, prefix string) []string {
+	if len(args.Positionals) > 0 {
+		return nil
+	}
+	tm, err := styles.NewThemeManager()
+	if err != nil {
+		return nil
+	}
+	result := []string{}
+	for _, name := range themeNames(tm) {
+		if strings.HasPrefix(name, prefix) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+// Package today implements the daily journal commands: `merlion today` and `merlion yesterday`
+package today
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"merlion/cmd/merlion/notes"
+	"merlion/cmd/merlion/parser"
+	"merlion/internal/config"
+	"merlion/internal/vault"
+	"merlion/internal/worklog"
+)
+
+var flags = []parser.Flag{
+	{Name: "append", Value: "<text>", Usage: "Add a timestamped bullet to the work log, without opening an editor"},
+	{Name: "print", Usage: "Print the work log instead of opening an editor"},
+	{Name: "vault", Value: "<name>", Usage: "Use the vault with this name (default: first vault of the config)", Complete: notes.CompleteVaults},
+}
+
+const long = `Configuration (~/.config/merlion/config.json):
+  workLogTitleFormat: Go time layout used as note title (default: 2006-01-02)
+  workLogTemplate: template used to create the work log (see ` + "`merlion new --help`" + `)
+
+Examples:
+  merlion today --append "Deployed the new API"
+  merlion yesterday --print`
+
+// Cmd opens the work log of the current day
+var Cmd = &parser.Command{
+	Name:        "today",
+	Description: "Open today's work log, `--append \"text\"` adds an entry",
+	Flags:       flags,
+	Long:        long,
+	Run: func(args *parser.Args) int {
+		return run(time.Now(), args)
+	},
+}
+
+// YesterdayCmd opens the work log of the previous day, handy for standups
+var YesterdayCmd = &parser.Command{
+	Name:        "yesterday",
+	Description: "Open yesterday's work log, `--print` to display it",
+	Flags:       flags,
+	Long:        long,
+	Run: func(args *parser.Args) int {
+		return run(time.Now().AddDate(0, 0, -1), args)
+	},
+}
+
+func run(day time.Time, args *parser.Args) int {
+	text, isAppend := args.Lookup("append")
+	// Unquoted text: `merlion today --append Deployed the API`
+	text = strings.TrimSpace(strings.Join(append([]string{text}, args.Positionals...), " "))
+	if isAppend && text == "" {
+		return args.UsageError("--append requires a text")
+	}
+	if !isAppend && len(args.Positionals) > 0 {
+		return args.UsageError("unexpected argument %s", args.Positionals[0])
+	}
+
+	manager, err := notes.LoadManager(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return notes.ExitError
+	}
+	cfg := config.Load()
+
+	if args.Bool("print") && !isAppend {
+		return printNote(manager, worklog.Title(cfg, day))
+	}
+
+	note, err := worklog.Open(manager, cfg, day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	switch {
+	case isAppend:
+		if _, err := worklog.Append(manager, note, text, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to append to %s: %v\n", note.Title, err)
+			return 1
+		}
+		fmt.Printf("Added to '%s'\n", note.Title)
+	default:
+		if err := notes.EditInEditor(manager, note); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// printNote displays the work log, without creating it when missing
+func printNote(manager *vault.Manager, title string) int {
+	found := manager.SearchByTitle(title)
+	if found == nil {
+		fmt.Fprintf(os.Stderr, "No work log for %s\n", title)
+		return 1
+	}
+	note, err := manager.GetFullNote(found.NoteID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to get %s: %v\n", title, err)
+		return 1
+	}
+	if note.Content != nil {
+		fmt.Print(*note.Content)
+	}
+	return 0
+}
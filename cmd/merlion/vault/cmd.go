@@ -6,13 +6,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"merlion/cmd/merlion/parser"
 	"merlion/internal/config"
 	"merlion/internal/styles"
 	"merlion/internal/ui/login"
 	"merlion/internal/ui/vault"
-	"merlion/internal/utils"
 	"merlion/internal/vault/cloud"
 	"merlion/internal/vault/files"
 	"merlion/internal/vault/sqlite"
@@ -21,48 +21,49 @@ import (
 	"github.com/charmbracelet/log"
 )
 
-func Cmd(args ...string) int {
-	if utils.Contains(args, "--help") || utils.Contains(args, "-h") {
-		printVaultHelp(true)
-	}
+var providers = []string{"sqlite", "files", "cloud"}
+
+// Cmd adds a vault, the provider is chosen in a TUI when not provided
+var Cmd = &parser.Command{
+	Name:        "vault",
+	Usage:       "[<provider>]",
+	Description: "Create a new note vault storage, switch between them using `)`",
+	Long: `Providers:
+  - sqlite: create a new SQLite database
+  - files <obsidian-vault-path>: create a new local Obsidian vault
+  - cloud: create a new cloud storage provider
+
+To remove a vault
+   - sqlite & files -> edit ~/.merlion/config.json
+   - cloud -> merlion logout`,
+	Complete: func(args *parser.Args, prefix string) []string {
+		if len(args.Positionals) > 0 {
+			return nil
+		}
+		return providers
+	},
+	Run: run,
+}
 
-	if len(args) == 0 {
+func run(args *parser.Args) int {
+	if len(args.Positionals) == 0 {
 		return ChooseVault()
 	}
 
-	arg, args := parser.GetArg(args, printVaultHelp)
-	switch arg {
+	provider, rest := args.Positionals[0], args.Positionals[1:]
+	switch provider {
 	case "sqlite":
-		return newSQLiteVault(args...)
+		return newSQLiteVault()
 	case "files":
-		return newFilesVault(args...)
+		if len(rest) != 1 {
+			return args.UsageError("expected the path of the Obsidian vault")
+		}
+		return newFilesVault(rest[0])
 	case "cloud":
-		return newCloudVault(args...)
+		return newCloudVault()
 	default:
-		printVaultHelp(true)
+		return args.UsageError("unknown provider %s, expected one of: %s", provider, strings.Join(providers, ", "))
 	}
-	return 0
-}
-
-func printVaultHelp(invalidArgs bool) {
-	if invalidArgs {
-		fmt.Println("Invalid arguments")
-	}
-
-	fmt.Println("Usage: merlion vault [<provider>]")
-	fmt.Println("Provider: sqlite, files [<obsidian-vault-path>], cloud")
-	fmt.Println("  - sqlite: create a new SQLite database")
-	fmt.Println("  - files <obsidian-vault-path>: create a new local Obsidian vault")
-	fmt.Println("  - cloud: create a new cloud storage provider")
-	fmt.Println("")
-	fmt.Println("To remove a vault")
-	fmt.Println("   - sqlite & files -> edit ~/.merlion/config.json")
-	fmt.Println("   - cloud -> merlion logout")
-
-	if invalidArgs {
-		os.Exit(1)
-	}
-	os.Exit(0)
 }
 
 func ChooseVault() int {
@@ -91,7 +92,7 @@ func ChooseVault() int {
 // TODO: move the vault creation logic in the related package
 // Will cause circular dependency => Let's deal with it later
 
-func newCloudVault(args ...string) int {
+func newCloudVault() int {
 	tm, err := styles.NewThemeManager()
 	if err != nil {
 		log.Fatalf("Failed to initialize theme manager: %v", err)
@@ -123,9 +124,7 @@ func newCloudVault(args ...string) int {
 	return 0
 }
 
-func newFilesVault(args ...string) int {
-	root, _ := parser.GetArg(args, printVaultHelp)
-
+func newFilesVault(root string) int {
 	absPath, err := filepath.Abs(root)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: invalid path: %v\n", err)
@@ -147,7 +146,7 @@ func newFilesVault(args ...string) int {
 	return 0
 }
 
-func newSQLiteVault(args ...string) int {
+func newSQLiteVault() int {
 	// TODO: could be more generic accepting a path for the db
 	cfg := config.Load()
 	cfg.Vaults = append(cfg.Vaults, config.Vault{
@@ -1,7 +1,11 @@
 // Package version contains the version information for the application
 package version
 
-import "fmt"
+import (
+	"fmt"
+
+	"merlion/cmd/merlion/parser"
+)
 
 var (
 	Version = "dev"
@@ -15,7 +19,13 @@ func GetFullVersionInfo() map[string]string {
 	}
 }
 
-func Cmd(args ...string) int {
+var Cmd = &parser.Command{
+	Name:        "version",
+	Description: "Show version information",
+	Run:         run,
+}
+
+func run(args *parser.Args) int {
 	version := fmt.Sprintf("version: %s\ncommit: %s", Version, Commit)
 	fmt.Println(version)
 	return 0
@@ -0,0 +1,129 @@
+// Package capture implements the quick capture of text into notes
+// The text is either saved as a new note tagged with the inbox tag, or added to an existing note
+package capture
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"merlion/internal/config"
+	"merlion/internal/model"
+	"merlion/internal/vault"
+)
+
+const (
+	DefaultInbox = "inbox"
+	titleFormat  = "Capture 2006-01-02 15.04.05" // Without the ":" the Files vaults can't use in a name
+	headerFormat = "2006-01-02 15:04"
+)
+
+type Options struct {
+	Timestamp bool   // Add a `### <date time>` header before the text
+	IsCode    bool   // Wrap the text in a fenced code block
+	Code      string // Language hint of the code block
+	Prepend   bool   // Insert at the top of the note instead of the bottom
+	Tags      []string
+}
+
+// Inbox returns the tag of the captured notes
+func Inbox(cfg *config.UserConfig) string {
+	if cfg == nil || strings.TrimSpace(cfg.CaptureInbox) == "" {
+		return DefaultInbox
+	}
+	return strings.TrimSpace(cfg.CaptureInbox)
+}
+
+// Format returns the markdown block of the captured text
+func Format(text string, opts Options, now time.Time) string {
+	text = strings.TrimRight(text, "\n")
+	if opts.IsCode {
+		fence := "```"
+		// The text itself may contain fences
+		for strings.Contains(text, fence) {
+			fence += "`"
+		}
+		text = fence + opts.Code + "\n" + text + "\n" + fence
+	}
+	if opts.Timestamp {
+		text = "### " + now.Format(headerFormat) + "\n" + text
+	}
+	return text + "\n"
+}
+
+// Insert adds the block to the content, separated by an empty line
+// When prepending, the block is placed after the leading `# title` heading if any
+func Insert(content string, block string, prepend bool) string {
+	if strings.TrimSpace(content) == "" {
+		return block
+	}
+	if !prepend {
+		return strings.TrimRight(content, "\n") + "\n\n" + block
+	}
+
+	heading := ""
+	if strings.HasPrefix(content, "# ") {
+		heading, content, _ = strings.Cut(content, "\n")
+		heading += "\n\n"
+		content = strings.TrimLeft(content, "\n")
+	}
+	return heading + block + "\n" + content
+}
+
+// New creates a note with the captured text, tagged with the inbox tag
+func New(m *vault.Manager, cfg *config.UserConfig, title string, text string, opts Options, now time.Time) (*model.Note, error) {
+	if strings.TrimSpace(title) == "" {
+		title = now.Format(titleFormat)
+	}
+	content := Format(text, opts, now)
+	note, err := m.CreateNote(model.CreateNoteRequest{
+		Title:   title,
+		Content: &content,
+		Tags:    mergeTags([]string{Inbox(cfg)}, opts.Tags),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", title, err)
+	}
+	return note, nil
+}
+
+// Add appends, or prepends, the captured text to an existing note
+// The note must be loaded with its content
+func Add(m *vault.Manager, note *model.Note, text string, opts Options, now time.Time) (*model.Note, error) {
+	content := ""
+	if note.Content != nil {
+		content = *note.Content
+	}
+	content = Insert(content, Format(text, opts, now), opts.Prepend)
+
+	req := note.ToCreateRequest()
+	req.Content = &content
+	req.Tags = mergeTags(note.Tags, opts.Tags)
+	updated, err := m.UpdateNote(note.NoteID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", note.Title, err)
+	}
+	return updated, nil
+}
+
+// mergeTags appends the new tags, skipping the empty ones and the ones already present
+func mergeTags(tags []string, newTags []string) []string {
+	result := append([]string{}, tags...)
+	for _, tag := range newTags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		exists := false
+		for _, existing := range result {
+			if strings.EqualFold(existing, tag) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
+	"merlion/internal/sorting"
 	"merlion/internal/vault/cloud"
 	"merlion/internal/vault/files"
 	"merlion/internal/vault/sqlite"
@@ -21,15 +23,54 @@ type Vault struct {
 	Name     string `json:"name"`
 }
 
+// Tab is a user defined tab of the note list, showing the notes matching a query
+type Tab struct {
+	Name  string         `json:"name"`
+	Query string         `json:"query"`
+	Sort  *sorting.Order `json:"sort,omitempty"`
+}
+
 type UserConfig struct {
 	Theme          string  `json:"theme"`
 	InfoHidden     bool    `json:"infoHidden"`
 	InfoBottom     bool    `json:"infoBottom"`
 	CompactView    bool    `json:"compactView"`
 	DefaultToCloud bool    `json:"defaultToCloud"`
+	TagsSort       string  `json:"tagsSort"`
 	Vaults         []Vault `json:"vaults"`
+
+	// Daily journal, the title is the day formatted with a Go time layout
+	WorkLogTitleFormat string `json:"workLogTitleFormat,omitempty"`
+	WorkLogTemplate    string `json:"workLogTemplate,omitempty"`
+
+	// Sort of the note list, by tab name
+	NotesSort map[string]sorting.Order `json:"notesSort,omitempty"`
+
+	// Tabs added after the built-in ones
+	Tabs []Tab `json:"tabs,omitempty"`
+
+	// Command palette items, most recently used first
+	RecentCommands []string `json:"recentCommands,omitempty"`
+
+	// Tag of the notes created by `merlion capture`
+	CaptureInbox string `json:"captureInbox,omitempty"`
+
+	// Key bindings: a preset (default, vim or emacs) then the keys by KeyMap field, e.g. "Quit": ["ctrl+q"]
+	KeyPreset string              `json:"keyPreset,omitempty"`
+	Keys      map[string][]string `json:"keys,omitempty"`
+
+	// Editor of the notes: internal, external ($EDITOR), or empty for $EDITOR when it is set
+	Editor string `json:"editor,omitempty"`
+	// Title, tags, flags and custom properties of the note in a YAML header above the content in $EDITOR
+	EditorFrontMatter bool `json:"editorFrontMatter,omitempty"`
 }
 
+// Editors of the notes
+const (
+	EditorInternal = "internal"
+	EditorExternal = "external"
+)
+
 var (
 	instance UserConfig
 	once     sync.Once
@@ -49,6 +90,11 @@ func getConfigDir() (string, error) {
 	return merlionDir, nil
 }
 
+// Dir returns the directory holding the merlion configuration, ~/.config/merlion
+func Dir() (string, error) {
+	return getConfigDir()
+}
+
 func (c *UserConfig) validate() error {
 	validProviders := map[string]bool{
 		cloud.Type:  true,
@@ -61,6 +107,42 @@ func (c *UserConfig) validate() error {
 			return fmt.Errorf("provider must be one of: cloud, sqlite, files")
 		}
 	}
+	if c.Editor != "" && c.Editor != EditorInternal && c.Editor != EditorExternal {
+		return fmt.Errorf("editor must be one of: %s, %s", EditorInternal, EditorExternal)
+	}
+	return nil
+}
+
+// NotesSortFor returns the sort of the tab, or its default
+func (c *UserConfig) NotesSortFor(tab string) sorting.Order {
+	if userTab := c.FindTab(tab); userTab != nil && userTab.Sort != nil {
+		return *userTab.Sort
+	}
+	if order, ok := c.NotesSort[tab]; ok {
+		return order
+	}
+	return sorting.Default(tab)
+}
+
+// InternalEditor tells if the notes are edited in the built-in editor rather than in $EDITOR
+func (c *UserConfig) InternalEditor() bool {
+	switch c.Editor {
+	case EditorInternal:
+		return true
+	case EditorExternal:
+		return false
+	default:
+		return os.Getenv("EDITOR") == ""
+	}
+}
+
+// FindTab returns the user defined tab with this name, case insensitive
+func (c *UserConfig) FindTab(name string) *Tab {
+	for i := range c.Tabs {
+		if strings.EqualFold(c.Tabs[i].Name, name) {
+			return &c.Tabs[i]
+		}
+	}
 	return nil
 }
 
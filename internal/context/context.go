@@ -33,6 +33,13 @@ func WithWorkLogOpen() ContextOption {
 	}
 }
 
+// WithFirstTab Allows to open the app on any tab, built-in or user defined, by name
+func WithFirstTab(name string) ContextOption {
+	return func(c *Context) {
+		c.FirstTab = name
+	}
+}
+
 // WithCompactViewStart if the user only has access to the compact view
 func WithCompactViewStart(isStartingInCompactView bool) ContextOption {
 	return func(c *Context) {
@@ -28,6 +28,32 @@ type KeyMap struct {
 	Create             key.Binding
 	Delete             key.Binding
 	ToggleStore        key.Binding
+	SortGroups         key.Binding
+	OpenToday          key.Binding
+	OpenYesterday      key.Binding
+	PrevDay            key.Binding
+	NextDay            key.Binding
+	Outline            key.Binding
+	PrevHeading        key.Binding
+	NextHeading        key.Binding
+	FindNext           key.Binding
+	FindPrev           key.Binding
+	ToggleFindCase     key.Binding
+	ToggleFindRegex    key.Binding
+	CommandPalette     key.Binding
+	NewTab             key.Binding
+	EditTab            key.Binding
+	RemoveTab          key.Binding
+	MoveTabLeft        key.Binding
+	MoveTabRight       key.Binding
+	ToggleSelect       key.Binding
+	SelectRange        key.Binding
+	SelectAll          key.Binding
+	Save               key.Binding
+	TogglePreview      key.Binding
+	Undo               key.Binding
+	Redo               key.Binding
+	Help               key.Binding
 }
 
 var Keys = KeyMap{
@@ -115,6 +141,110 @@ var Keys = KeyMap{
 		key.WithKeys("(", ")"),
 		key.WithHelp(")", "Toggle Store"),
 	),
+	SortGroups: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "Sort notes, or tags by name/count"),
+	),
+	OpenToday: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "Open today's work log"),
+	),
+	OpenYesterday: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "Open yesterday's work log"),
+	),
+	PrevDay: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "Previous work log"),
+	),
+	NextDay: key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "Next work log"),
+	),
+	Outline: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "Toggle the outline of the note"),
+	),
+	PrevHeading: key.NewBinding(
+		key.WithKeys("{"),
+		key.WithHelp("{", "Previous heading of the note"),
+	),
+	NextHeading: key.NewBinding(
+		key.WithKeys("}"),
+		key.WithHelp("}", "Next heading of the note"),
+	),
+	FindNext: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "Next match of the find in the note"),
+	),
+	FindPrev: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "Previous match of the find in the note"),
+	),
+	ToggleFindCase: key.NewBinding(
+		key.WithKeys("alt+c"),
+		key.WithHelp("alt+c", "Toggle case sensitivity, in the find bar"),
+	),
+	ToggleFindRegex: key.NewBinding(
+		key.WithKeys("alt+r"),
+		key.WithHelp("alt+r", "Toggle regular expressions, in the find bar"),
+	),
+	CommandPalette: key.NewBinding(
+		key.WithKeys("ctrl+k"),
+		key.WithHelp("ctrl+k", "Command palette"),
+	),
+	NewTab: key.NewBinding(
+		key.WithKeys("+"),
+		key.WithHelp("+", "New tab from the filter"),
+	),
+	EditTab: key.NewBinding(
+		key.WithKeys("="),
+		key.WithHelp("=", "Edit the current tab"),
+	),
+	RemoveTab: key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "Remove the current tab"),
+	),
+	MoveTabLeft: key.NewBinding(
+		key.WithKeys("<"),
+		key.WithHelp("<", "Move the current tab left"),
+	),
+	MoveTabRight: key.NewBinding(
+		key.WithKeys(">"),
+		key.WithHelp(">", "Move the current tab right"),
+	),
+	ToggleSelect: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "Select the note, or the notes of the tag"),
+	),
+	SelectRange: key.NewBinding(
+		key.WithKeys("V"),
+		key.WithHelp("V", "Select the notes up to the last selected one"),
+	),
+	SelectAll: key.NewBinding(
+		key.WithKeys("ctrl+a"),
+		key.WithHelp("ctrl+a", "Select all the filtered notes, or clear the selection"),
+	),
+	Save: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("ctrl+s", "Save the note, in the built-in editor"),
+	),
+	TogglePreview: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "Toggle the preview, in the built-in editor"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("ctrl+z"),
+		key.WithHelp("ctrl+z", "Undo, in the built-in editor"),
+	),
+	Redo: key.NewBinding(
+		key.WithKeys("ctrl+y"),
+		key.WithHelp("ctrl+y", "Redo, in the built-in editor"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "Show the key bindings"),
+	),
 }
 
 func (k KeyMap) ToSlice() []key.Binding {
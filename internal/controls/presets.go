@@ -0,0 +1,188 @@
+package controls
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// Presets of key bindings, applied before the overrides of the user config
+const (
+	PresetDefault = "default"
+	PresetVim     = "vim"
+	PresetEmacs   = "emacs"
+)
+
+// Keys of the presets by KeyMap field, the other bindings keep their default keys
+var presets = map[string]map[string][]string{
+	PresetDefault: {},
+	PresetVim: {
+		"PageUp":            {"ctrl+b", "ctrl+u", "pgup"},
+		"PageDown":          {"ctrl+f", "ctrl+d", "pgdown"},
+		"ToggleCompactView": {"z"},
+		"Create":            {"o", "c"},
+		"Delete":            {"x", "delete"},
+		"CommandPalette":    {":", "ctrl+k"},
+	},
+	PresetEmacs: {
+		"Up":                 {"up", "ctrl+p"},
+		"Down":               {"down", "ctrl+n"},
+		"Left":               {"left", "ctrl+b"},
+		"Right":              {"right", "ctrl+f"},
+		"PageUp":             {"pgup", "alt+v"},
+		"PageDown":           {"pgdown", "ctrl+v"},
+		"Back":               {"esc", "ctrl+g"},
+		"ClearFilter":        {"esc", "ctrl+g"},
+		"Quit":               {"ctrl+q", "ctrl+c"},
+		"ToggleInfoPosition": {"alt+p"},
+		"ToggleCompactView":  {"alt+f"},
+		"CommandPalette":     {"alt+x", "ctrl+k"},
+		"Undo":               {"ctrl+_", "ctrl+z"},
+	},
+}
+
+// Bindings which may share keys, they are never active at the same time:
+// esc clears the filter of the note list and goes back everywhere else
+var shared = [][]string{{"Back", "ClearFilter"}}
+
+// Keys handled by the components and not by the KeyMap
+var reserved = map[string]string{
+	"/": "the list filter and the find in the note",
+}
+
+var defaultKeys = Keys
+
+// Preset is the name of the preset loaded by Load
+var Preset = PresetDefault
+
+// PresetNames returns the names of the presets, sorted
+func PresetNames() []string {
+	names := []string{}
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FieldNames returns the names of the KeyMap fields, accepted as keys of the overrides
+func FieldNames() []string {
+	names := []string{}
+	t := reflect.TypeOf(KeyMap{})
+	for i := 0; i < t.NumField(); i++ {
+		names = append(names, t.Field(i).Name)
+	}
+	return names
+}
+
+// Load replaces Keys with the preset and the overrides of the user config, keyed by KeyMap field name.
+// An empty list of keys disables the binding. Load must be called before creating the views
+func Load(preset string, overrides map[string][]string) error {
+	if preset == "" {
+		preset = PresetDefault
+	}
+	presetKeys, ok := presets[strings.ToLower(preset)]
+	if !ok {
+		return fmt.Errorf("unknown key preset %q, expected one of: %s", preset, strings.Join(PresetNames(), ", "))
+	}
+
+	keys := defaultKeys
+	for name, bindingKeys := range presetKeys {
+		keys.rebind(name, bindingKeys)
+	}
+	unknown := []string{}
+	for name, bindingKeys := range overrides {
+		if !keys.rebind(name, bindingKeys) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown key bindings %s, expected one of: %s", strings.Join(unknown, ", "), strings.Join(FieldNames(), ", "))
+	}
+
+	Keys = keys
+	Preset = strings.ToLower(preset)
+	return nil
+}
+
+// rebind replaces the keys of the binding, the name is case insensitive
+func (k *KeyMap) rebind(name string, keys []string) bool {
+	field := reflect.ValueOf(k).Elem().FieldByNameFunc(func(field string) bool {
+		return strings.EqualFold(field, name)
+	})
+	if !field.IsValid() {
+		return false
+	}
+	binding := field.Interface().(key.Binding)
+	if len(keys) == 0 {
+		field.Set(reflect.ValueOf(key.NewBinding(key.WithDisabled())))
+		return true
+	}
+	help := strings.Join(keys, "/")
+	if help == " " {
+		help = "space"
+	}
+	field.Set(reflect.ValueOf(key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(help, binding.Help().Desc),
+	)))
+	return true
+}
+
+// Conflict is a key bound to several actions
+type Conflict struct {
+	Key      string
+	Bindings []string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%q is bound to %s", c.Key, strings.Join(c.Bindings, " and "))
+}
+
+// Conflicts returns the keys bound to several bindings, or to a key handled by a component
+func (k KeyMap) Conflicts() []Conflict {
+	names := FieldNames()
+	bindings := k.ToSlice()
+	byKey := map[string][]string{}
+	order := []string{}
+	for i, binding := range bindings {
+		if !binding.Enabled() {
+			continue
+		}
+		for _, bindingKey := range binding.Keys() {
+			if _, ok := byKey[bindingKey]; !ok {
+				order = append(order, bindingKey)
+			}
+			if !slices.Contains(byKey[bindingKey], names[i]) {
+				byKey[bindingKey] = append(byKey[bindingKey], names[i])
+			}
+		}
+	}
+
+	conflicts := []Conflict{}
+	for _, bindingKey := range order {
+		fields := byKey[bindingKey]
+		if component, ok := reserved[bindingKey]; ok {
+			conflicts = append(conflicts, Conflict{Key: bindingKey, Bindings: append(fields, component)})
+			continue
+		}
+		if len(fields) > 1 && !isShared(fields) {
+			conflicts = append(conflicts, Conflict{Key: bindingKey, Bindings: fields})
+		}
+	}
+	return conflicts
+}
+
+func isShared(fields []string) bool {
+	for _, group := range shared {
+		if !slices.ContainsFunc(fields, func(field string) bool { return !slices.Contains(group, field) }) {
+			return true
+		}
+	}
+	return false
+}
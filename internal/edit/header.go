@@ -0,0 +1,236 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"merlion/internal/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrHeader is returned when the header edited in $EDITOR can't be read back
+var ErrHeader = errors.New("invalid header")
+
+const (
+	headerDelimiter = "---"
+	// Prefix of the comment explaining why the header was rejected, removed from the next attempt
+	headerErrorPrefix = "# Error: "
+	// Prefix of the custom properties named like a field of the header, e.g. a `title` property is
+	// written `property.title`. The properties already starting with it get it twice
+	propertyPrefix = "property."
+)
+
+// headerFields are the keys of the header which aren't custom properties, in their order
+var headerFields = []string{"title", "tags", "favorite", "worklog"}
+
+// Header is the metadata of the note, edited as a YAML header above the content, e.g.
+//
+//	---
+//	title: Project plan
+//	tags: [work]
+//	favorite: false
+//	worklog: false
+//	status: draft
+//	---
+type Header struct {
+	Title      string
+	Tags       []string
+	Favorite   bool
+	WorkLog    bool
+	Properties map[string]any // Custom properties, Obsidian vaults only
+}
+
+// MarshalYAML writes the fields of the header first, then the custom properties
+func (h Header) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	add := func(key string, value any) error {
+		var valueNode yaml.Node
+		if err := valueNode.Encode(value); err != nil {
+			return err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &valueNode)
+		return nil
+	}
+	for i, value := range []any{h.Title, h.Tags, h.Favorite, h.WorkLog} {
+		if err := add(headerFields[i], value); err != nil {
+			return nil, err
+		}
+	}
+	node.Content[3].Style = yaml.FlowStyle
+
+	for _, key := range slices.Sorted(maps.Keys(h.Properties)) {
+		name := key
+		if slices.Contains(headerFields, key) || strings.HasPrefix(key, propertyPrefix) {
+			name = propertyPrefix + key
+		}
+		if err := add(name, h.Properties[key]); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// UnmarshalYAML reads the fields of the header, the other keys are the custom properties
+func (h *Header) UnmarshalYAML(value *yaml.Node) error {
+	var fields struct {
+		Title    string   `yaml:"title"`
+		Tags     []string `yaml:"tags"`
+		Favorite bool     `yaml:"favorite"`
+		WorkLog  bool     `yaml:"worklog"`
+	}
+	if err := value.Decode(&fields); err != nil {
+		return err
+	}
+	var all map[string]any
+	if err := value.Decode(&all); err != nil {
+		return err
+	}
+	*h = Header{Title: fields.Title, Tags: fields.Tags, Favorite: fields.Favorite, WorkLog: fields.WorkLog}
+	for key, property := range all {
+		if slices.Contains(headerFields, key) {
+			continue
+		}
+		if h.Properties == nil {
+			h.Properties = map[string]any{}
+		}
+		h.Properties[strings.TrimPrefix(key, propertyPrefix)] = property
+	}
+	return nil
+}
+
+// HeaderOf returns the metadata of the note
+func HeaderOf(note model.Note) Header {
+	tags := note.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return Header{
+		Title:      note.Title,
+		Tags:       tags,
+		Favorite:   note.IsFavorite,
+		WorkLog:    note.IsWorkLog,
+		Properties: note.Properties,
+	}
+}
+
+// WithHeader returns the content below the header
+func WithHeader(header Header, content string) string {
+	data, err := yaml.Marshal(header)
+	if err != nil {
+		// Only custom properties of an unexpected type could fail, they are left out
+		header.Properties = nil
+		data, _ = yaml.Marshal(header)
+	}
+	return headerDelimiter + "\n" + string(data) + headerDelimiter + "\n" + content
+}
+
+// SplitHeader reads the header back, and returns the content below it
+func SplitHeader(text string) (Header, string, error) {
+	lines := strings.SplitAfter(text, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != headerDelimiter {
+		return Header{}, "", fmt.Errorf("%w: the note must start with the header between '---' lines", ErrHeader)
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == headerDelimiter {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return Header{}, "", fmt.Errorf("%w: the closing '---' line is missing", ErrHeader)
+	}
+
+	var header Header
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "")), &header); err != nil {
+		return Header{}, "", fmt.Errorf("%w: %w", ErrHeader, err)
+	}
+	header.Title = strings.TrimSpace(header.Title)
+	if header.Title == "" {
+		return Header{}, "", fmt.Errorf("%w: the title is empty", ErrHeader)
+	}
+	if header.Tags == nil {
+		header.Tags = []string{}
+	}
+	return header, strings.Join(lines[end+1:], ""), nil
+}
+
+// AnnotateHeader explains the error in a comment at the top of the header, so it can be fixed in $EDITOR.
+// The comment of the previous attempt is replaced, the lines of the content are left as they are
+func AnnotateHeader(text string, err error) string {
+	lines := strings.SplitAfter(text, "\n")
+	// Without a header, the comment of the previous attempt is the first line
+	if len(lines) > 0 && strings.HasPrefix(lines[0], headerErrorPrefix) {
+		lines = lines[1:]
+	}
+	comment := headerErrorPrefix + strings.ReplaceAll(err.Error(), "\n", " ") +
+		" (fix it and save, or quit without saving to discard the edit)\n"
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != headerDelimiter {
+		return comment + strings.Join(lines, "")
+	}
+	rest := lines[1:]
+	if len(rest) > 0 && strings.HasPrefix(rest[0], headerErrorPrefix) {
+		rest = rest[1:]
+	}
+	return lines[0] + comment + strings.Join(rest, "")
+}
+
+// apply sets the metadata of the request
+func (h Header) apply(req *model.CreateNoteRequest) {
+	req.Title = h.Title
+	req.Tags = h.Tags
+	req.IsFavorite = &h.Favorite
+	req.IsWorkLog = &h.WorkLog
+	req.Properties = h.Properties
+	if req.Properties == nil {
+		req.Properties = map[string]any{}
+	}
+}
+
+func (h Header) equal(other Header) bool {
+	return h.Title == other.Title &&
+		slices.Equal(h.Tags, other.Tags) &&
+		h.Favorite == other.Favorite &&
+		h.WorkLog == other.WorkLog &&
+		sameProperties(h.Properties, other.Properties)
+}
+
+// sameProperties tells if the custom properties are the same, no property and an empty map are
+func sameProperties(a map[string]any, b map[string]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// headerEdit is the header changed by the user, from the header of the base
+type headerEdit struct {
+	from Header
+	to   Header
+}
+
+// over returns the header saved meanwhile, with the fields changed by the user
+func (e headerEdit) over(current Header) Header {
+	merged := current
+	if e.to.Title != e.from.Title {
+		merged.Title = e.to.Title
+	}
+	if !slices.Equal(e.to.Tags, e.from.Tags) {
+		merged.Tags = slices.Clone(e.to.Tags)
+	}
+	if e.to.Favorite != e.from.Favorite {
+		merged.Favorite = e.to.Favorite
+	}
+	if e.to.WorkLog != e.from.WorkLog {
+		merged.WorkLog = e.to.WorkLog
+	}
+	if !sameProperties(e.to.Properties, e.from.Properties) {
+		merged.Properties = maps.Clone(e.to.Properties)
+	}
+	return merged
+}
@@ -0,0 +1,90 @@
+package edit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHeaderRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		header Header
+		text   string
+	}{
+		{
+			name:   "without properties",
+			header: Header{Title: "Plan", Tags: []string{"work", "infra"}, Favorite: true},
+			text:   "---\ntitle: Plan\ntags: [work, infra]\nfavorite: true\nworklog: false\n---\nBody\n",
+		},
+		{
+			name: "properties after the fields",
+			header: Header{Title: "Plan", Tags: []string{}, Properties: map[string]any{
+				"status": "draft", "priority": 2,
+			}},
+			text: "---\ntitle: Plan\ntags: []\nfavorite: false\nworklog: false\npriority: 2\nstatus: draft\n---\nBody\n",
+		},
+		{
+			name: "properties named like the fields",
+			header: Header{Title: "Plan", Tags: []string{"work"}, Properties: map[string]any{
+				"title": "Shown title", "tags": []any{"other"}, "property.title": "x",
+			}},
+			text: "---\ntitle: Plan\ntags: [work]\nfavorite: false\nworklog: false\n" +
+				"property.property.title: x\nproperty.tags:\n    - other\nproperty.title: Shown title\n---\nBody\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := WithHeader(tt.header, "Body\n")
+			if text != tt.text {
+				t.Errorf("WithHeader() = %q, expected %q", text, tt.text)
+			}
+			header, content, err := SplitHeader(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if content != "Body\n" {
+				t.Errorf("content = %q", content)
+			}
+			if !header.equal(tt.header) {
+				t.Errorf("SplitHeader() = %+v, expected %+v", header, tt.header)
+			}
+		})
+	}
+}
+
+func TestSplitHeader(t *testing.T) {
+	header, content, err := SplitHeader("---\ntitle: ' Plan '\nstatus: done\n---\n# Body\n---\nmore\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Header{Title: "Plan", Tags: []string{}, Properties: map[string]any{"status": "done"}}
+	if !reflect.DeepEqual(header, expected) {
+		t.Errorf("SplitHeader() = %+v, expected %+v", header, expected)
+	}
+	if content != "# Body\n---\nmore\n" {
+		t.Errorf("content = %q", content)
+	}
+
+	for _, text := range []string{"no header", "---\ntitle: Plan\n", "---\ntags: [a]\n---\n", "---\ntitle: [\n---\n"} {
+		if _, _, err := SplitHeader(text); err == nil {
+			t.Errorf("SplitHeader(%q) expected an error", text)
+		}
+	}
+}
+
+func TestAnnotateHeader(t *testing.T) {
+	text := "---\ntitle: Plan\n---\n# Error: a heading of the body\n"
+	once := AnnotateHeader(text, errTest("first"))
+	twice := AnnotateHeader(once, errTest("second"))
+	if strings.Contains(twice, "first") || !strings.Contains(twice, "# Error: second") {
+		t.Errorf("the comment of the previous attempt is not replaced: %q", twice)
+	}
+	if !strings.HasSuffix(twice, "---\n# Error: a heading of the body\n") {
+		t.Errorf("the body is changed: %q", twice)
+	}
+}
+
+type errTest string
+
+func (e errTest) Error() string { return string(e) }
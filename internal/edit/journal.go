@@ -0,0 +1,297 @@
+package edit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"merlion/internal/model"
+	"merlion/internal/vault"
+
+	"github.com/google/uuid"
+	gap "github.com/muesli/go-app-paths"
+)
+
+// Journal ---
+// The edit sessions are recorded in the data directory, e.g. ~/.local/share/merlion/sessions,
+// until they are saved or discarded: an edit interrupted by a crash is offered again on the next
+// launch, and by `merlion recover`
+
+const (
+	entryExtension = ".json"
+	draftExtension = ".md"
+)
+
+// Entry is an edit session recorded in the journal, the text being edited is its draft
+type Entry struct {
+	ID        string     `json:"id"`
+	Vault     string     `json:"vault"`
+	NoteID    string     `json:"noteId"`
+	Base      model.Note `json:"base"`   // The version of the note before the edit, with its content
+	Header    bool       `json:"header"` // The draft starts with the YAML header of the metadata
+	PID       int        `json:"pid"`    // The merlion editing the note
+	StartedAt time.Time  `json:"startedAt"`
+}
+
+// JournalDir returns the directory of the recorded edit sessions
+func JournalDir() (string, error) {
+	return gap.NewScope(gap.User, "merlion").DataPath("sessions")
+}
+
+// Pending returns the recorded edit sessions, the oldest first
+func Pending() ([]Entry, error) {
+	dir, err := JournalDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the edit sessions: %w", err)
+	}
+
+	entries := []Entry{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != entryExtension {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the edit session %s: %w", file.Name(), err)
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("invalid edit session %s: %w", file.Name(), err)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].StartedAt.Before(entries[j].StartedAt) })
+	return entries, nil
+}
+
+// Interrupted returns the recorded edit sessions of the vault no merlion is running anymore
+func Interrupted(vaultName string) ([]Entry, error) {
+	entries, err := Pending()
+	if err != nil {
+		return nil, err
+	}
+	interrupted := []Entry{}
+	for _, entry := range entries {
+		if entry.Vault == vaultName && !entry.Running() {
+			interrupted = append(interrupted, entry)
+		}
+	}
+	return interrupted, nil
+}
+
+// FindEntry returns the recorded edit session with this ID, or its unique prefix
+func FindEntry(id string) (*Entry, error) {
+	entries, err := Pending()
+	if err != nil {
+		return nil, err
+	}
+	var found *Entry
+	for i, entry := range entries {
+		if entry.ID == id {
+			return &entries[i], nil
+		}
+		if strings.HasPrefix(entry.ID, id) {
+			if found != nil {
+				return nil, fmt.Errorf("several edit sessions start with %s", id)
+			}
+			found = &entries[i]
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no edit session %s", id)
+	}
+	return found, nil
+}
+
+// Running tells if the merlion of the session is still editing the note
+func (e Entry) Running() bool {
+	if e.PID == os.Getpid() {
+		return true
+	}
+	process, err := os.FindProcess(e.PID)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// DraftPath returns the file of the text being edited
+func (e Entry) DraftPath() (string, error) {
+	dir, err := JournalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, e.ID+draftExtension), nil
+}
+
+// Draft returns the text being edited
+func (e Entry) Draft() (string, error) {
+	path, err := e.DraftPath()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the draft: %w", err)
+	}
+	return string(data), nil
+}
+
+// Discard removes the session and its draft from the journal
+func (e Entry) Discard() error {
+	dir, err := JournalDir()
+	if err != nil {
+		return err
+	}
+	errs := []error{}
+	for _, ext := range []string{draftExtension, entryExtension} {
+		if err := os.Remove(filepath.Join(dir, e.ID+ext)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e Entry) save() error {
+	dir, err := JournalDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create the sessions directory: %w", err)
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Written then renamed, a crash never leaves a truncated session
+	path := filepath.Join(dir, e.ID+entryExtension)
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to record the edit session: %w", err)
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Record starts the journal of the session, the text is its first draft
+func (s *Session) Record(vaultName string, header bool, text string) error {
+	now := time.Now()
+	entry := Entry{
+		ID:        now.Format("20060102-150405") + "-" + uuid.NewString()[:4],
+		Vault:     vaultName,
+		NoteID:    s.NoteID,
+		Base:      s.Base,
+		Header:    header,
+		PID:       os.Getpid(),
+		StartedAt: now,
+	}
+	if err := entry.save(); err != nil {
+		return err
+	}
+	s.journal = &entry
+	if err := s.WriteDraft(text); err != nil {
+		return errors.Join(err, s.Close())
+	}
+	return nil
+}
+
+// Recorded tells if the session has a journal
+func (s Session) Recorded() bool {
+	return s.journal != nil
+}
+
+// DraftPath returns the file of the text being edited, empty without journal
+func (s Session) DraftPath() string {
+	if s.journal == nil {
+		return ""
+	}
+	path, _ := s.journal.DraftPath()
+	return path
+}
+
+// WriteDraft records the text being edited
+func (s *Session) WriteDraft(text string) error {
+	if s.journal == nil {
+		return nil
+	}
+	path, err := s.journal.DraftPath()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		return fmt.Errorf("failed to write the draft: %w", err)
+	}
+	return nil
+}
+
+// Close ends the journal of the session, once the edit is saved or discarded
+func (s *Session) Close() error {
+	if s.journal == nil {
+		return nil
+	}
+	err := s.journal.Discard()
+	s.journal = nil
+	return err
+}
+
+// Resume continues an interrupted session, from its base, and returns its draft
+func Resume(entry Entry) (*Session, string, error) {
+	text, err := entry.Draft()
+	if err != nil {
+		return nil, "", err
+	}
+	entry.PID = os.Getpid()
+	if err := entry.save(); err != nil {
+		return nil, "", err
+	}
+	return &Session{NoteID: entry.NoteID, Base: entry.Base, journal: &entry}, text, nil
+}
+
+// SaveDraft saves the text edited in the session, below its header when recorded with one
+func (s *Session) SaveDraft(m *vault.Manager, text string) (*Result, error) {
+	if s.journal != nil && s.journal.Header {
+		return s.SaveWithHeader(m, text)
+	}
+	return s.Save(m, text)
+}
+
+// updateJournal records the new base of the session
+func (s *Session) updateJournal() error {
+	if s.journal == nil {
+		return nil
+	}
+	s.journal.NoteID = s.NoteID
+	s.journal.Base = s.Base
+	return s.journal.save()
+}
+
+// Apply saves the draft of an interrupted session, merged with the changes made since.
+// The journal is closed once saved; on a conflict, it is kept with the session to resolve it
+func Apply(m *vault.Manager, entry Entry) (*Session, *Result, error) {
+	session, text, err := Resume(entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := session.SaveDraft(m, text)
+	if err != nil {
+		return session, nil, err
+	}
+	if result.Outcome != Conflict && result.Outcome != Deleted {
+		if err := session.Close(); err != nil {
+			return session, result, err
+		}
+	}
+	return session, result, nil
+}
@@ -0,0 +1,201 @@
+// Package edit contains the edit sessions of the notes: the version of the note before editing is kept,
+// so the changes made meanwhile, on disk, in the cloud or by another merlion, are merged instead of overwritten
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"merlion/internal/links"
+	"merlion/internal/merge"
+	"merlion/internal/model"
+	"merlion/internal/vault"
+	"merlion/internal/vault/clientError"
+)
+
+// Session is a note being edited, with its version before the edit
+type Session struct {
+	NoteID string
+	Base   model.Note // With its content
+
+	header  *headerEdit // The metadata edited in the header, not saved yet
+	journal *Entry      // The record of the session, see Record
+}
+
+// Outcome of a save
+type Outcome int
+
+const (
+	Saved     Outcome = iota // The note didn't change meanwhile
+	Unchanged                // Nothing to save
+	Merged                   // Both changed, the changes were merged
+	Conflict                 // Both changed the same lines, nothing was saved
+	Deleted                  // The note was deleted meanwhile, nothing was saved
+)
+
+// Result of a save, Mine is the edited content, Theirs the note saved meanwhile and Merge the text with the conflict markers
+type Result struct {
+	Outcome Outcome
+	Note    *model.Note
+	Mine    string
+	Theirs  *model.Note
+	Merge   merge.Result
+}
+
+// Begin loads the note, as the base of the edit
+func Begin(m *vault.Manager, noteID string) (*Session, error) {
+	note, err := m.GetFullNote(noteID)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{NoteID: noteID, Base: *note}, nil
+}
+
+// BaseContent is the content of the note before the edit
+func (s Session) BaseContent() string {
+	if s.Base.Content == nil {
+		return ""
+	}
+	return *s.Base.Content
+}
+
+// Header is the metadata of the base, with the changes of the header not saved yet
+func (s Session) Header() Header {
+	header := HeaderOf(s.Base)
+	if s.header != nil {
+		header = s.header.over(header)
+	}
+	return header
+}
+
+// SaveWithHeader saves the content edited below its header, see Header.
+// A malformed header returns ErrHeader, and nothing is saved
+func (s *Session) SaveWithHeader(m *vault.Manager, text string) (*Result, error) {
+	header, content, err := SplitHeader(text)
+	if err != nil {
+		return nil, err
+	}
+	s.header = &headerEdit{from: HeaderOf(s.Base), to: header}
+	return s.Save(m, content)
+}
+
+// Save writes the content when the note didn't change since the edit started,
+// or merges the changes of both sides. The metadata saved meanwhile, e.g. the tags, are kept,
+// unless they were changed in the header
+func (s *Session) Save(m *vault.Manager, content string) (*Result, error) {
+	theirs, err := m.GetFullNote(s.NoteID)
+	if errors.Is(err, clientError.ErrNoteNotFound) {
+		return &Result{Outcome: Deleted, Mine: content}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	theirContent := ""
+	if theirs.Content != nil {
+		theirContent = *theirs.Content
+	}
+
+	base := s.BaseContent()
+	switch {
+	case content == base || content == theirContent:
+		if !s.headerChanged(*theirs) {
+			return &Result{Outcome: Unchanged, Note: theirs, Mine: content}, nil
+		}
+		note, err := s.write(m, theirs, theirContent)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Outcome: Saved, Note: note, Mine: content}, nil
+	case theirContent == base:
+		note, err := s.write(m, theirs, content)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Outcome: Saved, Note: note, Mine: content}, nil
+	}
+
+	merged := merge.ThreeWay(base, content, theirContent)
+	if merged.Conflicts > 0 {
+		return &Result{Outcome: Conflict, Mine: content, Theirs: theirs, Merge: merged}, nil
+	}
+	note, err := s.write(m, theirs, merged.Text)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Outcome: Merged, Note: note, Mine: content, Theirs: theirs, Merge: merged}, nil
+}
+
+// KeepMine overwrites the changes made meanwhile
+func (s *Session) KeepMine(m *vault.Manager, content string) (*model.Note, error) {
+	theirs, err := m.GetFullNote(s.NoteID)
+	if err != nil {
+		return nil, err
+	}
+	return s.write(m, theirs, content)
+}
+
+// SaveAsCopy creates a new note with the content, next to the one changed meanwhile, e.g. `Title (conflict 2026-01-02 15.04)`
+func (s *Session) SaveAsCopy(m *vault.Manager, content string) (*model.Note, error) {
+	title, err := m.UniqueTitle(fmt.Sprintf("%s (conflict %s)", s.Base.Title, time.Now().Format("2006-01-02 15.04")))
+	if err != nil {
+		return nil, err
+	}
+	req := s.Base.ToCreateRequest()
+	if s.header != nil {
+		s.Header().apply(&req)
+	}
+	req.Title = title
+	req.Content = &content
+	return m.CreateNote(req)
+}
+
+// Rebase continues the edit from the note saved meanwhile, e.g. to edit the merge
+func (s *Session) Rebase(theirs model.Note) error {
+	s.Base = theirs
+	return s.updateJournal()
+}
+
+// headerChanged tells if the header changes the metadata of the note
+func (s Session) headerChanged(current model.Note) bool {
+	return s.header != nil && !s.header.over(HeaderOf(current)).equal(HeaderOf(current))
+}
+
+// write saves the content with the metadata of current, and the ones changed in the header.
+// A new title renames the note in the same write, and updates the links pointing to it
+func (s *Session) write(m *vault.Manager, current *model.Note, content string) (*model.Note, error) {
+	req := current.ToCreateRequest()
+	if s.headerChanged(*current) {
+		s.header.over(HeaderOf(*current)).apply(&req)
+	}
+	req.Content = &content
+
+	var note *model.Note
+	var err error
+	if req.Title != current.Title {
+		var rename *links.Rename
+		rename, err = links.PlanRename(m, s.NoteID, req.Title)
+		if err != nil {
+			return nil, fmt.Errorf("not saved, the note can't be renamed: %w", err)
+		}
+		// The links of the edited content to the note itself follow the rename
+		content = rename.RewriteContent(m, content)
+		rename.Update = func(renamed *model.CreateNoteRequest) {
+			title := renamed.Title
+			*renamed = req
+			renamed.Title = title
+			renamed.Content = &content
+		}
+		note, err = rename.Apply(m)
+	} else {
+		note, err = m.UpdateNote(s.NoteID, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.header = nil
+	s.NoteID = note.NoteID
+	s.Base = *note
+	s.Base.Content = &content
+	return note, nil
+}
@@ -0,0 +1,71 @@
+package edit
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"merlion/internal/config"
+	"merlion/internal/model"
+	"merlion/internal/vault"
+	"merlion/internal/vault/files"
+
+	"github.com/charmbracelet/log"
+)
+
+func TestSaveWithHeaderRenames(t *testing.T) {
+	log.SetOutput(io.Discard)
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("MERLION_DB_PATH", filepath.Join(tmp, "notes.db"))
+	dir := filepath.Join(tmp, "vault")
+	m := vault.NewManager(&config.UserConfig{Vaults: []config.Vault{
+		{Provider: files.Type, Name: dir, Path: dir},
+	}}, nil, false)
+	if _, err := m.ListNoteMetadata(); err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(title, content string) *model.Note {
+		note, err := m.CreateNote(model.CreateNoteRequest{Title: title, Content: &content, Tags: []string{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return note
+	}
+	note := create("Plan", "Body\n")
+	create("Other", "See [[Plan]]\n")
+
+	session, err := Begin(m, note.NoteID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := WithHeader(Header{Title: "Roadmap", Tags: []string{"work"}}, "New body, back to [[Plan#Top]]\n")
+	result, err := session.SaveWithHeader(m, text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != Saved || result.Note.Title != "Roadmap" || session.NoteID != "Roadmap" {
+		t.Fatalf("SaveWithHeader() = %v %+v, session of %s", result.Outcome, result.Note, session.NoteID)
+	}
+
+	for id, expected := range map[string]string{
+		"Roadmap": "New body, back to [[Roadmap#Top]]\n",
+		"Other":   "See [[Roadmap]]\n",
+	} {
+		saved, err := m.GetFullNote(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *saved.Content != expected {
+			t.Errorf("%s = %q, expected %q", id, *saved.Content, expected)
+		}
+		if id == "Roadmap" && (len(saved.Tags) != 1 || saved.Tags[0] != "work") {
+			t.Errorf("%s has the tags %v, expected the ones of the header", id, saved.Tags)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Plan.md")); !os.IsNotExist(err) {
+		t.Errorf("Plan.md is still there: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+// Package links parses the `[[wiki links]]` between notes, as used by Obsidian
+// Supported forms: [[Title]], [[Title#Heading]], [[Title|Alias]] and [[Title#Heading|Alias]]
+package links
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"merlion/internal/model"
+	"merlion/internal/vault"
+)
+
+var linkRegex = regexp.MustCompile(`\[\[([^\[\]|#]*)(?:#([^\[\]|]*))?(?:\|([^\[\]]*))?\]\]`)
+
+type Link struct {
+	Target  string `json:"target"`
+	Heading string `json:"heading,omitempty"`
+	Alias   string `json:"alias,omitempty"`
+	Line    int    `json:"line"` // 1-based line of the link in the content
+	Start   int    `json:"-"`    // Byte offsets of the link in the content
+	End     int    `json:"-"`
+}
+
+// Reference is a link pointing to a note, with the line containing it
+type Reference struct {
+	Note model.Note `json:"note"`
+	Link Link       `json:"link"`
+	Text string     `json:"text"`
+}
+
+// Parse returns all the wiki links of the content
+func Parse(content string) []Link {
+	links := []Link{}
+	for _, match := range linkRegex.FindAllStringSubmatchIndex(content, -1) {
+		link := Link{
+			Target: strings.TrimSpace(content[match[2]:match[3]]),
+			Line:   strings.Count(content[:match[0]], "\n") + 1,
+			Start:  match[0],
+			End:    match[1],
+		}
+		if match[4] != -1 {
+			link.Heading = strings.TrimSpace(content[match[4]:match[5]])
+		}
+		if match[6] != -1 {
+			link.Alias = strings.TrimSpace(content[match[6]:match[7]])
+		}
+		if link.Target == "" {
+			// [[#Heading]] points to the current note
+			continue
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// ParseLink parses a single link, with or without the surrounding brackets
+func ParseLink(text string) Link {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "[[") {
+		text = "[[" + text + "]]"
+	}
+	if links := Parse(text); len(links) > 0 {
+		return links[0]
+	}
+	return Link{}
+}
+
+// Resolve returns the note targeted by a link, matching in order the ID, the title, the aliases
+// and the last element of the ID for notes in sub folders (files vaults)
+func Resolve(notes []model.Note, target string) *model.Note {
+	target = strings.TrimSuffix(strings.TrimSpace(target), ".md")
+	if target == "" {
+		return nil
+	}
+	for i := range notes {
+		if notes[i].NoteID == target {
+			return &notes[i]
+		}
+	}
+	for i := range notes {
+		if strings.EqualFold(strings.TrimSpace(notes[i].Title), target) {
+			return &notes[i]
+		}
+	}
+	for i := range notes {
+		for _, alias := range notes[i].Aliases {
+			if strings.EqualFold(strings.TrimSpace(alias), target) {
+				return &notes[i]
+			}
+		}
+	}
+	for i := range notes {
+		if strings.EqualFold(path.Base(notes[i].NoteID), target) {
+			return &notes[i]
+		}
+	}
+	return nil
+}
+
+// Backlinks returns the links of the vault pointing to the note
+// The content of the notes is loaded when missing from the cache
+func Backlinks(m *vault.Manager, noteID string) ([]Reference, error) {
+	references := []Reference{}
+	ids := make([]string, 0, len(m.Notes))
+	for _, note := range m.Notes {
+		if note.NoteID != noteID {
+			ids = append(ids, note.NoteID)
+		}
+	}
+
+	for _, id := range ids {
+		note := m.SearchByID(id)
+		if note == nil {
+			continue
+		}
+		if note.Content == nil {
+			full, err := m.GetFullNote(id)
+			if err != nil {
+				return nil, err
+			}
+			note = full
+		}
+		lines := strings.Split(*note.Content, "\n")
+		for _, link := range Parse(*note.Content) {
+			target := Resolve(m.Notes, link.Target)
+			if target == nil || target.NoteID != noteID {
+				continue
+			}
+			metadata := *note
+			metadata.Content = nil
+			references = append(references, Reference{
+				Note: metadata,
+				Link: link,
+				Text: strings.TrimSpace(lines[link.Line-1]),
+			})
+		}
+	}
+	return references, nil
+}
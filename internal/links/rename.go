@@ -0,0 +1,230 @@
+package links
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"merlion/internal/model"
+	"merlion/internal/vault"
+	"merlion/internal/vault/clientError"
+)
+
+// Renames ---
+
+// Edit is a note whose links are rewritten by a rename
+type Edit struct {
+	Note     model.Note `json:"note"`     // Without content
+	Links    []Link     `json:"links"`    // The links to the renamed note, before the rename
+	OldLinks []string   `json:"oldLinks"` // The text of the links, as written in the note
+	NewLinks []string   `json:"newLinks"` // The rewritten links, in the same order
+	self     bool
+	before   model.Note
+	content  string
+}
+
+// Rename is a link aware rename, planned by PlanRename to preview the edits, then run by Apply
+type Rename struct {
+	NoteID   string `json:"noteId"`
+	OldTitle string `json:"oldTitle"`
+	NewTitle string `json:"newTitle"`
+	Edits    []Edit `json:"edits"`
+	// Update, when set, changes the other fields of the renamed note in the same write, e.g. its tags
+	Update func(req *model.CreateNoteRequest) `json:"-"`
+	note   model.Note
+}
+
+// PlanRename finds the links to the note in the whole vault, in all their forms:
+// [[Title]], [[Title#Heading]], [[Title|Alias]], [[folder/Title]] and [[Title.md]].
+// The links using an alias or the ID of the note are still valid after the rename and are kept
+func PlanRename(m *vault.Manager, noteID string, newTitle string) (*Rename, error) {
+	newTitle = strings.TrimSpace(newTitle)
+	if newTitle == "" {
+		return nil, fmt.Errorf("the new title is empty")
+	}
+	cached := m.SearchByID(noteID)
+	if cached == nil {
+		return nil, fmt.Errorf("%w: %s", clientError.ErrNoteNotFound, noteID)
+	}
+	if existing := m.SearchByTitle(newTitle); existing != nil && existing.NoteID != noteID {
+		return nil, fmt.Errorf("a note named '%s' already exists", existing.Title)
+	}
+
+	rename := &Rename{NoteID: noteID, OldTitle: cached.Title, NewTitle: newTitle, Edits: []Edit{}}
+	ids := make([]string, 0, len(m.Notes))
+	for _, note := range m.Notes {
+		ids = append(ids, note.NoteID)
+	}
+	for _, id := range ids {
+		note := m.SearchByID(id)
+		if note == nil {
+			continue
+		}
+		if note.Content == nil {
+			full, err := m.GetFullNote(id)
+			if err != nil {
+				return nil, err
+			}
+			note = full
+		}
+		if id == noteID {
+			rename.note = *note
+		}
+
+		edit := rewriteLinks(m.Notes, *note.Content, *cached, newTitle)
+		edit.self = id == noteID
+		edit.before = *note
+		if len(edit.Links) > 0 {
+			edit.Note = *note
+			edit.Note.Content = nil
+			rename.Edits = append(rename.Edits, edit)
+		}
+	}
+	return rename, nil
+}
+
+// rewriteLinks rewrites the links of the content pointing to the note
+func rewriteLinks(notes []model.Note, content string, note model.Note, newTitle string) Edit {
+	edit := Edit{content: content}
+	found := Parse(content)
+	// From the end, so the offsets of the previous links stay valid
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Start > found[j].Start })
+	for _, link := range found {
+		target := Resolve(notes, link.Target)
+		if target == nil || target.NoteID != note.NoteID {
+			continue
+		}
+		oldLink := edit.content[link.Start:link.End]
+		newLink, ok := rewriteLink(oldLink, note, newTitle)
+		if !ok {
+			continue
+		}
+		edit.content = edit.content[:link.Start] + newLink + edit.content[link.End:]
+		edit.Links = append([]Link{link}, edit.Links...)
+		edit.OldLinks = append([]string{oldLink}, edit.OldLinks...)
+		edit.NewLinks = append([]string{newLink}, edit.NewLinks...)
+	}
+	return edit
+}
+
+// RewriteContent rewrites the links to the renamed note in a content about to replace its own,
+// e.g. an edit saved with the rename. It must run before Apply, while the vault knows the old title
+func (r Rename) RewriteContent(m *vault.Manager, content string) string {
+	note := r.note
+	note.Title = r.OldTitle
+	return rewriteLinks(m.Notes, content, note, r.NewTitle).content
+}
+
+// rewriteLink returns the link text pointing to the new title, keeping the heading, the alias and the spacing
+func rewriteLink(text string, note model.Note, newTitle string) (string, bool) {
+	inner := text[2 : len(text)-2]
+	end := strings.IndexAny(inner, "#|")
+	if end == -1 {
+		end = len(inner)
+	}
+	rawTarget := inner[:end]
+	target := strings.TrimSpace(rawTarget)
+	extension := ""
+	if strings.HasSuffix(target, ".md") {
+		target = strings.TrimSuffix(target, ".md")
+		extension = ".md"
+	}
+
+	var newTarget string
+	switch {
+	case strings.EqualFold(target, strings.TrimSpace(note.Title)):
+		newTarget = newTitle
+	case target == note.NoteID:
+		// The ID of the note doesn't change with its title
+		return "", false
+	case strings.EqualFold(target, path.Base(note.NoteID)):
+		// Note of a sub folder linked by its name, files vaults
+		newTarget = path.Base(newTitle)
+	default:
+		// Linked by an alias
+		return "", false
+	}
+	if newTarget == target {
+		return "", false
+	}
+	leading := rawTarget[:len(rawTarget)-len(strings.TrimLeft(rawTarget, " \t"))]
+	trailing := rawTarget[len(strings.TrimRight(rawTarget, " \t")):]
+	return "[[" + leading + newTarget + extension + trailing + inner[end:] + "]]", true
+}
+
+// LinkCount returns the number of links rewritten by the rename
+func (r Rename) LinkCount() int {
+	count := 0
+	for _, edit := range r.Edits {
+		count += len(edit.Links)
+	}
+	return count
+}
+
+// Summary describes the edits, e.g. `3 links in 2 notes`
+func (r Rename) Summary() string {
+	links, notes := "links", "notes"
+	if r.LinkCount() == 1 {
+		links = "link"
+	}
+	if len(r.Edits) == 1 {
+		notes = "note"
+	}
+	return fmt.Sprintf("%d %s in %d %s", r.LinkCount(), links, len(r.Edits), notes)
+}
+
+// Apply renames the note, then rewrites the links of the other notes.
+// On error, the notes already written are restored and the note gets back its title
+func (r Rename) Apply(m *vault.Manager) (*model.Note, error) {
+	req := r.note.ToCreateRequest()
+	req.Title = r.NewTitle
+	for _, edit := range r.Edits {
+		if edit.self {
+			content := edit.content
+			req.Content = &content
+		}
+	}
+	if r.Update != nil {
+		r.Update(&req)
+	}
+	renamed, err := m.UpdateNote(r.NoteID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, edit := range r.Edits {
+		if edit.self {
+			continue
+		}
+		req := edit.before.ToCreateRequest()
+		content := edit.content
+		req.Content = &content
+		if _, err := m.UpdateNote(edit.Note.NoteID, req); err != nil {
+			err = fmt.Errorf("updating the links of '%s': %w", edit.Note.Title, err)
+			if rollbackErr := r.rollback(m, renamed.NoteID, r.Edits[:i]); rollbackErr != nil {
+				return nil, errors.Join(err, fmt.Errorf("reverting the rename: %w", rollbackErr))
+			}
+			return nil, fmt.Errorf("%w, the rename was reverted", err)
+		}
+	}
+	return renamed, nil
+}
+
+// rollback restores the content of the edited notes, then the title of the renamed one
+func (r Rename) rollback(m *vault.Manager, renamedID string, applied []Edit) error {
+	errs := []error{}
+	for _, edit := range applied {
+		if edit.self {
+			continue
+		}
+		if _, err := m.UpdateNote(edit.Note.NoteID, edit.before.ToCreateRequest()); err != nil {
+			errs = append(errs, fmt.Errorf("'%s': %w", edit.Note.Title, err))
+		}
+	}
+	if _, err := m.UpdateNote(renamedID, r.note.ToCreateRequest()); err != nil {
+		errs = append(errs, fmt.Errorf("'%s': %w", r.NewTitle, err))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+package links
+
+import (
+	"testing"
+
+	"merlion/internal/model"
+)
+
+func TestRewriteLink(t *testing.T) {
+	global := model.Note{NoteID: "2f1c9a", Title: "Title"}
+	files := model.Note{NoteID: "folder/Title", Title: "folder/Title"}
+	tests := []struct {
+		name     string
+		note     model.Note
+		newTitle string
+		link     string
+		want     string // Empty when the link is kept
+	}{
+		{"title", global, "New", "[[Title]]", "[[New]]"},
+		{"case insensitive", global, "New", "[[title]]", "[[New]]"},
+		{"heading", global, "New", "[[Title#Heading]]", "[[New#Heading]]"},
+		{"alias", global, "New", "[[Title|shown]]", "[[New|shown]]"},
+		{"heading and alias", global, "New", "[[Title#Heading|shown]]", "[[New#Heading|shown]]"},
+		{"extension", global, "New", "[[Title.md]]", "[[New.md]]"},
+		{"spacing", global, "New", "[[ Title |shown]]", "[[ New |shown]]"},
+		{"linked by an alias", global, "New", "[[Other name]]", ""},
+		{"linked by its ID", global, "New", "[[2f1c9a]]", ""},
+		{"linked by its ID with a heading", global, "New", "[[2f1c9a#Heading]]", ""},
+		{"folder", files, "folder/New", "[[folder/Title]]", "[[folder/New]]"},
+		{"moved to another folder", files, "other/New", "[[folder/Title#Heading]]", "[[other/New#Heading]]"},
+		{"name of a note in a folder", files, "folder/New", "[[Title]]", "[[New]]"},
+		{"name with its extension", files, "folder/New", "[[Title.md|shown]]", "[[New.md|shown]]"},
+		{"name unchanged by the move", files, "other/Title", "[[Title]]", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rewriteLink(tt.link, tt.note, tt.newTitle)
+			if !ok {
+				got = ""
+			}
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("rewriteLink(%q, %q) = %q, %v, expected %q", tt.link, tt.newTitle, got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+// Package merge contains the line based three-way merge of the notes edited in two places
+package merge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markers around the conflicting sections, like git
+const (
+	MarkerMine   = "<<<<<<< mine"
+	MarkerSplit  = "======="
+	MarkerTheirs = ">>>>>>> theirs"
+)
+
+// Above this number of compared lines, the changed sections are not diffed line by line
+const maxDiffCells = 4_000_000
+
+// Result is the merged text, with the conflicting sections between markers
+type Result struct {
+	Text      string
+	Conflicts int
+}
+
+// change replaces the lines [start, end) of the base
+type change struct {
+	start int
+	end   int
+	lines []string
+}
+
+// ThreeWay merges the changes made to base in mine and in theirs.
+// The changes of both sides are kept when they touch different lines, the others are conflicts
+func ThreeWay(base string, mine string, theirs string) Result {
+	baseLines := splitLines(base)
+	mineChanges := diff(baseLines, splitLines(mine))
+	theirChanges := diff(baseLines, splitLines(theirs))
+
+	var out strings.Builder
+	conflicts := 0
+	pos := 0
+	m, t := 0, 0
+	for m < len(mineChanges) || t < len(theirChanges) {
+		// The region starts with the first change of either side
+		var first change
+		if t >= len(theirChanges) || (m < len(mineChanges) && mineChanges[m].start <= theirChanges[t].start) {
+			first = mineChanges[m]
+		} else {
+			first = theirChanges[t]
+		}
+		start, end := first.start, first.end
+
+		// Then grows with all the changes touching it, on both sides
+		mineFrom, theirFrom := m, t
+		for {
+			grown := false
+			if m < len(mineChanges) && mineChanges[m].start <= end {
+				end = max(end, mineChanges[m].end)
+				m++
+				grown = true
+			}
+			if t < len(theirChanges) && theirChanges[t].start <= end {
+				end = max(end, theirChanges[t].end)
+				t++
+				grown = true
+			}
+			if !grown {
+				break
+			}
+		}
+
+		writeLines(&out, baseLines[pos:start])
+		mineRegion := mineChanges[mineFrom:m]
+		theirRegion := theirChanges[theirFrom:t]
+		switch {
+		case len(theirRegion) == 0:
+			writeLines(&out, apply(baseLines, start, end, mineRegion))
+		case len(mineRegion) == 0:
+			writeLines(&out, apply(baseLines, start, end, theirRegion))
+		default:
+			mineText := apply(baseLines, start, end, mineRegion)
+			theirText := apply(baseLines, start, end, theirRegion)
+			if strings.Join(mineText, "") == strings.Join(theirText, "") {
+				writeLines(&out, mineText)
+				break
+			}
+			conflicts++
+			out.WriteString(MarkerMine + "\n")
+			writeSection(&out, mineText)
+			out.WriteString(MarkerSplit + "\n")
+			writeSection(&out, theirText)
+			out.WriteString(MarkerTheirs + "\n")
+		}
+		pos = end
+	}
+	writeLines(&out, baseLines[pos:])
+	return Result{Text: out.String(), Conflicts: conflicts}
+}
+
+// splitLines keeps the line breaks, so the text is rebuilt as it was
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+}
+
+// writeSection writes a side of a conflict, ending with a line break before the next marker
+func writeSection(out *strings.Builder, lines []string) {
+	writeLines(out, lines)
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		out.WriteString("\n")
+	}
+}
+
+// apply returns the lines [start, end) of the base, with the changes of one side
+func apply(base []string, start int, end int, changes []change) []string {
+	lines := []string{}
+	pos := start
+	for _, c := range changes {
+		lines = append(lines, base[pos:c.start]...)
+		lines = append(lines, c.lines...)
+		pos = c.end
+	}
+	return append(lines, base[pos:end]...)
+}
+
+// diff returns the changes from base to other, using the longest common subsequence of lines
+func diff(base []string, other []string) []change {
+	// The common start and end are not compared line by line
+	prefix := 0
+	for prefix < len(base) && prefix < len(other) && base[prefix] == other[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(base)-prefix && suffix < len(other)-prefix &&
+		base[len(base)-1-suffix] == other[len(other)-1-suffix] {
+		suffix++
+	}
+	a := base[prefix : len(base)-suffix]
+	b := other[prefix : len(other)-suffix]
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	if len(a) == 0 || len(b) == 0 || (len(a)+1)*(len(b)+1) > maxDiffCells {
+		return []change{{start: prefix, end: prefix + len(a), lines: b}}
+	}
+
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	changes := []change{}
+	i, j := 0, 0
+	startA, startB := 0, 0
+	flush := func() {
+		if i > startA || j > startB {
+			changes = append(changes, change{start: prefix + startA, end: prefix + i, lines: b[startB:j]})
+		}
+	}
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			flush()
+			i++
+			j++
+			startA, startB = i, j
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	i, j = len(a), len(b)
+	flush()
+	return changes
+}
+
+// Diff returns the changes from base to other like `diff -u`: the hunks of removed lines, starting with `-`,
+// and of added lines, starting with `+`, between context lines of the base
+func Diff(base string, other string, context int) string {
+	baseLines := splitLines(base)
+	changes := diff(baseLines, splitLines(other))
+
+	var out strings.Builder
+	line := func(prefix string, text string) {
+		out.WriteString(prefix + strings.TrimSuffix(text, "\n") + "\n")
+	}
+	delta := 0 // Lines added before the hunk
+	for first := 0; first < len(changes); {
+		// The hunk groups the changes whose context lines touch
+		last := first
+		for last+1 < len(changes) && changes[last+1].start-changes[last].end <= 2*context {
+			last++
+		}
+		start := max(changes[first].start-context, 0)
+		end := min(changes[last].end+context, len(baseLines))
+		hunkDelta := 0
+		for _, c := range changes[first : last+1] {
+			hunkDelta += len(c.lines) - (c.end - c.start)
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+delta+1, end-start+hunkDelta)
+
+		pos := start
+		for _, c := range changes[first : last+1] {
+			for _, text := range baseLines[pos:c.start] {
+				line(" ", text)
+			}
+			for _, text := range baseLines[c.start:c.end] {
+				line("-", text)
+			}
+			for _, text := range c.lines {
+				line("+", text)
+			}
+			pos = c.end
+		}
+		for _, text := range baseLines[pos:end] {
+			line(" ", text)
+		}
+		delta += hunkDelta
+		first = last + 1
+	}
+	return out.String()
+}
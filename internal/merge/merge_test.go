@@ -0,0 +1,155 @@
+package merge
+
+import (
+	"testing"
+)
+
+func TestThreeWay(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      string
+		mine      string
+		theirs    string
+		text      string
+		conflicts int
+	}{
+		{
+			name: "unchanged",
+			base: "a\nb\n", mine: "a\nb\n", theirs: "a\nb\n",
+			text: "a\nb\n",
+		},
+		{
+			name: "only mine",
+			base: "a\nb\nc\n", mine: "a\nB\nc\n", theirs: "a\nb\nc\n",
+			text: "a\nB\nc\n",
+		},
+		{
+			name: "only theirs",
+			base: "a\nb\nc\n", mine: "a\nb\nc\n", theirs: "a\nb\nC\n",
+			text: "a\nb\nC\n",
+		},
+		{
+			name: "different lines",
+			base: "a\nb\nc\nd\ne\n", mine: "A\nb\nc\nd\ne\n", theirs: "a\nb\nc\nd\nE\n",
+			text: "A\nb\nc\nd\nE\n",
+		},
+		{
+			name: "identical changes",
+			base: "a\nb\nc\n", mine: "a\nB\nc\n", theirs: "a\nB\nc\n",
+			text: "a\nB\nc\n",
+		},
+		{
+			name: "overlapping changes",
+			base: "a\nb\nc\n", mine: "a\nX\nc\n", theirs: "a\nY\nc\n",
+			text:      "a\n<<<<<<< mine\nX\n=======\nY\n>>>>>>> theirs\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "adjacent changes",
+			base: "a\nb\nc\nd\n", mine: "a\nB\nc\nd\n", theirs: "a\nb\nC\nd\n",
+			text:      "a\n<<<<<<< mine\nB\nc\n=======\nb\nC\n>>>>>>> theirs\nd\n",
+			conflicts: 1,
+		},
+		{
+			name: "two conflicts",
+			base: "a\nb\nc\nd\ne\n", mine: "a\n1\nc\n1\ne\n", theirs: "a\n2\nc\n2\ne\n",
+			text: "a\n<<<<<<< mine\n1\n=======\n2\n>>>>>>> theirs\n" +
+				"c\n<<<<<<< mine\n1\n=======\n2\n>>>>>>> theirs\ne\n",
+			conflicts: 2,
+		},
+		{
+			name: "deletion and edit of the same line",
+			base: "a\nb\nc\n", mine: "a\nc\n", theirs: "a\nB\nc\n",
+			text:      "a\n<<<<<<< mine\n=======\nB\n>>>>>>> theirs\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "deletion and edit of different lines",
+			base: "a\nb\nc\nd\n", mine: "a\nc\nd\n", theirs: "a\nb\nc\nD\n",
+			text: "a\nc\nD\n",
+		},
+		{
+			name: "additions at the end",
+			base: "a\n", mine: "a\nb\n", theirs: "a\nc\n",
+			text:      "a\n<<<<<<< mine\nb\n=======\nc\n>>>>>>> theirs\n",
+			conflicts: 1,
+		},
+		{
+			name: "trailing newline added on one side",
+			base: "a\nb\nc", mine: "A\nb\nc", theirs: "a\nb\nc\n",
+			text: "A\nb\nc\n",
+		},
+		{
+			name: "conflict without trailing newline",
+			base: "a", mine: "b", theirs: "c",
+			text:      "<<<<<<< mine\nb\n=======\nc\n>>>>>>> theirs\n",
+			conflicts: 1,
+		},
+		{
+			name: "empty base",
+			base: "", mine: "a\n", theirs: "a\n",
+			text: "a\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ThreeWay(tt.base, tt.mine, tt.theirs)
+			if result.Text != tt.text || result.Conflicts != tt.conflicts {
+				t.Errorf("ThreeWay() = %q with %d conflicts, expected %q with %d", result.Text, result.Conflicts, tt.text, tt.conflicts)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		other   string
+		context int
+		diff    string
+	}{
+		{
+			name: "identical",
+			base: "1\n2\n", other: "1\n2\n", context: 3,
+			diff: "",
+		},
+		{
+			name: "edit",
+			base: "1\n2\n3\n4\n5\n", other: "1\n2\nx\n4\n5\n", context: 1,
+			diff: "@@ -2,3 +2,3 @@\n 2\n-3\n+x\n 4\n",
+		},
+		{
+			name: "addition at the end",
+			base: "1\n2\n", other: "1\n2\n3\n", context: 1,
+			diff: "@@ -2,1 +2,2 @@\n 2\n+3\n",
+		},
+		{
+			name: "changes in one hunk",
+			base: "1\n2\n3\n4\n5\n", other: "1\na\n3\nb\n5\n", context: 1,
+			diff: "@@ -1,5 +1,5 @@\n 1\n-2\n+a\n 3\n-4\n+b\n 5\n",
+		},
+		{
+			name: "changes in two hunks",
+			base: "1\n2\n3\n4\n5\n6\n7\n8\n9\n", other: "1\na\n3\n4\n5\n6\n7\nb\n9\n", context: 1,
+			diff: "@@ -1,3 +1,3 @@\n 1\n-2\n+a\n 3\n@@ -7,3 +7,3 @@\n 7\n-8\n+b\n 9\n",
+		},
+		{
+			name: "deletion shifts the next hunk",
+			base: "1\n2\n3\n4\n5\n6\n7\n8\n9\n", other: "1\n3\n4\n5\n6\n7\nb\n9\n", context: 1,
+			diff: "@@ -1,3 +1,2 @@\n 1\n-2\n 3\n@@ -7,3 +6,3 @@\n 7\n-8\n+b\n 9\n",
+		},
+		{
+			name: "missing trailing newline",
+			base: "a\nb", other: "a\nb\n", context: 0,
+			diff: "@@ -2,1 +2,1 @@\n-b\n+b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := Diff(tt.base, tt.other, tt.context); diff != tt.diff {
+				t.Errorf("Diff() = %q, expected %q", diff, tt.diff)
+			}
+		})
+	}
+}
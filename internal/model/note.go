@@ -8,11 +8,14 @@ type Note struct {
 	Content     *string   `json:"content"`      // pointer for nullable string
 	WorkspaceID *string   `json:"workspace_id"` // pointer for nullable UUID
 	Tags        []string  `json:"tags"`
+	Aliases     []string  `json:"aliases,omitempty"` // Alternative titles, Obsidian vaults only
 	IsFavorite  bool      `json:"is_favorite"`
 	IsWorkLog   bool      `json:"is_work_log"`
 	IsPublic    bool      `json:"is_public"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	Properties map[string]any `json:"properties,omitempty"` // Custom front matter properties, Obsidian vaults only
 }
 
 type CreateNoteRequest struct {
@@ -25,6 +28,9 @@ type CreateNoteRequest struct {
 	IsPublic    *bool      `json:"is_public,omitempty"`
 	CreatedAt   *time.Time `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at"`
+
+	Properties map[string]any `json:"properties,omitempty"` // Replaces the custom properties when set, Obsidian vaults only
+	Aliases    []string       `json:"aliases,omitempty"`    // Replaces the aliases when set, Obsidian vaults only
 }
 
 // Utilities
@@ -0,0 +1,381 @@
+// Package query implements the search language shared by the note list filter, the CLI and the RPC server
+//
+//	tag:infra -tag:archived is:fav created:>=2025-01-01 updated:<7d "exact phrase" title:deploy
+//
+// Terms are separated by spaces and must all match. A leading `-` negates a term.
+// Words and "quoted phrases" match the title, the aliases or the content, case insensitive.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"merlion/internal/model"
+)
+
+// Fields of a `field:value` term
+const (
+	FieldText    = "" // Bare word or phrase
+	FieldTitle   = "title"
+	FieldContent = "content"
+	FieldTag     = "tag"
+	FieldIs      = "is"
+	FieldCreated = "created"
+	FieldUpdated = "updated"
+)
+
+var fields = []string{FieldTitle, FieldContent, FieldTag, FieldIs, FieldCreated, FieldUpdated}
+
+// Values of `is:`
+const (
+	IsFavorite = "fav"
+	IsWorkLog  = "worklog"
+	IsPublic   = "public"
+	IsUntagged = "untagged"
+)
+
+var isAliases = map[string]string{
+	"fav": IsFavorite, "favorite": IsFavorite,
+	"worklog": IsWorkLog, "log": IsWorkLog,
+	"public":   IsPublic,
+	"untagged": IsUntagged,
+}
+
+// Comparison operators of the date fields
+const (
+	OpEq = "="
+	OpLt = "<"
+	OpLe = "<="
+	OpGt = ">"
+	OpGe = ">="
+)
+
+type Term struct {
+	Field   string
+	Value   string // Lower case, except for the dates
+	Negated bool
+	Pos     int // Byte offset of the term in the query
+
+	// Date fields: the dates between From (included) and To (excluded), a zero bound is open
+	From time.Time
+	To   time.Time
+}
+
+type Query struct {
+	Raw   string
+	Terms []Term
+}
+
+// Error points to the part of the query which can't be parsed
+type Error struct {
+	Query   string
+	Pos     int
+	Len     int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos+1, e.Message)
+}
+
+// Pointer underlines the invalid part of the query, to be displayed below it
+func (e *Error) Pointer() string {
+	offset := len([]rune(e.Query[:min(e.Pos, len(e.Query))]))
+	return strings.Repeat(" ", offset) + "^" + strings.Repeat("~", max(e.Len-1, 0))
+}
+
+// Parse reads a query, the relative dates like `7d` are computed from now
+func Parse(input string, now time.Time) (Query, error) {
+	q := Query{Raw: input}
+	pos := 0
+	for {
+		for pos < len(input) && input[pos] == ' ' {
+			pos++
+		}
+		if pos >= len(input) {
+			return q, nil
+		}
+
+		start := pos
+		term := Term{Pos: start}
+		if input[pos] == '-' && pos+1 < len(input) && input[pos+1] != ' ' {
+			term.Negated = true
+			pos++
+		}
+
+		// field:value
+		if end := identifierEnd(input, pos); end < len(input) && input[end] == ':' && end > pos {
+			name := strings.ToLower(input[pos:end])
+			if !isField(name) {
+				return q, &Error{Query: input, Pos: pos, Len: end - pos,
+					Message: fmt.Sprintf("unknown field %q, expected one of %s (quote the text to search it)", name, strings.Join(fields, ", "))}
+			}
+			term.Field = name
+			pos = end + 1
+		}
+
+		valuePos := pos
+		value, next, err := readValue(input, pos)
+		if err != nil {
+			return q, err
+		}
+		pos = next
+		if value == "" {
+			if term.Field == FieldText {
+				return q, &Error{Query: input, Pos: start, Len: pos - start, Message: "empty phrase"}
+			}
+			return q, &Error{Query: input, Pos: start, Len: pos - start, Message: fmt.Sprintf("missing value after %s:", term.Field)}
+		}
+
+		if err := term.setValue(value, now); err != nil {
+			return q, &Error{Query: input, Pos: valuePos, Len: pos - valuePos, Message: err.Error()}
+		}
+		q.Terms = append(q.Terms, term)
+	}
+}
+
+func identifierEnd(input string, pos int) int {
+	for pos < len(input) && (input[pos] >= 'a' && input[pos] <= 'z' || input[pos] >= 'A' && input[pos] <= 'Z') {
+		pos++
+	}
+	return pos
+}
+
+func isField(name string) bool {
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readValue reads a word or a quoted phrase, returns the position after it
+func readValue(input string, pos int) (string, int, error) {
+	if pos < len(input) && input[pos] == '"' {
+		end := strings.IndexByte(input[pos+1:], '"')
+		if end == -1 {
+			return "", pos, &Error{Query: input, Pos: pos, Len: len(input) - pos, Message: "unterminated quote"}
+		}
+		return input[pos+1 : pos+1+end], pos + end + 2, nil
+	}
+	end := pos
+	for end < len(input) && input[end] != ' ' {
+		if input[end] == '"' {
+			return "", pos, &Error{Query: input, Pos: end, Len: 1, Message: "unexpected quote, phrases must be separated by a space"}
+		}
+		end++
+	}
+	return input[pos:end], end, nil
+}
+
+func (t *Term) setValue(value string, now time.Time) error {
+	switch t.Field {
+	case FieldIs:
+		is, ok := isAliases[strings.ToLower(value)]
+		if !ok {
+			return fmt.Errorf("unknown value %q for is:, expected fav, worklog, public or untagged", value)
+		}
+		t.Value = is
+	case FieldCreated, FieldUpdated:
+		t.Value = value
+		return t.setDateRange(value, now)
+	case FieldTag:
+		t.Value = strings.ToLower(strings.TrimPrefix(value, "#"))
+	default:
+		t.Value = strings.ToLower(value)
+	}
+	return nil
+}
+
+// setDateRange converts `>=2025-01-01` or `<7d` to a range of dates
+func (t *Term) setDateRange(value string, now time.Time) error {
+	op := OpEq
+	for _, candidate := range []string{OpGe, OpLe, OpGt, OpLt, OpEq} {
+		if strings.HasPrefix(value, candidate) {
+			op = candidate
+			value = value[len(candidate):]
+			break
+		}
+	}
+
+	var day time.Time
+	if duration, ok, err := parseAge(value); err != nil {
+		return err
+	} else if ok {
+		// An age: <7d is less than 7 days ago, so after the date
+		at := now.Add(-duration)
+		switch op {
+		case OpLt:
+			t.From = at.Add(time.Nanosecond)
+		case OpLe:
+			t.From = at
+		case OpGt:
+			t.To = at
+		case OpGe:
+			t.To = at.Add(time.Nanosecond)
+		default:
+			day = startOfDay(at)
+			t.From, t.To = day, day.AddDate(0, 0, 1)
+		}
+		return nil
+	}
+
+	switch strings.ToLower(value) {
+	case "today":
+		day = startOfDay(now)
+	case "yesterday":
+		day = startOfDay(now).AddDate(0, 0, -1)
+	default:
+		parsed, err := time.ParseInLocation("2006-01-02", value, now.Location())
+		if err != nil {
+			return fmt.Errorf("invalid date %q, expected YYYY-MM-DD, today, yesterday or an age like 7d", value)
+		}
+		day = parsed
+	}
+	next := day.AddDate(0, 0, 1)
+	switch op {
+	case OpLt:
+		t.To = day
+	case OpLe:
+		t.To = next
+	case OpGt:
+		t.From = next
+	case OpGe:
+		t.From = day
+	default:
+		t.From, t.To = day, next
+	}
+	return nil
+}
+
+// parseAge reads durations like 12h, 7d, 2w, 3m (months) or 1y
+func parseAge(value string) (time.Duration, bool, error) {
+	if len(value) < 2 || !unicode.IsDigit(rune(value[0])) {
+		return 0, false, nil
+	}
+	unit := value[len(value)-1]
+	count, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil {
+		// A date
+		return 0, false, nil
+	}
+	day := 24 * time.Hour
+	switch unit {
+	case 'h':
+		return time.Duration(count) * time.Hour, true, nil
+	case 'd':
+		return time.Duration(count) * day, true, nil
+	case 'w':
+		return time.Duration(count) * 7 * day, true, nil
+	case 'm':
+		return time.Duration(count) * 30 * day, true, nil
+	case 'y':
+		return time.Duration(count) * 365 * day, true, nil
+	}
+	return 0, false, fmt.Errorf("unknown unit %q in %q, expected h, d, w, m or y", string(unit), value)
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// IsPlainText returns true when the query only has words, which the list filter matches fuzzily
+func (q Query) IsPlainText() bool {
+	for _, term := range q.Terms {
+		if term.Field != FieldText || term.Negated {
+			return false
+		}
+	}
+	return !strings.Contains(q.Raw, `"`)
+}
+
+// Match returns true when the note matches every term of the query
+// The content is only searched when loaded
+func (q Query) Match(note model.Note) bool {
+	for _, term := range q.Terms {
+		if term.Match(note) == term.Negated {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the notes matching the query, in the same order
+func (q Query) Filter(notes []model.Note) []model.Note {
+	result := []model.Note{}
+	for _, note := range notes {
+		if q.Match(note) {
+			result = append(result, note)
+		}
+	}
+	return result
+}
+
+// Match returns true when the note matches the term, ignoring the negation
+func (t Term) Match(note model.Note) bool {
+	switch t.Field {
+	case FieldTitle:
+		return contains(note.Title, t.Value)
+	case FieldContent:
+		return note.Content != nil && contains(*note.Content, t.Value)
+	case FieldTag:
+		return HasTag(note, t.Value)
+	case FieldIs:
+		switch t.Value {
+		case IsFavorite:
+			return note.IsFavorite
+		case IsWorkLog:
+			return note.IsWorkLog
+		case IsPublic:
+			return note.IsPublic
+		case IsUntagged:
+			return len(note.Tags) == 0
+		}
+		return false
+	case FieldCreated:
+		return t.inRange(note.CreatedAt)
+	case FieldUpdated:
+		return t.inRange(note.UpdatedAt)
+	default:
+		if contains(note.Title, t.Value) {
+			return true
+		}
+		for _, alias := range note.Aliases {
+			if contains(alias, t.Value) {
+				return true
+			}
+		}
+		return note.Content != nil && contains(*note.Content, t.Value)
+	}
+}
+
+func (t Term) inRange(date time.Time) bool {
+	if !t.From.IsZero() && date.Before(t.From) {
+		return false
+	}
+	if !t.To.IsZero() && !date.Before(t.To) {
+		return false
+	}
+	return true
+}
+
+func contains(text string, value string) bool {
+	return strings.Contains(strings.ToLower(text), value)
+}
+
+// HasTag returns true when the note has the tag, or one of its nested tags (`work` matches `work/infra`)
+func HasTag(note model.Note, tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, noteTag := range note.Tags {
+		noteTag = strings.ToLower(noteTag)
+		if noteTag == tag || strings.HasPrefix(noteTag, tag+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,264 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"merlion/internal/model"
+)
+
+var now = time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		terms []Term
+	}{
+		{"empty", "   ", nil},
+		{"words", "deploy  Infra", []Term{
+			{Value: "deploy", Pos: 0},
+			{Value: "infra", Pos: 8},
+		}},
+		{"phrase", `"exact Phrase" x`, []Term{
+			{Value: "exact phrase", Pos: 0},
+			{Value: "x", Pos: 15},
+		}},
+		{"quoted field value", `title:"release notes"`, []Term{
+			{Field: FieldTitle, Value: "release notes"},
+		}},
+		{"field names are case insensitive", "TAG:Infra", []Term{
+			{Field: FieldTag, Value: "infra"},
+		}},
+		{"tag without its hash", "tag:#work/infra", []Term{
+			{Field: FieldTag, Value: "work/infra"},
+		}},
+		{"negation", "-tag:archived -draft", []Term{
+			{Field: FieldTag, Value: "archived", Negated: true},
+			{Value: "draft", Negated: true, Pos: 14},
+		}},
+		{"lone dash is a word", "a - b", []Term{
+			{Value: "a"},
+			{Value: "-", Pos: 2},
+			{Value: "b", Pos: 4},
+		}},
+		{"is aliases", "is:favorite is:log", []Term{
+			{Field: FieldIs, Value: IsFavorite},
+			{Field: FieldIs, Value: IsWorkLog, Pos: 12},
+		}},
+		{"date", "created:2025-01-02", []Term{
+			{Field: FieldCreated, Value: "2025-01-02", From: day(2025, 1, 2), To: day(2025, 1, 3)},
+		}},
+		{"date after", "created:>2025-01-02", []Term{
+			{Field: FieldCreated, Value: ">2025-01-02", From: day(2025, 1, 3)},
+		}},
+		{"date from", "created:>=2025-01-02", []Term{
+			{Field: FieldCreated, Value: ">=2025-01-02", From: day(2025, 1, 2)},
+		}},
+		{"date before", "updated:<2025-01-02", []Term{
+			{Field: FieldUpdated, Value: "<2025-01-02", To: day(2025, 1, 2)},
+		}},
+		{"date until", "updated:<=2025-01-02", []Term{
+			{Field: FieldUpdated, Value: "<=2025-01-02", To: day(2025, 1, 3)},
+		}},
+		{"today", "updated:today", []Term{
+			{Field: FieldUpdated, Value: "today", From: day(2025, 6, 15), To: day(2025, 6, 16)},
+		}},
+		{"yesterday", "updated:yesterday", []Term{
+			{Field: FieldUpdated, Value: "yesterday", From: day(2025, 6, 14), To: day(2025, 6, 15)},
+		}},
+		{"less than an age", "updated:<7d", []Term{
+			{Field: FieldUpdated, Value: "<7d", From: now.AddDate(0, 0, -7).Add(time.Nanosecond)},
+		}},
+		{"more than an age", "updated:>2w", []Term{
+			{Field: FieldUpdated, Value: ">2w", To: now.AddDate(0, 0, -14)},
+		}},
+		{"day of an age", "created:1d", []Term{
+			{Field: FieldCreated, Value: "1d", From: day(2025, 6, 14), To: day(2025, 6, 15)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.input, now)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if len(q.Terms) != len(tt.terms) {
+				t.Fatalf("Parse(%q) = %+v, expected %+v", tt.input, q.Terms, tt.terms)
+			}
+			for i, term := range q.Terms {
+				expected := tt.terms[i]
+				if term.Field != expected.Field || term.Value != expected.Value || term.Negated != expected.Negated ||
+					term.Pos != expected.Pos || !term.From.Equal(expected.From) || !term.To.Equal(expected.To) {
+					t.Errorf("term %d of %q = %+v, expected %+v", i, tt.input, term, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+		len   int
+	}{
+		{"foo:bar", 0, 3},
+		{"a -size:1", 3, 4},
+		{`"unterminated`, 0, 13},
+		{`ab"cd"`, 2, 1},
+		{`""`, 0, 2},
+		{"tag:", 0, 4},
+		{"is:draft", 3, 5},
+		{"created:2025-13-01", 8, 10},
+		{"updated:<7x", 8, 3},
+		{"created:>=soon", 8, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input, now)
+			var queryErr *Error
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("Parse(%q) = %v, expected a query error", tt.input, err)
+			}
+			if queryErr.Pos != tt.pos || queryErr.Len != tt.len {
+				t.Errorf("Parse(%q) points to %d+%d, expected %d+%d: %v", tt.input, queryErr.Pos, queryErr.Len, tt.pos, tt.len, err)
+			}
+		})
+	}
+}
+
+func TestErrorPointer(t *testing.T) {
+	_, err := Parse(`é foo:bar`, now)
+	var queryErr *Error
+	if !errors.As(err, &queryErr) {
+		t.Fatalf("expected a query error, got %v", err)
+	}
+	if pointer := queryErr.Pointer(); pointer != "  ^~~" {
+		t.Errorf("Pointer() = %q", pointer)
+	}
+}
+
+// testNotes is the vault searched by the tests of Match
+func testNotes() []model.Note {
+	content := func(s string) *string { return &s }
+	return []model.Note{
+		{
+			NoteID: "deploy", Title: "Deploy checklist", Content: content("Run the migrations\nthen restart"),
+			Tags: []string{"work/infra"}, IsFavorite: true,
+			CreatedAt: day(2025, 1, 2).Add(10 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour),
+		},
+		{
+			NoteID: "journal", Title: "2025-06-14", Content: content("Met Alice about the deploy"),
+			Tags: []string{"journal"}, IsWorkLog: true,
+			CreatedAt: day(2025, 6, 14).Add(9 * time.Hour), UpdatedAt: day(2025, 6, 14).Add(18 * time.Hour),
+		},
+		{
+			NoteID: "archive", Title: "Old ideas", Content: content("Nothing about infra"),
+			Tags: []string{"Archived", "workshop"}, Aliases: []string{"Brainstorm"}, IsPublic: true,
+			CreatedAt: day(2024, 3, 1), UpdatedAt: day(2024, 12, 31).Add(23 * time.Hour),
+		},
+		{
+			NoteID: "inbox", Title: "Inbox", Content: content(""),
+			CreatedAt: day(2025, 6, 15).Add(8 * time.Hour), UpdatedAt: day(2025, 6, 15).Add(8 * time.Hour),
+		},
+	}
+}
+
+// matchTests are the queries run on testNotes, with the IDs of the notes they match, in order
+var matchTests = []struct {
+	query string
+	ids   []string
+}{
+	{"", []string{"deploy", "journal", "archive", "inbox"}},
+	{"deploy", []string{"deploy", "journal"}},
+	{"DEPLOY", []string{"deploy", "journal"}},
+	{`"the migrations"`, []string{"deploy"}},
+	{`"migrations then"`, nil},
+	{"brainstorm", []string{"archive"}},
+	{"title:deploy", []string{"deploy"}},
+	{"content:deploy", []string{"journal"}},
+	{"-deploy", []string{"archive", "inbox"}},
+	{"deploy -is:fav", []string{"journal"}},
+	{"-is:fav -is:worklog", []string{"archive", "inbox"}},
+	{"tag:work", []string{"deploy"}},
+	{"tag:work/infra", []string{"deploy"}},
+	{"tag:wor", nil},
+	{"tag:archived", []string{"archive"}},
+	{"-tag:archived", []string{"deploy", "journal", "inbox"}},
+	{"is:untagged", []string{"inbox"}},
+	{"is:public", []string{"archive"}},
+	{"is:worklog deploy", []string{"journal"}},
+	{"created:2025-06-14", []string{"journal"}},
+	{"created:<2025-01-02", []string{"archive"}},
+	{"created:<=2025-01-02", []string{"deploy", "archive"}},
+	{"created:>2025-01-02", []string{"journal", "inbox"}},
+	{"created:>=2025-01-02", []string{"deploy", "journal", "inbox"}},
+	{"updated:today", []string{"deploy", "inbox"}},
+	{"updated:yesterday", []string{"journal"}},
+	{"updated:<1d", []string{"deploy", "journal", "inbox"}},
+	{"updated:>1d", []string{"archive"}},
+	{"updated:>=5m", []string{"archive"}},
+	{"created:>=2025-01-01 -tag:journal -is:untagged", []string{"deploy"}},
+}
+
+func TestMatch(t *testing.T) {
+	notes := testNotes()
+	for _, tt := range matchTests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query, now)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.query, err)
+			}
+			ids := []string{}
+			for _, note := range q.Filter(notes) {
+				ids = append(ids, note.NoteID)
+			}
+			if !equal(ids, tt.ids) {
+				t.Errorf("%q matches %v, expected %v", tt.query, ids, tt.ids)
+			}
+		})
+	}
+}
+
+func TestMatchWithoutContent(t *testing.T) {
+	note := model.Note{Title: "Deploy"}
+	for query, expected := range map[string]bool{"deploy": true, "content:deploy": false, "-content:deploy": true} {
+		q, err := Parse(query, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Match(note) != expected {
+			t.Errorf("%q matches a note without content: %v, expected %v", query, !expected, expected)
+		}
+	}
+}
+
+func TestIsPlainText(t *testing.T) {
+	for query, expected := range map[string]bool{"deploy infra": true, "": true, `"deploy"`: false, "-deploy": false, "tag:x": false} {
+		q, err := Parse(query, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.IsPlainText() != expected {
+			t.Errorf("IsPlainText(%q) = %v", query, !expected)
+		}
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
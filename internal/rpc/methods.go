@@ -0,0 +1,481 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"merlion/internal/links"
+	"merlion/internal/model"
+	"merlion/internal/query"
+	"merlion/internal/templates"
+	"merlion/internal/vault/clientError"
+)
+
+const defaultLimit = 50
+
+var methods = map[string]handler{
+	"list":         list,
+	"get":          get,
+	"create":       create,
+	"update":       update,
+	"delete":       remove,
+	"search":       search,
+	"complete":     complete,
+	"tags":         tags,
+	"resolve-link": resolveLink,
+	"backlinks":    backlinks,
+	"vaults":       vaults,
+	"switch-vault": switchVault,
+}
+
+// decode unmarshals the params, missing params are accepted for methods without required fields
+func decode(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return newError(CodeInvalidParams, "invalid params: "+err.Error())
+	}
+	return nil
+}
+
+func metadata(note model.Note) model.Note {
+	note.Content = nil
+	return note
+}
+
+// findNote returns the note with its content, found by ID then by title
+func (s *Server) findNote(ref string) (*model.Note, error) {
+	if strings.TrimSpace(ref) == "" {
+		return nil, newError(CodeInvalidParams, "missing note")
+	}
+	note := s.manager.SearchByID(ref)
+	if note == nil {
+		note = s.manager.SearchByTitle(ref)
+	}
+	if note == nil {
+		return nil, fmt.Errorf("%w: %s", clientError.ErrNoteNotFound, ref)
+	}
+	return s.manager.GetFullNote(note.NoteID)
+}
+
+type noteParams struct {
+	Note string `json:"note"` // ID or title
+}
+
+type listParams struct {
+	Tag      string `json:"tag"`
+	Favorite bool   `json:"favorite"`
+	WorkLog  bool   `json:"worklog"`
+	Query    string `json:"query"`
+}
+
+func list(s *Server, params json.RawMessage) (any, error) {
+	var p listParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	candidates := s.manager.Notes
+	if strings.TrimSpace(p.Query) != "" {
+		q, err := query.Parse(p.Query, time.Now())
+		if err != nil {
+			return nil, queryError(err)
+		}
+		if candidates, err = s.manager.Query(q); err != nil {
+			return nil, err
+		}
+	}
+	notes := []model.Note{}
+	for _, note := range candidates {
+		if p.Tag != "" && !query.HasTag(note, p.Tag) {
+			continue
+		}
+		if (p.Favorite && !note.IsFavorite) || (p.WorkLog && !note.IsWorkLog) {
+			continue
+		}
+		notes = append(notes, metadata(note))
+	}
+	return notes, nil
+}
+
+// queryError returns an invalid params error, with the position of the error in the query as data
+func queryError(err error) error {
+	rpcErr := newError(CodeInvalidParams, "invalid query: "+err.Error())
+	var parseErr *query.Error
+	if errors.As(err, &parseErr) {
+		rpcErr.Data = map[string]int{"column": parseErr.Pos + 1, "length": parseErr.Len}
+	}
+	return rpcErr
+}
+
+func get(s *Server, params json.RawMessage) (any, error) {
+	var p noteParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	return s.findNote(p.Note)
+}
+
+type createParams struct {
+	Title    string   `json:"title"`
+	Content  *string  `json:"content"`
+	Tags     []string `json:"tags"`
+	Favorite *bool    `json:"favorite"`
+	WorkLog  *bool    `json:"worklog"`
+	Template string   `json:"template"`
+}
+
+func create(s *Server, params json.RawMessage) (any, error) {
+	var p createParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	req := model.CreateNoteRequest{Title: p.Title}
+	if p.Template != "" {
+		dir, err := s.manager.TemplatesDir()
+		if err != nil {
+			return nil, err
+		}
+		template, err := templates.Load(dir, p.Template)
+		if err != nil {
+			return nil, newError(CodeInvalidParams, err.Error())
+		}
+		req = template.Apply(p.Title, time.Now())
+	}
+	title, err := validateTitle(req.Title)
+	if err != nil {
+		return nil, err
+	}
+	req.Title = title
+	if existing := s.manager.SearchByTitle(req.Title); existing != nil {
+		return nil, newError(CodeConflict, fmt.Sprintf("a note named '%s' already exists", existing.Title))
+	}
+	if p.Content != nil {
+		req.Content = p.Content
+	}
+	if p.Tags != nil {
+		req.Tags = p.Tags
+	}
+	if p.Favorite != nil {
+		req.IsFavorite = p.Favorite
+	}
+	if p.WorkLog != nil {
+		req.IsWorkLog = p.WorkLog
+	}
+
+	note, err := s.manager.CreateNote(req)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyChange(changeCreated, *note, true)
+	return note, nil
+}
+
+// updateParams only changes the fields which are set
+type updateParams struct {
+	Note     string    `json:"note"`
+	Title    *string   `json:"title"`
+	Content  *string   `json:"content"`
+	Tags     *[]string `json:"tags"`
+	Favorite *bool     `json:"favorite"`
+	WorkLog  *bool     `json:"worklog"`
+}
+
+func update(s *Server, params json.RawMessage) (any, error) {
+	var p updateParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	note, err := s.findNote(p.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	req := note.ToCreateRequest()
+	if p.Title != nil {
+		title, err := validateTitle(*p.Title)
+		if err != nil {
+			return nil, err
+		}
+		if existing := s.manager.SearchByTitle(title); existing != nil && existing.NoteID != note.NoteID {
+			return nil, newError(CodeConflict, fmt.Sprintf("a note named '%s' already exists", existing.Title))
+		}
+		req.Title = title
+	}
+	if p.Content != nil {
+		req.Content = p.Content
+	}
+	if p.Tags != nil {
+		req.Tags = *p.Tags
+	}
+	if p.Favorite != nil {
+		req.IsFavorite = p.Favorite
+	}
+	if p.WorkLog != nil {
+		req.IsWorkLog = p.WorkLog
+	}
+
+	oldID := note.NoteID
+	if req.Title == note.Title {
+		updated, err := s.manager.UpdateNote(oldID, req)
+		if err != nil {
+			return nil, err
+		}
+		s.notifyChange(changeUpdated, *updated, true)
+		return updated, nil
+	}
+
+	// A new title rewrites the links to the note, like a rename in the app or with `mv`
+	rename, err := links.PlanRename(s.manager, oldID, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if p.Content != nil {
+		content := rename.RewriteContent(s.manager, *p.Content)
+		req.Content = &content
+	}
+	rename.Update = func(renamed *model.CreateNoteRequest) {
+		title, content := renamed.Title, renamed.Content
+		*renamed = req
+		renamed.Title = title
+		if p.Content == nil {
+			// The content with its own links rewritten
+			renamed.Content = content
+		}
+	}
+	updated, err := rename.Apply(s.manager)
+	if err != nil {
+		return nil, err
+	}
+	if updated.NoteID != oldID {
+		// Files vaults identify the notes by their title
+		delete(s.snapshot, oldID)
+	}
+	s.notifyChange(changeUpdated, *updated, true)
+	for _, edit := range rename.Edits {
+		if edit.Note.NoteID == oldID {
+			continue
+		}
+		if edited := s.manager.SearchByID(edit.Note.NoteID); edited != nil {
+			s.notifyChange(changeUpdated, *edited, true)
+		}
+	}
+	return updated, nil
+}
+
+// validateTitle returns the trimmed title, refused when empty or on several lines
+func validateTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", newError(CodeInvalidParams, "empty title")
+	}
+	if strings.IndexFunc(title, unicode.IsControl) != -1 {
+		return "", newError(CodeInvalidParams, fmt.Sprintf("invalid title: %q", title))
+	}
+	return title, nil
+}
+
+func remove(s *Server, params json.RawMessage) (any, error) {
+	var p noteParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	note, err := s.findNote(p.Note)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.manager.DeleteNote(note.NoteID); err != nil {
+		return nil, err
+	}
+	s.notifyChange(changeDeleted, *note, true)
+	return map[string]string{"deleted": note.NoteID}, nil
+}
+
+type searchParams struct {
+	Query   string `json:"query"`
+	Content *bool  `json:"content"` // Search in the content too, default true
+	Limit   int    `json:"limit"`
+}
+
+type Match struct {
+	Line int    `json:"line"` // 1-based, 0 for a match in the title
+	Text string `json:"text"`
+}
+
+type SearchResult struct {
+	Note    model.Note `json:"note"`
+	Matches []Match    `json:"matches"`
+}
+
+func search(s *Server, params json.RawMessage) (any, error) {
+	var p searchParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	needle := strings.ToLower(strings.TrimSpace(p.Query))
+	if needle == "" {
+		return nil, newError(CodeInvalidParams, "missing query")
+	}
+	if p.Limit <= 0 {
+		p.Limit = defaultLimit
+	}
+	withContent := p.Content == nil || *p.Content
+
+	// The stores which can query their notes search the content themselves,
+	// the others filter the cached notes, without loading each one from the store
+	term := query.Term{Field: query.FieldText, Value: needle}
+	if !withContent {
+		term.Field = query.FieldTitle
+	}
+	notes, err := s.manager.Query(query.Query{Raw: p.Query, Terms: []query.Term{term}})
+	if err != nil {
+		return nil, err
+	}
+
+	// Matches in the titles first
+	titleResults, contentResults := []SearchResult{}, []SearchResult{}
+	for _, note := range notes {
+		result := SearchResult{Note: metadata(note), Matches: []Match{}}
+		if strings.Contains(strings.ToLower(note.Title), needle) {
+			result.Matches = append(result.Matches, Match{Line: 0, Text: note.Title})
+		}
+		if withContent && note.Content != nil {
+			for i, line := range strings.Split(*note.Content, "\n") {
+				if strings.Contains(strings.ToLower(line), needle) {
+					result.Matches = append(result.Matches, Match{Line: i + 1, Text: strings.TrimSpace(line)})
+				}
+			}
+		}
+
+		switch {
+		case len(result.Matches) > 0 && result.Matches[0].Line == 0:
+			titleResults = append(titleResults, result)
+		case len(result.Matches) > 0:
+			contentResults = append(contentResults, result)
+		}
+	}
+
+	results := append(titleResults, contentResults...)
+	if len(results) > p.Limit {
+		results = results[:p.Limit]
+	}
+	return results, nil
+}
+
+type completeParams struct {
+	Prefix string `json:"prefix"`
+	Limit  int    `json:"limit"`
+}
+
+// complete returns the titles starting with the prefix, then the ones containing it
+func complete(s *Server, params json.RawMessage) (any, error) {
+	var p completeParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Limit <= 0 {
+		p.Limit = defaultLimit
+	}
+	prefix := strings.ToLower(strings.TrimSpace(p.Prefix))
+
+	starts, contains := []string{}, []string{}
+	for _, note := range s.manager.Notes {
+		title := strings.ToLower(note.Title)
+		switch {
+		case strings.HasPrefix(title, prefix):
+			starts = append(starts, note.Title)
+		case strings.Contains(title, prefix):
+			contains = append(contains, note.Title)
+		}
+	}
+	sort.Strings(starts)
+	sort.Strings(contains)
+	titles := append(starts, contains...)
+	if len(titles) > p.Limit {
+		titles = titles[:p.Limit]
+	}
+	return titles, nil
+}
+
+func tags(s *Server, params json.RawMessage) (any, error) {
+	result := s.manager.GetTags()
+	sort.Strings(result)
+	return result, nil
+}
+
+type resolveLinkParams struct {
+	Link string `json:"link"` // [[Title#Heading|Alias]], brackets are optional
+}
+
+type ResolvedLink struct {
+	links.Link
+	Note *model.Note `json:"note"` // Null when the note doesn't exist
+}
+
+func resolveLink(s *Server, params json.RawMessage) (any, error) {
+	var p resolveLinkParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	link := links.ParseLink(p.Link)
+	if link.Target == "" {
+		return nil, newError(CodeInvalidParams, "invalid link: "+p.Link)
+	}
+	result := ResolvedLink{Link: link}
+	if note := links.Resolve(s.manager.Notes, link.Target); note != nil {
+		meta := metadata(*note)
+		result.Note = &meta
+	}
+	return result, nil
+}
+
+func backlinks(s *Server, params json.RawMessage) (any, error) {
+	var p noteParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	note, err := s.findNote(p.Note)
+	if err != nil {
+		return nil, err
+	}
+	return links.Backlinks(s.manager, note.NoteID)
+}
+
+type VaultsResult struct {
+	Active string   `json:"active"`
+	Vaults []string `json:"vaults"`
+}
+
+func vaults(s *Server, params json.RawMessage) (any, error) {
+	return VaultsResult{Active: s.manager.Name, Vaults: s.manager.StoreNames()}, nil
+}
+
+type switchVaultParams struct {
+	Name string `json:"name"`
+}
+
+func switchVault(s *Server, params json.RawMessage) (any, error) {
+	var p switchVaultParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	previous := s.manager.Name
+	if err := s.manager.UseStore(p.Name); err != nil {
+		return nil, newError(CodeInvalidParams, err.Error())
+	}
+	if _, err := s.manager.ListNoteMetadata(); err != nil {
+		// Stay on a usable vault
+		s.manager.UseStore(previous)
+		s.manager.ListNoteMetadata()
+		return nil, fmt.Errorf("failed to list notes of %s: %w", p.Name, err)
+	}
+	// The changes are tracked on the new vault
+	s.takeSnapshot()
+	return VaultsResult{Active: s.manager.Name, Vaults: s.manager.StoreNames()}, nil
+}
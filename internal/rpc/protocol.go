@@ -0,0 +1,59 @@
+// Package rpc implements a JSON-RPC 2.0 server exposing the notes of a vault
+// to editor integrations. Messages are exchanged as one JSON document per line,
+// see docs/rpc.md for the methods and their schema
+package rpc
+
+import "encoding/json"
+
+const version = "2.0"
+
+// Error codes, the standard ones followed by the application ones
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	CodeNoteNotFound = -32001
+	CodeConflict     = -32002
+)
+
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // Absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(code int, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// isNotification reports if the request doesn't expect a response
+func (r Request) isNotification() bool {
+	return len(r.ID) == 0
+}
@@ -0,0 +1,266 @@
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"merlion/internal/model"
+	"merlion/internal/vault"
+	"merlion/internal/vault/clientError"
+	"merlion/internal/vault/cloud"
+
+	"github.com/charmbracelet/log"
+)
+
+// Maximum size of a message, notes are sent with their content
+const maxMessageSize = 64 * 1024 * 1024
+
+type handler func(s *Server, params json.RawMessage) (any, error)
+
+// Default interval between the polls of a Cloud vault, each one lists the notes through the API
+const DefaultCloudWatchInterval = time.Minute
+
+// Server handles the requests of a single client, one at a time
+// The vault is polled every WatchInterval to notify the client of the changes
+// made outside of the server (TUI, Obsidian, other devices).
+// Cloud vaults are polled every CloudWatchInterval, never when it is 0
+type Server struct {
+	manager            *vault.Manager
+	WatchInterval      time.Duration
+	CloudWatchInterval time.Duration
+	lastPoll           time.Time
+
+	mu       sync.Mutex            // Protects the manager and the snapshot
+	snapshot map[string]model.Note // Metadata of the notes at the last poll
+	// Notes changed by the client, already notified, skipped by the next poll
+	ownChanges map[string]bool
+
+	writeMu sync.Mutex
+	out     io.Writer
+}
+
+// NewServer returns a server on the manager, its notes must already be loaded
+func NewServer(manager *vault.Manager) *Server {
+	s := &Server{
+		manager:            manager,
+		CloudWatchInterval: DefaultCloudWatchInterval,
+		ownChanges:         make(map[string]bool),
+	}
+	s.takeSnapshot()
+	return s
+}
+
+// Serve reads the requests from r and writes the responses and notifications to w,
+// until r is closed
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.out = w
+
+	done := make(chan struct{})
+	defer close(done)
+	if s.WatchInterval > 0 {
+		go s.watch(done)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if response := s.handleMessage(line); response != nil {
+			s.write(response)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read requests: %w", err)
+	}
+	return nil
+}
+
+// handleMessage returns the response of a request or a batch, nil when nothing must be sent
+func (s *Server) handleMessage(line []byte) any {
+	if line[0] != '[' {
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			return Response{JSONRPC: version, Error: newError(CodeParseError, "parse error: "+err.Error())}
+		}
+		return s.handle(req)
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(line, &batch); err != nil {
+		return Response{JSONRPC: version, Error: newError(CodeParseError, "parse error: "+err.Error())}
+	}
+	if len(batch) == 0 {
+		return Response{JSONRPC: version, Error: newError(CodeInvalidRequest, "empty batch")}
+	}
+	responses := []*Response{}
+	for _, raw := range batch {
+		var req Request
+		if err := json.Unmarshal(raw, &req); err != nil {
+			responses = append(responses, &Response{JSONRPC: version, Error: newError(CodeInvalidRequest, "invalid request")})
+			continue
+		}
+		if response := s.handle(req); response != nil {
+			responses = append(responses, response)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+func (s *Server) handle(req Request) *Response {
+	response := &Response{JSONRPC: version, ID: req.ID}
+	if req.JSONRPC != version || req.Method == "" {
+		response.Error = newError(CodeInvalidRequest, "invalid request")
+		return response
+	}
+
+	method, ok := methods[req.Method]
+	if !ok {
+		response.Error = newError(CodeMethodNotFound, "method not found: "+req.Method)
+	} else {
+		s.mu.Lock()
+		result, err := method(s, req.Params)
+		s.mu.Unlock()
+		if err != nil {
+			response.Error = toError(err)
+		} else {
+			response.Result = result
+		}
+	}
+
+	if req.isNotification() {
+		return nil
+	}
+	return response
+}
+
+// toError converts the errors of the handlers in JSON-RPC errors
+func toError(err error) *Error {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	if errors.Is(err, clientError.ErrNoteNotFound) {
+		return newError(CodeNoteNotFound, err.Error())
+	}
+	return newError(CodeInternalError, err.Error())
+}
+
+func (s *Server) write(message any) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Errorf("Failed to encode RPC message: %v", err)
+		return
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := s.out.Write(append(data, '\n')); err != nil {
+		log.Errorf("Failed to write RPC message: %v", err)
+	}
+}
+
+// ChangeEvent is the payload of the `changed` notification
+type ChangeEvent struct {
+	Type string     `json:"type"` // created, updated or deleted
+	Note model.Note `json:"note"` // Without content
+}
+
+const (
+	changeCreated = "created"
+	changeUpdated = "updated"
+	changeDeleted = "deleted"
+)
+
+// notifyChange sends the `changed` notification, expects s.mu to be held
+func (s *Server) notifyChange(changeType string, note model.Note, own bool) {
+	note.Content = nil
+	if own {
+		s.ownChanges[note.NoteID] = true
+	}
+	if changeType == changeDeleted {
+		delete(s.snapshot, note.NoteID)
+	} else {
+		s.snapshot[note.NoteID] = note
+	}
+	s.write(Notification{
+		JSONRPC: version,
+		Method:  "changed",
+		Params:  ChangeEvent{Type: changeType, Note: note},
+	})
+}
+
+// takeSnapshot records the last update of the notes just listed, expects s.mu to be held
+func (s *Server) takeSnapshot() {
+	s.snapshot = make(map[string]model.Note, len(s.manager.Notes))
+	for _, note := range s.manager.Notes {
+		note.Content = nil
+		s.snapshot[note.NoteID] = note
+	}
+	s.ownChanges = make(map[string]bool)
+	s.lastPoll = time.Now()
+}
+
+func (s *Server) watch(done chan struct{}) {
+	ticker := time.NewTicker(s.WatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			s.poll()
+		}
+	}
+}
+
+// poll reloads the notes of the vault and notifies the changes made outside of the server
+func (s *Server) poll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.manager.StoreType() == cloud.Type &&
+		(s.CloudWatchInterval <= 0 || time.Since(s.lastPoll) < s.CloudWatchInterval) {
+		return
+	}
+	s.lastPoll = time.Now()
+
+	previous := s.snapshot
+	if _, err := s.manager.ListNoteMetadata(); err != nil {
+		log.Errorf("Failed to poll the vault: %v", err)
+		return
+	}
+
+	seen := make(map[string]bool, len(s.manager.Notes))
+	for _, note := range s.manager.Notes {
+		seen[note.NoteID] = true
+		last, exists := previous[note.NoteID]
+		switch {
+		case s.ownChanges[note.NoteID]:
+			// Already notified, the store may report a slightly different update time
+			delete(s.ownChanges, note.NoteID)
+			note.Content = nil
+			s.snapshot[note.NoteID] = note
+		case !exists:
+			s.notifyChange(changeCreated, note, false)
+		case !last.UpdatedAt.Equal(note.UpdatedAt):
+			s.notifyChange(changeUpdated, note, false)
+		}
+	}
+	for id, note := range previous {
+		if !seen[id] {
+			delete(s.ownChanges, id)
+			s.notifyChange(changeDeleted, note, false)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"merlion/internal/config"
+	"merlion/internal/vault"
+	"merlion/internal/vault/files"
+	"merlion/internal/vault/sqlite"
+
+	"github.com/charmbracelet/log"
+)
+
+// client drives the server through pipes, like an editor plugin would
+type client struct {
+	t             *testing.T
+	in            *io.PipeWriter
+	messages      chan json.RawMessage
+	notifications []ChangeEvent
+	nextID        int
+}
+
+func newClient(t *testing.T, server *Server) (*client, chan error) {
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		err := server.Serve(inReader, outWriter)
+		outWriter.Close()
+		done <- err
+	}()
+
+	c := &client{t: t, in: inWriter, messages: make(chan json.RawMessage, 100)}
+	go func() {
+		scanner := bufio.NewScanner(outReader)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+		for scanner.Scan() {
+			if !json.Valid(scanner.Bytes()) {
+				t.Errorf("invalid message from the server: %s", scanner.Text())
+				continue
+			}
+			c.messages <- json.RawMessage(append([]byte{}, scanner.Bytes()...))
+		}
+		close(c.messages)
+	}()
+	return c, done
+}
+
+func (c *client) send(line string) {
+	c.t.Helper()
+	if _, err := io.WriteString(c.in, line+"\n"); err != nil {
+		c.t.Fatalf("failed to write request: %v", err)
+	}
+}
+
+// next returns the next message which isn't a notification
+func (c *client) next() json.RawMessage {
+	c.t.Helper()
+	for {
+		select {
+		case raw, ok := <-c.messages:
+			if !ok {
+				c.t.Fatal("the server closed the connection")
+			}
+			var message map[string]json.RawMessage
+			if json.Unmarshal(raw, &message) != nil {
+				// Batch response
+				return raw
+			}
+			if _, isNotification := message["method"]; isNotification {
+				var event ChangeEvent
+				if err := json.Unmarshal(message["params"], &event); err != nil {
+					c.t.Fatalf("invalid notification: %v", err)
+				}
+				c.notifications = append(c.notifications, event)
+				continue
+			}
+			return raw
+		case <-time.After(5 * time.Second):
+			c.t.Fatal("timeout waiting for a response")
+		}
+	}
+}
+
+// call sends a request and decodes its result, the error code is returned on failure
+func (c *client) call(method string, params any, result any) int {
+	c.t.Helper()
+	c.nextID++
+	request, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.send(string(request))
+
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(c.next(), &response); err != nil {
+		c.t.Fatalf("unexpected response to %s: %v", method, err)
+	}
+	var id int
+	if err := json.Unmarshal(response["id"], &id); err != nil || id != c.nextID {
+		c.t.Fatalf("unexpected response id %s, expected %d", response["id"], c.nextID)
+	}
+	if raw, ok := response["error"]; ok {
+		var rpcErr Error
+		if err := json.Unmarshal(raw, &rpcErr); err != nil {
+			c.t.Fatal(err)
+		}
+		return rpcErr.Code
+	}
+	if result != nil {
+		if err := json.Unmarshal(response["result"], result); err != nil {
+			c.t.Fatalf("failed to decode the result of %s: %v", method, err)
+		}
+	}
+	return 0
+}
+
+// waitNotification returns the first notification of this type
+func (c *client) waitNotification(changeType string, title string) ChangeEvent {
+	c.t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		for i, event := range c.notifications {
+			if event.Type == changeType && event.Note.Title == title {
+				c.notifications = append(c.notifications[:i], c.notifications[i+1:]...)
+				return event
+			}
+		}
+		select {
+		case raw, ok := <-c.messages:
+			if !ok {
+				c.t.Fatal("the server closed the connection")
+			}
+			var message Notification
+			if err := json.Unmarshal(raw, &message); err != nil || message.Method != "changed" {
+				c.t.Fatalf("expected a notification, got %s", raw)
+			}
+			var event ChangeEvent
+			data, _ := json.Marshal(message.Params)
+			if err := json.Unmarshal(data, &event); err != nil {
+				c.t.Fatalf("invalid notification: %v", err)
+			}
+			c.notifications = append(c.notifications, event)
+		case <-timeout:
+			c.t.Fatalf("timeout waiting for the %s notification of %s", changeType, title)
+		}
+	}
+}
+
+func newTestManager(t *testing.T) (*vault.Manager, string) {
+	t.Helper()
+	log.SetOutput(io.Discard)
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("MERLION_DB_PATH", filepath.Join(tmp, "notes.db"))
+
+	filesDir := filepath.Join(tmp, "obsidian")
+	cfg := &config.UserConfig{Vaults: []config.Vault{
+		{Provider: sqlite.Type, Name: sqlite.Name},
+		{Provider: files.Type, Name: filesDir, Path: filesDir},
+	}}
+	manager := vault.NewManager(cfg, nil, false)
+	if _, err := manager.ListNoteMetadata(); err != nil {
+		t.Fatal(err)
+	}
+	return manager, filesDir
+}
+
+type note struct {
+	NoteID  string   `json:"note_id"`
+	Title   string   `json:"title"`
+	Content *string  `json:"content"`
+	Tags    []string `json:"tags"`
+}
+
+func TestServerThroughPipes(t *testing.T) {
+	manager, filesDir := newTestManager(t)
+	server := NewServer(manager)
+	server.WatchInterval = 20 * time.Millisecond
+	c, done := newClient(t, server)
+
+	// Create
+	var roadmap, meeting note
+	if code := c.call("create", map[string]any{"title": "Roadmap", "content": "# Roadmap\nShip the API", "tags": []string{"work/plan"}}, &roadmap); code != 0 {
+		t.Fatalf("create failed with code %d", code)
+	}
+	c.waitNotification(changeCreated, "Roadmap")
+	if code := c.call("create", map[string]any{"title": "Meeting", "content": "Discussed [[Roadmap#API|the roadmap]]\nand [[Missing]]"}, &meeting); code != 0 {
+		t.Fatalf("create failed with code %d", code)
+	}
+	c.waitNotification(changeCreated, "Meeting")
+	if code := c.call("create", map[string]any{"title": "roadmap"}, nil); code != CodeConflict {
+		t.Errorf("creating a duplicated title returned %d, expected %d", code, CodeConflict)
+	}
+
+	// List & get
+	var notes []note
+	c.call("list", nil, &notes)
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 notes, got %d", len(notes))
+	}
+	for _, n := range notes {
+		if n.Content != nil {
+			t.Errorf("list should not return the content of %s", n.Title)
+		}
+	}
+	c.call("list", map[string]any{"tag": "work"}, &notes)
+	if len(notes) != 1 || notes[0].Title != "Roadmap" {
+		t.Errorf("unexpected notes for the tag work: %+v", notes)
+	}
+	c.call("list", map[string]any{"query": `-tag:work "the roadmap"`}, &notes)
+	if len(notes) != 1 || notes[0].Title != "Meeting" {
+		t.Errorf("unexpected notes for the query: %+v", notes)
+	}
+	if code := c.call("list", map[string]any{"query": "unknown:field"}, nil); code != CodeInvalidParams {
+		t.Errorf("invalid query returned %d, expected %d", code, CodeInvalidParams)
+	}
+	var got note
+	c.call("get", map[string]any{"note": "meeting"}, &got)
+	if got.NoteID != meeting.NoteID || got.Content == nil {
+		t.Errorf("get by title returned %+v", got)
+	}
+	if code := c.call("get", map[string]any{"note": "Unknown"}, nil); code != CodeNoteNotFound {
+		t.Errorf("get of an unknown note returned %d, expected %d", code, CodeNoteNotFound)
+	}
+
+	// Update only changes the provided fields
+	var updated note
+	c.call("update", map[string]any{"note": roadmap.NoteID, "content": "# Roadmap\nShip the API\nThen the plugins"}, &updated)
+	if updated.Title != "Roadmap" || len(updated.Tags) != 1 || *updated.Content != "# Roadmap\nShip the API\nThen the plugins" {
+		t.Errorf("unexpected updated note: %+v", updated)
+	}
+	c.waitNotification(changeUpdated, "Roadmap")
+
+	// Search, completion and tags
+	var results []SearchResult
+	c.call("search", map[string]any{"query": "plugins"}, &results)
+	if len(results) != 1 || results[0].Note.Title != "Roadmap" || results[0].Matches[0].Line != 3 {
+		t.Errorf("unexpected search results: %+v", results)
+	}
+	var titles []string
+	c.call("complete", map[string]any{"prefix": "mee"}, &titles)
+	if len(titles) != 1 || titles[0] != "Meeting" {
+		t.Errorf("unexpected completion: %v", titles)
+	}
+	var tags []string
+	c.call("tags", nil, &tags)
+	if len(tags) != 1 || tags[0] != "work/plan" {
+		t.Errorf("unexpected tags: %v", tags)
+	}
+
+	// Links
+	var resolved ResolvedLink
+	c.call("resolve-link", map[string]any{"link": "[[roadmap#API|the roadmap]]"}, &resolved)
+	if resolved.Note == nil || resolved.Note.NoteID != roadmap.NoteID || resolved.Heading != "API" || resolved.Alias != "the roadmap" {
+		t.Errorf("unexpected resolved link: %+v", resolved)
+	}
+	c.call("resolve-link", map[string]any{"link": "Missing"}, &resolved)
+	if resolved.Note != nil {
+		t.Errorf("a missing note should resolve to null, got %+v", resolved.Note)
+	}
+	var references []struct {
+		Note note   `json:"note"`
+		Text string `json:"text"`
+	}
+	c.call("backlinks", map[string]any{"note": "Roadmap"}, &references)
+	if len(references) != 1 || references[0].Note.Title != "Meeting" || references[0].Text != "Discussed [[Roadmap#API|the roadmap]]" {
+		t.Errorf("unexpected backlinks: %+v", references)
+	}
+
+	// A new title rewrites the links to the note
+	if code := c.call("update", map[string]any{"note": roadmap.NoteID, "title": "Plan\nB"}, nil); code != CodeInvalidParams {
+		t.Errorf("a title on several lines returned %d, expected %d", code, CodeInvalidParams)
+	}
+	c.call("update", map[string]any{"note": roadmap.NoteID, "title": " Plan "}, &updated)
+	if updated.Title != "Plan" || len(updated.Tags) != 1 {
+		t.Errorf("unexpected renamed note: %+v", updated)
+	}
+	c.waitNotification(changeUpdated, "Plan")
+	c.waitNotification(changeUpdated, "Meeting")
+	c.call("get", map[string]any{"note": "Meeting"}, &got)
+	if got.Content == nil || *got.Content != "Discussed [[Plan#API|the roadmap]]\nand [[Missing]]" {
+		t.Errorf("the link of Meeting is not rewritten: %+v", got)
+	}
+
+	// Delete
+	c.call("delete", map[string]any{"note": "Meeting"}, nil)
+	c.waitNotification(changeDeleted, "Meeting")
+	c.call("list", nil, &notes)
+	if len(notes) != 1 {
+		t.Errorf("expected 1 note after the deletion, got %d", len(notes))
+	}
+
+	// Protocol errors
+	c.send("not json")
+	var parseError Response
+	if err := json.Unmarshal(c.next(), &parseError); err != nil || string(parseError.ID) != "null" || parseError.Error == nil || parseError.Error.Code != CodeParseError {
+		t.Errorf("expected a parse error, got %+v", parseError)
+	}
+	if code := c.call("unknown", nil, nil); code != CodeMethodNotFound {
+		t.Errorf("unknown method returned %d, expected %d", code, CodeMethodNotFound)
+	}
+	if code := c.call("get", "not an object", nil); code != CodeInvalidParams {
+		t.Errorf("invalid params returned %d, expected %d", code, CodeInvalidParams)
+	}
+	c.send(`[{"jsonrpc":"2.0","id":"a","method":"vaults"},{"jsonrpc":"2.0","method":"tags"}]`)
+	// The notification of the batch has no response
+	var batch []Response
+	if err := json.Unmarshal(c.next(), &batch); err != nil || len(batch) != 1 || string(batch[0].ID) != `"a"` {
+		t.Errorf("unexpected batch response: %+v", batch)
+	}
+
+	// Switch to the Obsidian vault, and detect the changes made by other applications
+	var switched VaultsResult
+	if code := c.call("switch-vault", map[string]any{"name": "obsidian"}, &switched); code != 0 {
+		t.Fatalf("switch-vault failed with code %d", code)
+	}
+	if switched.Active != filesDir || len(switched.Vaults) != 2 {
+		t.Errorf("unexpected vaults: %+v", switched)
+	}
+	if err := os.WriteFile(filepath.Join(filesDir, "External.md"), []byte("Written by Obsidian"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c.waitNotification(changeCreated, "External")
+	c.call("get", map[string]any{"note": "External"}, &got)
+	if got.Content == nil || *got.Content != "Written by Obsidian" {
+		t.Errorf("unexpected external note: %+v", got)
+	}
+
+	c.in.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Serve returned %v", err)
+	}
+}
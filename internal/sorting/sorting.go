@@ -0,0 +1,178 @@
+// Package sorting orders the notes the same way for every store:
+// SQLite returns them by update date, Obsidian vaults in walk order and the cloud in server order
+package sorting
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"merlion/internal/model"
+)
+
+const (
+	ByUpdated = "updated"
+	ByCreated = "created"
+	ByTitle   = "title"
+	// ByProperty sorts on a custom front matter property, notes without it come last
+	ByProperty = "property"
+)
+
+// Fields lists the sort fields, in menu order
+var Fields = []string{ByUpdated, ByCreated, ByTitle, ByProperty}
+
+// Order is the sort of a note list, stored per tab in the user config
+type Order struct {
+	By           string `json:"by"`
+	Property     string `json:"property,omitempty"`
+	Descending   bool   `json:"descending"`
+	PinFavorites bool   `json:"pinFavorites,omitempty"`
+}
+
+// Default returns the order of a tab without config: work logs by creation, the other tabs by update
+func Default(tab string) Order {
+	if tab == "Work Logs" {
+		return Order{By: ByCreated, Descending: true}
+	}
+	return Order{By: ByUpdated, Descending: true}
+}
+
+// Parse reads an order written as `field`, `field:asc|desc` or `property:name[:asc|desc]`
+// Dates are sorted newest first and text alphabetically unless specified
+func Parse(text string) (Order, error) {
+	parts := strings.Split(strings.TrimSpace(text), ":")
+	order := Order{By: strings.ToLower(parts[0])}
+	switch order.By {
+	case ByUpdated, ByCreated:
+		order.Descending = true
+	case ByTitle:
+	case ByProperty:
+		if len(parts) < 2 || parts[1] == "" {
+			return Order{}, fmt.Errorf("missing property name, expected property:<name>")
+		}
+		order.Property = parts[1]
+		parts = parts[1:]
+	default:
+		return Order{}, fmt.Errorf("unknown sort field %q, expected one of %s", parts[0], strings.Join(Fields, ", "))
+	}
+
+	switch {
+	case len(parts) == 1:
+	case len(parts) == 2 && strings.EqualFold(parts[1], "asc"):
+		order.Descending = false
+	case len(parts) == 2 && strings.EqualFold(parts[1], "desc"):
+		order.Descending = true
+	default:
+		return Order{}, fmt.Errorf("invalid sort %q, expected <field>[:asc|desc]", text)
+	}
+	return order, nil
+}
+
+// String returns the order in the format read by Parse
+func (o Order) String() string {
+	direction := "asc"
+	if o.Descending {
+		direction = "desc"
+	}
+	if o.By == ByProperty {
+		return fmt.Sprintf("%s:%s:%s", ByProperty, o.Property, direction)
+	}
+	return o.By + ":" + direction
+}
+
+// Label describes the order in the UI, e.g. "Title ↑"
+func (o Order) Label() string {
+	name := map[string]string{ByUpdated: "Updated", ByCreated: "Created", ByTitle: "Title"}[o.By]
+	if o.By == ByProperty {
+		name = o.Property
+	}
+	arrow := "↑"
+	if o.Descending {
+		arrow = "↓"
+	}
+	return name + " " + arrow
+}
+
+// Sort orders the notes in place. Ties are broken by title then ID, so the
+// result doesn't depend on the order returned by the store
+func Sort(notes []model.Note, order Order) {
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, b := notes[i], notes[j]
+		if order.PinFavorites && a.IsFavorite != b.IsFavorite {
+			return a.IsFavorite
+		}
+
+		var cmp int
+		switch order.By {
+		case ByCreated:
+			cmp = a.CreatedAt.Compare(b.CreatedAt)
+		case ByTitle:
+			cmp = compareText(a.Title, b.Title)
+		case ByProperty:
+			va, okA := a.Properties[order.Property]
+			vb, okB := b.Properties[order.Property]
+			if okA != okB {
+				// Missing values last, whatever the direction
+				return okA
+			}
+			if okA {
+				cmp = compareValues(va, vb)
+			}
+		default:
+			cmp = a.UpdatedAt.Compare(b.UpdatedAt)
+		}
+		if order.Descending {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+
+		if cmp = compareText(a.Title, b.Title); cmp != 0 {
+			return cmp < 0
+		}
+		return a.NoteID < b.NoteID
+	})
+}
+
+func compareText(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// compareValues compares front matter values: numbers, dates, then as text
+func compareValues(a, b any) int {
+	if na, okA := toNumber(a); okA {
+		if nb, okB := toNumber(b); okB {
+			switch {
+			case na < nb:
+				return -1
+			case na > nb:
+				return 1
+			}
+			return 0
+		}
+	}
+	if ta, okA := a.(time.Time); okA {
+		if tb, okB := b.(time.Time); okB {
+			return ta.Compare(tb)
+		}
+	}
+	return compareText(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,104 @@
+package sorting
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"merlion/internal/model"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text  string
+		order Order
+	}{
+		{"updated", Order{By: ByUpdated, Descending: true}},
+		{"created", Order{By: ByCreated, Descending: true}},
+		{"title", Order{By: ByTitle}},
+		{" Title ", Order{By: ByTitle}},
+		{"updated:asc", Order{By: ByUpdated}},
+		{"created:ASC", Order{By: ByCreated}},
+		{"title:desc", Order{By: ByTitle, Descending: true}},
+		{"property:priority", Order{By: ByProperty, Property: "priority"}},
+		{"property:priority:desc", Order{By: ByProperty, Property: "priority", Descending: true}},
+		{"property:Due:asc", Order{By: ByProperty, Property: "Due"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			order, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.text, err)
+			}
+			if order != tt.order {
+				t.Errorf("Parse(%q) = %+v, expected %+v", tt.text, order, tt.order)
+			}
+			if again, err := Parse(order.String()); err != nil || again != order {
+				t.Errorf("Parse(%q) = %+v, %v, expected %+v", order.String(), again, err, order)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{"", "size", "updated:up", "title:asc:desc", "property", "property:", "property:due:sideways"} {
+		if order, err := Parse(text); err == nil {
+			t.Errorf("Parse(%q) = %+v, expected an error", text, order)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	for order, label := range map[Order]string{
+		{By: ByTitle}:                                       "Title ↑",
+		{By: ByUpdated, Descending: true}:                   "Updated ↓",
+		{By: ByProperty, Property: "due", Descending: true}: "due ↓",
+	} {
+		if order.Label() != label {
+			t.Errorf("%+v.Label() = %q, expected %q", order, order.Label(), label)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	notes := []model.Note{
+		{NoteID: "b", Title: "beta", CreatedAt: day(2), UpdatedAt: day(5), Properties: map[string]any{"priority": 2, "due": day(20)}},
+		{NoteID: "a", Title: "Alpha", CreatedAt: day(3), UpdatedAt: day(4), Properties: map[string]any{"priority": "10", "due": day(10)}},
+		{NoteID: "d", Title: "delta", CreatedAt: day(1), UpdatedAt: day(4), IsFavorite: true},
+		{NoteID: "c2", Title: "gamma", CreatedAt: day(3), UpdatedAt: day(1), Properties: map[string]any{"priority": 2.5}},
+		{NoteID: "c1", Title: "Gamma", CreatedAt: day(3), UpdatedAt: day(1), Properties: map[string]any{"priority": "high"}},
+	}
+	tests := []struct {
+		order Order
+		ids   string
+	}{
+		{Order{By: ByUpdated, Descending: true}, "b a d c1 c2"},
+		{Order{By: ByUpdated}, "c1 c2 a d b"},
+		{Order{By: ByCreated, Descending: true}, "a c1 c2 b d"},
+		{Order{By: ByCreated}, "d b a c1 c2"},
+		{Order{By: ByTitle}, "a b d c1 c2"},
+		{Order{By: ByTitle, Descending: true}, "c1 c2 d b a"},
+		// Numbers, numbers written as text, then the other values as text
+		{Order{By: ByProperty, Property: "priority"}, "b c2 a c1 d"},
+		// The notes without the property stay last
+		{Order{By: ByProperty, Property: "priority", Descending: true}, "c1 a c2 b d"},
+		{Order{By: ByProperty, Property: "due"}, "a b d c1 c2"},
+		{Order{By: ByProperty, Property: "missing"}, "a b d c1 c2"},
+		{Order{By: ByUpdated, Descending: true, PinFavorites: true}, "d b a c1 c2"},
+		{Order{By: ByTitle, Descending: true, PinFavorites: true}, "d c1 c2 b a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order.String(), func(t *testing.T) {
+			sorted := append([]model.Note{}, notes...)
+			Sort(sorted, tt.order)
+			ids := []string{}
+			for _, note := range sorted {
+				ids = append(ids, note.NoteID)
+			}
+			if got := strings.Join(ids, " "); got != tt.ids {
+				t.Errorf("Sort(%+v) = %s, expected %s", tt.order, got, tt.ids)
+			}
+		})
+	}
+}
@@ -15,14 +15,27 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Group is a named set of items. Children allow to build a tree of groups,
+// a parent is expected to contain the items of all its children.
 type Group struct {
-	Name  string
-	Items []list.Item
+	Name     string
+	Items    []list.Item
+	Children []Group
+}
+
+// row is a visible group once the tree is flattened
+type row struct {
+	group *Group
+	path  string
+	depth int
 }
 
 type Model struct {
 	// Logic
 	Groups        []Group
+	rows          []row
+	expanded      map[string]bool
+	sortOrder     SortOrder
 	opennedGroup  *int
 	selectedGroup int
 	selectedItem  *int
@@ -53,8 +66,10 @@ func New(groups []Group, delegate *delegate.StyledDelegate, tm *styles.ThemeMana
 	p.ActiveDot = lipgloss.NewStyle().Foreground(tm.Current().Primary).Render("•")
 	p.InactiveDot = lipgloss.NewStyle().Foreground(tm.Current().MutedColor).Render("•")
 
-	return Model{
+	m := Model{
 		Groups:        groups,
+		expanded:      make(map[string]bool),
+		sortOrder:     ParseSortOrder(tm.Config.TagsSort),
 		opennedGroup:  nil,
 		themeManager:  tm,
 		keys:          controls.Keys,
@@ -64,6 +79,9 @@ func New(groups []Group, delegate *delegate.StyledDelegate, tm *styles.ThemeMana
 		visibleGroups: 0,
 		paginator:     p,
 	}
+	sortGroups(m.Groups, m.sortOrder)
+	m.rebuildRows()
+	return m
 }
 
 func (m Model) Width() int {
@@ -75,15 +93,31 @@ func (m Model) CurrentItemIdx() *int {
 }
 
 func (m Model) SelectedItem() list.Item {
-	if len(m.Groups) <= m.selectedGroup {
+	if len(m.rows) <= m.selectedGroup {
 		return nil
 	}
 	if m.selectedItem != nil {
-		return m.Groups[m.selectedGroup].Items[*m.selectedItem]
+		return m.rows[m.selectedGroup].group.Items[*m.selectedItem]
 	}
 	return nil
 }
 
+// SelectedGroup returns the full path of the group under the cursor, e.g. `work/clients`
+func (m Model) SelectedGroup() string {
+	if len(m.rows) <= m.selectedGroup {
+		return ""
+	}
+	return m.rows[m.selectedGroup].path
+}
+
+// SelectedGroupItems returns the items of the group under the cursor, including the ones of its children
+func (m Model) SelectedGroupItems() []list.Item {
+	if len(m.rows) <= m.selectedGroup {
+		return nil
+	}
+	return m.rows[m.selectedGroup].group.Items
+}
+
 func (m *Model) SetWidth(w int) {
 	m.width = w
 }
@@ -92,12 +126,59 @@ func (m *Model) SetHeight(h int) {
 	m.height = h
 }
 
+// SetGroups replaces the groups, e.g. after a refresh of the notes. The groups still there
+// stay expanded, and the cursor stays on the same group, or on its item when it still exists
 func (m *Model) SetGroups(groups []Group) {
+	selectedPath := m.SelectedGroup()
+	openedPath := ""
+	if m.opennedGroup != nil && *m.opennedGroup < len(m.rows) {
+		openedPath = m.rows[*m.opennedGroup].path
+	}
+
 	m.Groups = groups
+	sortGroups(m.Groups, m.sortOrder)
+	paths := groupPaths(m.Groups, "")
+	for path := range m.expanded {
+		if !paths[path] {
+			delete(m.expanded, path)
+		}
+	}
+	m.rebuildRows()
+
 	m.opennedGroup = nil
 	m.selectedGroup = 0
-	m.selectedItem = nil
-	m.page = 0
+	for i, r := range m.rows {
+		if r.path == selectedPath {
+			m.selectedGroup = i
+		}
+		if openedPath != "" && r.path == openedPath {
+			opened := i
+			m.opennedGroup = &opened
+		}
+	}
+	if m.selectedItem != nil && (m.opennedGroup == nil || *m.opennedGroup != m.selectedGroup ||
+		*m.selectedItem >= len(m.rows[m.selectedGroup].group.Items)) {
+		m.selectedItem = nil
+	}
+	if m.selectedItem == nil {
+		m.page = 0
+	}
+}
+
+// groupPaths returns the full paths of the groups and of all their children
+func groupPaths(groups []Group, parent string) map[string]bool {
+	paths := make(map[string]bool)
+	for _, group := range groups {
+		path := group.Name
+		if parent != "" {
+			path = parent + "/" + path
+		}
+		paths[path] = true
+		for child := range groupPaths(group.Children, path) {
+			paths[child] = true
+		}
+	}
+	return paths
 }
 
 func (m Model) Init() tea.Cmd {
@@ -119,6 +200,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Select):
 			m.handleSelectItem()
 
+		case key.Matches(msg, m.keys.SortGroups):
+			m.toggleSortOrder()
+
 		case key.Matches(msg, m.keys.PageUp):
 			// items page
 			if m.page > 0 {
@@ -137,7 +221,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 			availableHeight := m.height - headerHeight
 			itemsPerPage := availableHeight / (m.delegate.Height() + m.delegate.Spacing())
-			maxPages := (len(m.Groups[m.selectedGroup].Items) + itemsPerPage - 1) / itemsPerPage
+			maxPages := (len(m.rows[m.selectedGroup].group.Items) + itemsPerPage - 1) / itemsPerPage
 			if m.page < maxPages-1 {
 				m.page++
 				m.paginator.NextPage()
@@ -165,7 +249,7 @@ func (m *Model) ensureItemVisible() {
 	itemsPerPage := availableHeight / (m.delegate.Height() + m.delegate.Spacing())
 
 	// Update paginator total pages
-	totalItems := len(m.Groups[*m.opennedGroup].Items)
+	totalItems := len(m.rows[*m.opennedGroup].group.Items)
 	m.paginator.SetTotalPages((totalItems + itemsPerPage - 1) / itemsPerPage)
 
 	// Calculate which page the selected item should be on
@@ -183,7 +267,7 @@ func (m Model) populatedView() string {
 		return ""
 	}
 	styles := m.themeManager.Styles()
-	items := m.Groups[*m.opennedGroup].Items
+	items := m.rows[*m.opennedGroup].group.Items
 
 	var b strings.Builder
 
@@ -243,7 +327,7 @@ func (t Model) View() string {
 	indent := strings.Repeat(" ", 2)
 
 	s.WriteString("\n")
-	desc := fmt.Sprintf("   %d items", len(t.Groups))
+	desc := fmt.Sprintf("   %d items • sorted by %s", len(t.Groups), t.sortOrder)
 	s.WriteString(styles.Muted.Render(desc) + "\n")
 	s.WriteString("\n")
 
@@ -269,7 +353,7 @@ func (t Model) View() string {
 	}
 
 	// Ensure we don't scroll past the end
-	maxOffset := len(t.Groups) - t.visibleGroups
+	maxOffset := len(t.rows) - t.visibleGroups
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
@@ -286,12 +370,12 @@ func (t Model) View() string {
 
 	// List visible groups
 	endIdx := t.groupOffset + t.visibleGroups
-	if endIdx > len(t.Groups) {
-		endIdx = len(t.Groups)
+	if endIdx > len(t.rows) {
+		endIdx = len(t.rows)
 	}
 
 	for i := t.groupOffset; i < endIdx; i++ {
-		group := t.Groups[i]
+		group := t.rows[i].group
 		noteCount := len(group.Items)
 
 		// indication on open/closed state
@@ -300,15 +384,22 @@ func (t Model) View() string {
 			// When selected, reduce the indent by 2 to compensate for the border
 			prefix = prefix[2:]
 		}
+		depthIndent := strings.Repeat(indent, t.rows[i].depth)
 
 		// Create title and description
-		title := prefix + utils.UpperFirst(group.Name)
+		title := depthIndent + prefix + utils.UpperFirst(group.Name)
 		desc := fmt.Sprintf("%d items", noteCount)
+		if len(group.Children) > 0 {
+			desc += fmt.Sprintf(" • %d tags", len(group.Children))
+		}
 		if t.opennedGroup != nil && *t.opennedGroup == i {
 			desc = "▼ " + desc
+		} else if t.expanded[t.rows[i].path] {
+			desc = "▽ " + desc
 		} else {
 			desc = "▶ " + desc
 		}
+		desc = depthIndent + desc
 
 		// Style based on selection state
 		if i == t.selectedGroup && t.selectedItem == nil {
@@ -318,7 +409,7 @@ func (t Model) View() string {
 				Padding(0, 0, 0, 1)
 			descStyle := titleStyle.Foreground(theme.Secondary)
 			s.WriteString(titleStyle.Render(title) + "\n")
-			s.WriteString(descStyle.Render(indent[2:]+desc) + "\n")
+			s.WriteString(descStyle.Render(desc) + "\n")
 		} else {
 			s.WriteString(styles.Text.Render(title) + "\n")
 			s.WriteString(styles.Muted.Render(indent+desc) + "\n")
@@ -342,7 +433,7 @@ func (t Model) View() string {
 	}
 
 	// Show scroll indicators if needed
-	if endIdx < len(t.Groups) {
+	if endIdx < len(t.rows) {
 		s.WriteString(styles.Muted.Render("  ▼\n"))
 	}
 
@@ -0,0 +1,87 @@
+package grouplist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type item string
+
+func (i item) FilterValue() string { return string(i) }
+
+func tree() []Group {
+	return []Group{
+		{Name: "work", Items: []list.Item{item("a"), item("b")}, Children: []Group{
+			{Name: "infra", Items: []list.Item{item("a")}, Children: []Group{
+				{Name: "db", Items: []list.Item{item("a")}},
+			}},
+			{Name: "clients", Items: []list.Item{item("b")}},
+		}},
+		{Name: "home", Items: []list.Item{item("c")}},
+	}
+}
+
+func (m Model) visible() []string {
+	paths := []string{}
+	for _, r := range m.rows {
+		paths = append(paths, r.path)
+	}
+	return paths
+}
+
+// selectPath moves the cursor on the group and opens or closes it
+func (m *Model) selectPath(t *testing.T, path string) {
+	t.Helper()
+	for i, r := range m.rows {
+		if r.path == path {
+			m.selectedGroup = i
+			m.selectedItem = nil
+			m.handleSelectItem()
+			return
+		}
+	}
+	t.Fatalf("%s is not visible in %v", path, m.visible())
+}
+
+func TestSetGroupsKeepsTheExpandedGroups(t *testing.T) {
+	m := Model{expanded: make(map[string]bool)}
+	m.SetGroups(tree())
+	m.selectPath(t, "work")
+	m.selectPath(t, "work/infra")
+	item := 0
+	m.selectedItem = &item
+
+	groups := tree()
+	groups[0].Children = groups[0].Children[:1] // work/clients is gone
+	groups = append(groups, Group{Name: "archive"})
+	m.SetGroups(groups)
+
+	if got := strings.Join(m.visible(), " "); got != "archive home work work/infra work/infra/db" {
+		t.Fatalf("rows = %s, expected work and work/infra still expanded", got)
+	}
+	if m.SelectedGroup() != "work/infra" || m.opennedGroup == nil || *m.opennedGroup != m.selectedGroup {
+		t.Errorf("the cursor moved to %s", m.SelectedGroup())
+	}
+	if m.selectedItem == nil || *m.selectedItem != 0 {
+		t.Errorf("the selected item is lost")
+	}
+}
+
+func TestCollapseClearsTheExpandedChildren(t *testing.T) {
+	m := Model{expanded: make(map[string]bool)}
+	m.SetGroups(tree())
+	m.selectPath(t, "work")
+	m.selectPath(t, "work/infra")
+	m.selectPath(t, "work") // Opens work again
+	m.selectPath(t, "work") // Closes it
+
+	if m.expanded["work/infra"] {
+		t.Errorf("work/infra is still expanded after closing work")
+	}
+	m.selectPath(t, "work")
+	if got := strings.Join(m.visible(), " "); got != "home work work/clients work/infra" {
+		t.Errorf("rows = %s, expected the children of work collapsed", got)
+	}
+}
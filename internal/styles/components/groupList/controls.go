@@ -1,5 +1,7 @@
 package grouplist
 
+import "strings"
+
 func (m *Model) prevGroup() {
 	if m.selectedGroup > 0 {
 		m.selectedGroup -= 1
@@ -13,7 +15,7 @@ func (m *Model) prevGroup() {
 }
 
 func (m *Model) nextGroup() {
-	if m.selectedGroup < len(m.Groups)-1 {
+	if m.selectedGroup < len(m.rows)-1 {
 		m.selectedGroup += 1
 		m.page = 0
 
@@ -29,13 +31,13 @@ func (m *Model) handleUpNavigation() {
 		if m.opennedGroup == nil {
 			// No group open => move to previous group
 			m.prevGroup()
-		} else if m.selectedGroup == (*m.opennedGroup+1) && len(m.Groups[*m.opennedGroup].Items) == 0 {
+		} else if m.selectedGroup == (*m.opennedGroup+1) && len(m.rows[*m.opennedGroup].group.Items) == 0 {
 			// Next group + no items in open => move to previous group
 			m.prevGroup()
 		} else if m.selectedGroup == (*m.opennedGroup+1) {
 			// Next group + items in open group => move to last item
 			m.prevGroup()
-			lastItemIdx := len(m.Groups[*m.opennedGroup].Items) - 1
+			lastItemIdx := len(m.rows[*m.opennedGroup].group.Items) - 1
 			m.selectedItem = &lastItemIdx
 			m.ensureItemVisible()
 		} else if m.selectedGroup == *m.opennedGroup && m.selectedItem == nil {
@@ -43,13 +45,13 @@ func (m *Model) handleUpNavigation() {
 			m.prevGroup()
 		} else if m.selectedGroup == *m.opennedGroup {
 			// Nav in the open group
-			if len(m.Groups[*m.opennedGroup].Items) == 0 {
+			if len(m.rows[*m.opennedGroup].group.Items) == 0 {
 				// Safety check
 				m.prevGroup()
 			} else if m.selectedItem == nil {
 				// Safety check
 				m.selectedGroup = *m.opennedGroup
-				lastItemIdx := len(m.Groups[*m.opennedGroup].Items) - 1
+				lastItemIdx := len(m.rows[*m.opennedGroup].group.Items) - 1
 				m.selectedItem = &lastItemIdx
 				m.ensureItemVisible()
 			} else if *m.selectedItem == 0 {
@@ -68,7 +70,7 @@ func (m *Model) handleUpNavigation() {
 }
 
 func (m *Model) handleDownNavigation() {
-	if m.selectedGroup <= len(m.Groups)-1 {
+	if m.selectedGroup <= len(m.rows)-1 {
 		if m.opennedGroup == nil {
 			// No open group => Next
 			m.nextGroup()
@@ -76,7 +78,7 @@ func (m *Model) handleDownNavigation() {
 			// No in the open group => Next
 			m.nextGroup()
 		} else {
-			if m.selectedItem == nil && len(m.Groups[m.selectedGroup].Items) == 0 {
+			if m.selectedItem == nil && len(m.rows[m.selectedGroup].group.Items) == 0 {
 				// No Item => Next
 				m.nextGroup()
 			} else if m.selectedItem == nil {
@@ -84,9 +86,9 @@ func (m *Model) handleDownNavigation() {
 				var value int = 0
 				m.selectedItem = &value
 				m.ensureItemVisible()
-			} else if *m.selectedItem == (len(m.Groups[m.selectedGroup].Items) - 1) {
+			} else if *m.selectedItem == (len(m.rows[m.selectedGroup].group.Items) - 1) {
 				// Last item => Next group
-				if m.selectedGroup != len(m.Groups)-1 {
+				if m.selectedGroup != len(m.rows)-1 {
 					m.selectedItem = nil
 					m.nextGroup()
 				}
@@ -100,13 +102,27 @@ func (m *Model) handleDownNavigation() {
 }
 
 func (m *Model) handleSelectItem() {
+	if len(m.rows) == 0 {
+		return
+	}
 	if m.selectedItem == nil {
+		path := m.rows[m.selectedGroup].path
 		if m.opennedGroup != nil && m.selectedGroup == *m.opennedGroup {
+			// Closing a group also collapses its sub groups, and the ones expanded below them
 			m.opennedGroup = nil
+			for expanded := range m.expanded {
+				if expanded == path || strings.HasPrefix(expanded, path+"/") {
+					delete(m.expanded, expanded)
+				}
+			}
+			m.rebuildRows()
 		} else {
+			m.expanded[path] = true
+			m.rebuildRows()
+			openned := m.selectedGroup
+			m.opennedGroup = &openned
+
 			// When opening a group, ensure we have enough space by adjusting visible groups
-			m.opennedGroup = &m.selectedGroup
-			
 			// Calculate how many groups can be displayed with the open group
 			availableHeight := m.height - HEADER_HEIGHT
 			openGroupSpace := POPULATED_VIEW_HEIGHT + 4 // Add 4 for separators and spacing
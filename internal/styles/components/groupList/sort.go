@@ -0,0 +1,93 @@
+package grouplist
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// SortOrder defines how the groups are ordered, at every level of the tree
+type SortOrder int
+
+const (
+	SortByName SortOrder = iota
+	SortByCount
+)
+
+func (s SortOrder) String() string {
+	switch s {
+	case SortByCount:
+		return "count"
+	default:
+		return "name"
+	}
+}
+
+// ParseSortOrder returns the SortOrder matching the name, default to SortByName
+func ParseSortOrder(name string) SortOrder {
+	if strings.EqualFold(name, SortByCount.String()) {
+		return SortByCount
+	}
+	return SortByName
+}
+
+func sortGroups(groups []Group, order SortOrder) {
+	sort.SliceStable(groups, func(i, j int) bool {
+		if order == SortByCount && len(groups[i].Items) != len(groups[j].Items) {
+			return len(groups[i].Items) > len(groups[j].Items)
+		}
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+	for i := range groups {
+		sortGroups(groups[i].Children, order)
+	}
+}
+
+// rebuildRows flattens the tree of groups into the visible rows,
+// children are only visible when their parent is expanded
+func (m *Model) rebuildRows() {
+	m.rows = make([]row, 0, len(m.rows))
+	var walk func(groups []Group, parent string, depth int)
+	walk = func(groups []Group, parent string, depth int) {
+		for i := range groups {
+			path := groups[i].Name
+			if parent != "" {
+				path = parent + "/" + path
+			}
+			m.rows = append(m.rows, row{group: &groups[i], path: path, depth: depth})
+			if m.expanded[path] {
+				walk(groups[i].Children, path, depth+1)
+			}
+		}
+	}
+	walk(m.Groups, "", 0)
+}
+
+// toggleSortOrder swaps between the sort orders while keeping the cursor on the same group
+func (m *Model) toggleSortOrder() {
+	if m.sortOrder == SortByName {
+		m.sortOrder = SortByCount
+	} else {
+		m.sortOrder = SortByName
+	}
+	// The order still applies until the app is closed
+	if err := m.themeManager.SetTagsSort(m.sortOrder.String()); err != nil {
+		log.Error("Failed to save the sort of the tags", "error", err)
+	}
+
+	selectedPath := m.SelectedGroup()
+	sortGroups(m.Groups, m.sortOrder)
+	m.rebuildRows()
+
+	m.opennedGroup = nil
+	m.selectedItem = nil
+	m.selectedGroup = 0
+	m.page = 0
+	for i, r := range m.rows {
+		if r.path == selectedPath {
+			m.selectedGroup = i
+			break
+		}
+	}
+}
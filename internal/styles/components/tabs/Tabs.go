@@ -1,8 +1,11 @@
 package Tabs
 
 import (
+	"strings"
+
 	"merlion/internal/styles"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
 )
 
@@ -38,7 +41,7 @@ func New[T interface {
 	}
 	startTab := 0
 	for i, tab := range tabs {
-		if tab.String() == firstTab {
+		if strings.EqualFold(tab.String(), firstTab) {
 			startTab = i
 			break
 		}
@@ -57,6 +60,14 @@ func (t *Tabs[T]) renderTabs() string {
 	}
 	style := t.themeManager.Styles()
 	availableWidth := t.Width - 4 // borders and padding
+	// Keep room for the arrows when all the tabs don't fit
+	allTabsWidth := 0
+	for _, tab := range t.Tabs {
+		allTabsWidth += len(tab.String()) + 4
+	}
+	if t.ShowArrows && allTabsWidth > availableWidth {
+		availableWidth -= 2 * lipgloss.Width(style.InactiveTab.Render("→"))
+	}
 	var visibleTabs []T
 	var startIdx, endIdx int
 	currentWidth := 0
@@ -173,3 +184,27 @@ func (t *Tabs[T]) PrevTab() T {
 	}
 	return t.Tabs[t.ActiveTab]
 }
+
+// SelectTab moves to the tab displayed with this name, returns false if there is none
+func (t *Tabs[T]) SelectTab(name string) bool {
+	for i, tab := range t.Tabs {
+		if strings.EqualFold(tab.String(), name) {
+			t.ActiveTab = i
+			return true
+		}
+	}
+	return false
+}
+
+// SetTabs replaces the tabs, staying on the active tab when it still exists
+func (t *Tabs[T]) SetTabs(tabs []T) {
+	if len(tabs) == 0 {
+		log.Fatal("Tabs update: tabs lenght is 0")
+	}
+	current := t.CurrentTab().String()
+	t.Tabs = tabs
+	if t.ActiveTab >= len(tabs) {
+		t.ActiveTab = len(tabs) - 1
+	}
+	t.SelectTab(current)
+}
@@ -0,0 +1,271 @@
+package styles
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"merlion/internal/config"
+
+	"github.com/BurntSushi/toml"
+	chromaStyles "github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/latentdream/merlion/lib/glamour/ansi"
+	glamourStyles "github.com/latentdream/merlion/lib/glamour/styles"
+)
+
+// Theme Files ---
+
+// themeFile is a theme of ~/.config/merlion/themes, in JSON or TOML with the same keys
+//
+//	name = "dracula"
+//	base = "neotokyo"
+//	markdownStyle = "dracula"
+//	codeTheme = "dracula"
+//
+//	[colors]
+//	primary = "#bd93f9"
+//
+//	[markdown.h1]
+//	background_color = "#ff79c6"
+type themeFile struct {
+	Name          string            `json:"name"`
+	Base          string            `json:"base"` // Built-in theme of the missing colors, neotokyo by default
+	Colors        map[string]string `json:"colors"`
+	Margin        *uint             `json:"margin"`
+	ListIndent    *uint             `json:"listIndent"`
+	MarkdownStyle string            `json:"markdownStyle"`
+	Markdown      map[string]any    `json:"markdown"`
+	CodeTheme     string            `json:"codeTheme"`
+}
+
+// ThemesDir returns the directory of the theme files, ~/.config/merlion/themes
+func ThemesDir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+// LoadThemes returns the built-in themes followed by the ones of the theme files, sorted by name.
+// The invalid files are skipped and reported in the errors
+func LoadThemes() ([]Theme, []error) {
+	themes := append([]Theme{}, Builtin...)
+	dir, err := ThemesDir()
+	if err != nil {
+		return themes, []error{err}
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return themes, nil
+	} else if err != nil {
+		return themes, []error{fmt.Errorf("reading the themes: %w", err)}
+	}
+
+	errs := []error{}
+	loaded := []Theme{}
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".toml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		theme, err := LoadThemeFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if existing := findTheme(append(themes, loaded...), theme.Name); existing != nil {
+			errs = append(errs, fmt.Errorf("%s: theme %q is already defined", entry.Name(), theme.Name))
+			continue
+		}
+		loaded = append(loaded, theme)
+	}
+	sort.SliceStable(loaded, func(i, j int) bool { return loaded[i].Name < loaded[j].Name })
+	return append(themes, loaded...), errs
+}
+
+// LoadThemeFile reads a JSON or TOML theme, errors are prefixed by the file name
+func LoadThemeFile(path string) (Theme, error) {
+	theme, err := loadThemeFile(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return theme, nil
+}
+
+func loadThemeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	// TOML is converted to JSON, so both formats use the keys of the JSON tags
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		values := map[string]any{}
+		if _, err := toml.Decode(string(data), &values); err != nil {
+			return Theme{}, err
+		}
+		if data, err = json.Marshal(values); err != nil {
+			return Theme{}, err
+		}
+	}
+	var file themeFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return Theme{}, err
+	}
+
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	base := NeoTokyo
+	if file.Base != "" {
+		if found := findTheme(Builtin, file.Base); found != nil {
+			base = *found
+		} else {
+			return Theme{}, fmt.Errorf("unknown base theme %q, expected one of: %s", file.Base, strings.Join(themeNames(Builtin), ", "))
+		}
+	}
+
+	theme := base
+	theme.Name = file.Name
+	theme.Path = path
+	if err := setColors(&theme, file.Colors); err != nil {
+		return Theme{}, err
+	}
+	if file.Margin != nil {
+		theme.Margin = *file.Margin
+	}
+	if file.ListIndent != nil {
+		theme.ListIndent = *file.ListIndent
+	}
+
+	if file.MarkdownStyle != "" {
+		if _, ok := glamourStyles.DefaultStyles[file.MarkdownStyle]; !ok {
+			return Theme{}, fmt.Errorf("unknown markdownStyle %q, expected one of: %s", file.MarkdownStyle, strings.Join(glamourStyleNames(), ", "))
+		}
+	}
+	theme.MarkdownStyle = file.MarkdownStyle
+	if file.CodeTheme != "" && chromaStyles.Registry[file.CodeTheme] == nil {
+		return Theme{}, fmt.Errorf("unknown codeTheme %q, expected a chroma style like monokai, dracula or github", file.CodeTheme)
+	}
+	theme.CodeTheme = file.CodeTheme
+	theme.Markdown = file.Markdown
+	if _, err := mergeStyle(ansi.StyleConfig{}, theme.Markdown); err != nil {
+		return Theme{}, fmt.Errorf("invalid markdown: %w", err)
+	}
+	return theme, nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// setColors replaces the colors of the theme, by their name in the theme files
+func setColors(theme *Theme, colors map[string]string) error {
+	fields := map[string]*lipgloss.Color{
+		"background": &theme.Background,
+		"foreground": &theme.Foreground,
+		"selection":  &theme.Selection,
+		"comment":    &theme.Comment,
+		"primary":    &theme.Primary,
+		"secondary":  &theme.Secondary,
+		"tertiary":   &theme.Tertiary,
+		"error":      &theme.Error,
+		"warning":    &theme.Warning,
+		"success":    &theme.Success,
+		"border":     &theme.BorderColor,
+		"highlight":  &theme.HighlightColor,
+		"muted":      &theme.MutedColor,
+		"help":       &theme.HelpColor,
+	}
+	for name, value := range colors {
+		field, ok := fields[name]
+		if !ok {
+			names := []string{}
+			for name := range fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown color %q, expected one of: %s", name, strings.Join(names, ", "))
+		}
+		if n, err := strconv.Atoi(value); !hexColor.MatchString(value) && (err != nil || n < 0 || n > 255) {
+			return fmt.Errorf("invalid color %s = %q, expected #rrggbb or an ANSI color between 0 and 255", name, value)
+		}
+		*field = lipgloss.Color(value)
+	}
+	return nil
+}
+
+// mergeStyle applies the overrides, with the JSON keys of the ansi.StyleConfig, on the style
+func mergeStyle(style ansi.StyleConfig, overrides map[string]any) (ansi.StyleConfig, error) {
+	if len(overrides) == 0 {
+		return style, nil
+	}
+	data, err := json.Marshal(style)
+	if err != nil {
+		return style, err
+	}
+	values := map[string]any{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return style, err
+	}
+	mergeValues(values, overrides)
+
+	if data, err = json.Marshal(values); err != nil {
+		return style, err
+	}
+	var result ansi.StyleConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return style, err
+	}
+	return result, nil
+}
+
+// mergeValues sets the overrides in the values, recursively for the objects
+func mergeValues(values map[string]any, overrides map[string]any) {
+	for key, override := range overrides {
+		overrideObject, isObject := override.(map[string]any)
+		valueObject, hasObject := values[key].(map[string]any)
+		if isObject && hasObject {
+			mergeValues(valueObject, overrideObject)
+			continue
+		}
+		values[key] = override
+	}
+}
+
+func findTheme(themes []Theme, name string) *Theme {
+	for i := range themes {
+		if strings.EqualFold(themes[i].Name, name) {
+			return &themes[i]
+		}
+	}
+	return nil
+}
+
+func themeNames(themes []Theme) []string {
+	names := []string{}
+	for _, theme := range themes {
+		names = append(names, theme.Name)
+	}
+	return names
+}
+
+func glamourStyleNames() []string {
+	names := []string{}
+	for name := range glamourStyles.DefaultStyles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"merlion/internal/config"
+	"merlion/internal/sorting"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
@@ -14,19 +15,38 @@ type ThemeManager struct {
 	Theme        Theme
 	Config       *config.UserConfig
 	saveOnChange bool
+	themes       []Theme
+	// Errors of the theme files, the invalid themes are skipped
+	ThemeErrors []error
 }
 
 func NewThemeManager() (*ThemeManager, error) {
 	config := config.Load()
+	themes, errs := LoadThemes()
 	tm := &ThemeManager{
 		Config:       config,
-		Theme:        FindThemeByName(config.Theme),
+		Theme:        NeoTokyo,
 		saveOnChange: true,
+		themes:       themes,
+		ThemeErrors:  errs,
+	}
+	if theme := findTheme(themes, config.Theme); theme != nil {
+		tm.Theme = *theme
+	} else if config.Theme != "" {
+		tm.ThemeErrors = append(tm.ThemeErrors, fmt.Errorf("unknown theme %q, using %s", config.Theme, NeoTokyo.Name))
+	}
+	for _, err := range tm.ThemeErrors {
+		log.Warnf("Theme: %v", err)
 	}
 
 	return tm, nil
 }
 
+// Themes returns the built-in themes and the ones of the theme files
+func (tm *ThemeManager) Themes() []Theme {
+	return tm.themes
+}
+
 // SetSaveOnChange: Allows to remove the auto onsave when changing the config element
 func (tm *ThemeManager) SetSaveOnChange(saveOnChange bool) {
 	tm.saveOnChange = saveOnChange
@@ -45,35 +65,27 @@ func (tm *ThemeManager) Current() Theme {
 	return tm.Theme
 }
 
+// NextTheme switches to the next theme, built-in ones first then the theme files
 func (tm *ThemeManager) NextTheme() *Styles {
-	var nextTheme string
-	switch tm.Current().Name {
-	case "gruvbox":
-		nextTheme = "neotokyo"
-	case "neotokyo":
-		nextTheme = "terminal"
-	case "terminal":
-		nextTheme = "gruvbox"
+	next := 0
+	for i, theme := range tm.themes {
+		if theme.Name == tm.Current().Name {
+			next = (i + 1) % len(tm.themes)
+		}
 	}
-	err := tm.SetTheme(nextTheme)
-	if err != nil {
-		log.Fatalf("Failed to toggle theme %s", err)
+	if err := tm.SetTheme(tm.themes[next].Name); err != nil {
+		log.Errorf("Failed to toggle theme %s", err)
 	}
 	return tm.Styles()
 }
 
 func (tm *ThemeManager) SetTheme(name string) error {
-	switch name {
-	case "gruvbox":
-		tm.Theme = Gruvbox
-	case "neotokyo":
-		tm.Theme = NeoTokyo
-	case "terminal":
-		tm.Theme = Terminal
-	default:
+	theme := findTheme(tm.themes, name)
+	if theme == nil {
 		return fmt.Errorf("unknown theme: %s", name)
 	}
-	tm.Config.Theme = name
+	tm.Theme = *theme
+	tm.Config.Theme = theme.Name
 	return tm.SaveConfig()
 }
 
@@ -92,6 +104,43 @@ func (tm *ThemeManager) SetCompactViewOnly(compact bool) error {
 	return tm.SaveConfig()
 }
 
+func (tm *ThemeManager) SetTagsSort(order string) error {
+	tm.Config.TagsSort = order
+	return tm.SaveConfig()
+}
+
+// SetNotesSort saves the sort of a tab, in the tab itself for the user defined ones
+func (tm *ThemeManager) SetNotesSort(tab string, order sorting.Order) error {
+	if userTab := tm.Config.FindTab(tab); userTab != nil {
+		userTab.Sort = &order
+		return tm.SaveConfig()
+	}
+	if tm.Config.NotesSort == nil {
+		tm.Config.NotesSort = make(map[string]sorting.Order)
+	}
+	tm.Config.NotesSort[tab] = order
+	return tm.SaveConfig()
+}
+
+func (tm *ThemeManager) SetTabs(tabs []config.Tab) error {
+	tm.Config.Tabs = tabs
+	return tm.SaveConfig()
+}
+
+const maxRecentCommands = 20
+
+// AddRecentCommand moves the command palette item at the top of the recently used ones
+func (tm *ThemeManager) AddRecentCommand(id string) error {
+	recent := []string{id}
+	for _, existing := range tm.Config.RecentCommands {
+		if existing != id && len(recent) < maxRecentCommands {
+			recent = append(recent, existing)
+		}
+	}
+	tm.Config.RecentCommands = recent
+	return tm.SaveConfig()
+}
+
 // Create the styles that use the current theme
 func (tm *ThemeManager) Styles() *Styles {
 	theme := tm.Current()
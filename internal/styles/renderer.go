@@ -1,6 +1,10 @@
 package styles
 
-import "github.com/latentdream/merlion/lib/glamour/ansi"
+import (
+	"github.com/charmbracelet/log"
+	"github.com/latentdream/merlion/lib/glamour/ansi"
+	glamourStyles "github.com/latentdream/merlion/lib/glamour/styles"
+)
 
 func stringPtr(s string) *string {
 	return &s
@@ -14,7 +18,56 @@ func uintPtr(u uint) *uint {
 	return &u
 }
 
+// GetRendererStyle returns the markdown style built from the colors, with the overrides of the theme file
 func (tm *ThemeManager) GetRendererStyle() ansi.StyleConfig {
+	style := tm.colorsRendererStyle()
+	if base, ok := glamourStyles.DefaultStyles[tm.Theme.MarkdownStyle]; ok {
+		colors := style
+		style = *base
+		// Wiki links are specific to merlion, the glamour styles don't have them
+		style.WikiLink = colors.WikiLink
+		style.Selector = colors.Selector
+	}
+	if merged, err := mergeStyle(style, tm.Theme.Markdown); err == nil {
+		style = merged
+	} else {
+		log.Errorf("Theme %s: %v", tm.Theme.Name, err)
+	}
+	if tm.Theme.CodeTheme != "" {
+		style.CodeBlock.Theme = tm.Theme.CodeTheme
+		style.CodeBlock.Chroma = nil
+	}
+	return style
+}
+
+// calloutTypes returns the styles of the callout types, in the colors of the theme
+func (tm *ThemeManager) calloutTypes() map[string]ansi.StyleCalloutType {
+	colors := map[string]string{
+		"note":     string(tm.Theme.Primary),
+		"abstract": string(tm.Theme.Secondary),
+		"info":     string(tm.Theme.Primary),
+		"todo":     string(tm.Theme.Primary),
+		"tip":      string(tm.Theme.Tertiary),
+		"success":  string(tm.Theme.Success),
+		"question": string(tm.Theme.Warning),
+		"warning":  string(tm.Theme.Warning),
+		"failure":  string(tm.Theme.Error),
+		"danger":   string(tm.Theme.Error),
+		"bug":      string(tm.Theme.Error),
+		"example":  string(tm.Theme.Secondary),
+		"quote":    string(tm.Theme.MutedColor),
+	}
+	types := make(map[string]ansi.StyleCalloutType, len(colors))
+	for name, color := range colors {
+		types[name] = ansi.StyleCalloutType{
+			StylePrimitive: ansi.StylePrimitive{Color: stringPtr(color)},
+			Icon:           glamourStyles.CalloutIcons[name],
+		}
+	}
+	return types
+}
+
+func (tm *ThemeManager) colorsRendererStyle() ansi.StyleConfig {
 	return ansi.StyleConfig{
 		Document: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
@@ -29,6 +82,18 @@ func (tm *ThemeManager) GetRendererStyle() ansi.StyleConfig {
 			Indent:         uintPtr(1),
 			IndentToken:    stringPtr("│ "),
 		},
+		Callout: ansi.StyleCallout{
+			StyleBlock: ansi.StyleBlock{
+				Indent:      uintPtr(1),
+				IndentToken: stringPtr("│ "),
+			},
+			Title: ansi.StylePrimitive{
+				Bold: boolPtr(true),
+			},
+			Folded:   " ▸",
+			Unfolded: " ▾",
+			Types:    tm.calloutTypes(),
+		},
 		List: ansi.StyleList{
 			LevelIndent: tm.Theme.ListIndent,
 		},
@@ -84,6 +149,10 @@ func (tm *ThemeManager) GetRendererStyle() ansi.StyleConfig {
 		Strong: ansi.StylePrimitive{
 			Bold: boolPtr(true),
 		},
+		Highlight: ansi.StylePrimitive{
+			Color:           stringPtr(string(tm.Theme.Background)),
+			BackgroundColor: stringPtr(string(tm.Theme.HighlightColor)),
+		},
 		HorizontalRule: ansi.StylePrimitive{
 			Color:  stringPtr(string(tm.Theme.MutedColor)),
 			Format: "\n--------\n",
@@ -224,13 +293,41 @@ func (tm *ThemeManager) GetRendererStyle() ansi.StyleConfig {
 				},
 			},
 		},
+		Math: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Color: stringPtr(string(tm.Theme.Tertiary)),
+			},
+			Indent: uintPtr(tm.Theme.Margin),
+		},
+		MathInline: ansi.StylePrimitive{
+			Color: stringPtr(string(tm.Theme.Tertiary)),
+		},
+		MathRaw: ansi.StylePrimitive{
+			Color: stringPtr(string(tm.Theme.Error)),
+		},
 		Table: ansi.StyleTable{
 			StyleBlock: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{},
 			},
 		},
-		DefinitionDescription: ansi.StylePrimitive{
-			BlockPrefix: "\n🠶 ",
+		DefinitionTerm: ansi.StylePrimitive{
+			Color: stringPtr(string(tm.Theme.Primary)),
+			Bold:  boolPtr(true),
+		},
+		DefinitionDescription: ansi.StyleBlock{
+			Indent: uintPtr(4),
+		},
+		FootnoteLink: ansi.StylePrimitive{
+			Color:  stringPtr(string(tm.Theme.Secondary)),
+			Format: "{{Superscript .text}}",
+		},
+		Footnote: ansi.StylePrimitive{
+			Color:       stringPtr(string(tm.Theme.Secondary)),
+			BlockSuffix: ". ",
+		},
+		FootnoteBacklink: ansi.StylePrimitive{
+			Color:       stringPtr(string(tm.Theme.Secondary)),
+			BlockPrefix: " ",
 		},
 	}
 }
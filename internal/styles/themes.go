@@ -28,19 +28,27 @@ type Theme struct {
 	// Specific Renderer elements
 	Margin     uint
 	ListIndent uint
+
+	// Markdown of the notes, set by the theme files
+	MarkdownStyle string         // Glamour style (dracula, tokyo-night, ...) applied over the one built from the colors
+	Markdown      map[string]any // Overrides of the ansi.StyleConfig, with its JSON keys
+	CodeTheme     string         // Chroma style of the code blocks, instead of the colors
+
+	// File of the theme, empty for the built-in ones
+	Path string
 }
 
+// Builtin lists the built-in themes, in ctrl+t order
+var Builtin = []Theme{Gruvbox, NeoTokyo, Terminal}
+
+// FindThemeByName returns the built-in theme with this name, NeoTokyo by default
 func FindThemeByName(name string) Theme {
-	switch name {
-	case "gruvbox":
-		return Gruvbox
-	case "neotokyo":
-		return NeoTokyo
-	case "terminal":
-		return Terminal
-	default:
-		return NeoTokyo
+	for _, theme := range Builtin {
+		if theme.Name == name {
+			return theme
+		}
 	}
+	return NeoTokyo
 }
 
 var (
@@ -0,0 +1,185 @@
+// Package templates contains the logic to create notes from markdown templates
+// A template is a markdown file, with an optional front matter declaring the
+// defaults of the note (title, tags, favorite, worklog)
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"merlion/internal/model"
+
+	"github.com/charmbracelet/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultDateFormat = "2006-01-02"
+	defaultTimeFormat = "15:04"
+)
+
+// Supported variables: {{title}}, {{date}}, {{date:2006-01-02}}, {{time}}, {{time:15:04:05}}
+var variableRegex = regexp.MustCompile(`\{\{\s*(title|date|time)(?::([^}]*))?\s*\}\}`)
+
+type Template struct {
+	Name       string
+	Title      string
+	Tags       []string
+	IsFavorite bool
+	IsWorkLog  bool
+	Body       string
+}
+
+type frontMatter struct {
+	Title    string   `yaml:"title"`
+	Tags     []string `yaml:"tags"`
+	Favorite bool     `yaml:"favorite"`
+	WorkLog  bool     `yaml:"worklog"`
+}
+
+// List returns all the templates found in the directory, sorted by name
+// A missing directory is not an error, it only means there is no template.
+// The templates which can't be loaded are logged and skipped
+func List(dir string) ([]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Template{}, nil
+		}
+		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+
+	templates := []Template{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.ToLower(filepath.Ext(entry.Name())) != ".md" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		template, err := Load(dir, name)
+		if err != nil {
+			// One malformed template doesn't hide the others
+			log.Error("Failed to load template", "name", name, "error", err)
+			continue
+		}
+		templates = append(templates, *template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return strings.ToLower(templates[i].Name) < strings.ToLower(templates[j].Name)
+	})
+	return templates, nil
+}
+
+// Load reads the template `name` from the directory (case insensitive)
+func Load(dir string, name string) (*Template, error) {
+	path := filepath.Join(dir, name+".md")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// Fallback on a case insensitive search
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			entryName := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			if strings.EqualFold(entryName, name) {
+				path = filepath.Join(dir, entry.Name())
+				name = entryName
+				break
+			}
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("template not found: %s", name)
+		}
+		return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+
+	template, err := parse(name, string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	return template, nil
+}
+
+func parse(name string, content string) (*Template, error) {
+	template := &Template{Name: name, Body: content}
+
+	lines := strings.Split(content, "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[0]) != "---" {
+		return template, nil
+	}
+	endIndex := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			endIndex = i
+			break
+		}
+	}
+	if endIndex == -1 {
+		return template, nil
+	}
+
+	var fm frontMatter
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:endIndex], "\n")), &fm); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML front matter: %w", err)
+	}
+
+	template.Title = fm.Title
+	template.Tags = fm.Tags
+	template.IsFavorite = fm.Favorite
+	template.IsWorkLog = fm.WorkLog
+	template.Body = strings.TrimPrefix(strings.Join(lines[endIndex+1:], "\n"), "\n")
+	return template, nil
+}
+
+// Expand replaces the variables of the text
+func Expand(text string, title string, now time.Time) string {
+	return variableRegex.ReplaceAllStringFunc(text, func(match string) string {
+		parts := variableRegex.FindStringSubmatch(match)
+		variable, format := parts[1], strings.TrimSpace(parts[2])
+		switch variable {
+		case "title":
+			return title
+		case "date":
+			if format == "" {
+				format = defaultDateFormat
+			}
+			return now.Format(format)
+		case "time":
+			if format == "" {
+				format = defaultTimeFormat
+			}
+			return now.Format(format)
+		}
+		return match
+	})
+}
+
+// NoteTitle returns the title of the note, the template title is used when none is provided
+func (t Template) NoteTitle(title string, now time.Time) string {
+	if strings.TrimSpace(title) == "" && t.Title != "" {
+		return Expand(t.Title, "", now)
+	}
+	return title
+}
+
+// Apply creates the request of a new note based on the template
+func (t Template) Apply(title string, now time.Time) model.CreateNoteRequest {
+	title = t.NoteTitle(title, now)
+	content := Expand(t.Body, title, now)
+	isFavorite := t.IsFavorite
+	isWorkLog := t.IsWorkLog
+
+	return model.CreateNoteRequest{
+		Title:      title,
+		Content:    &content,
+		Tags:       t.Tags,
+		IsFavorite: &isFavorite,
+		IsWorkLog:  &isWorkLog,
+	}
+}
@@ -0,0 +1,37 @@
+package templates
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/log"
+)
+
+func TestListSkipsTheBrokenTemplates(t *testing.T) {
+	log.SetOutput(io.Discard)
+	dir := t.TempDir()
+	files := map[string]string{
+		"Meeting.md": "---\ntitle: Meeting {{date}}\ntags: [work]\n---\nAgenda\n",
+		"broken.md":  "---\ntags: [unclosed\n---\nBody\n",
+		"daily.md":   "Plain body\n",
+		"notes.txt":  "Not a template\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 || templates[0].Name != "daily" || templates[1].Name != "Meeting" {
+		t.Fatalf("List() = %+v, expected daily and Meeting", templates)
+	}
+	if templates[1].Title != "Meeting {{date}}" || len(templates[1].Tags) != 1 {
+		t.Errorf("unexpected front matter: %+v", templates[1])
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"merlion/internal/context"
 	"merlion/internal/vault"
 	"merlion/internal/vault/cloud"
+	"merlion/internal/ui/conflict"
 	"merlion/internal/ui/create"
 	"merlion/internal/ui/dialog"
+	"merlion/internal/ui/editor"
+	"merlion/internal/ui/help"
 	"merlion/internal/ui/manage"
 	"merlion/internal/ui/navigation"
 	NotesUI "merlion/internal/ui/notes"
+	"merlion/internal/ui/palette"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -31,6 +35,10 @@ func NewModel(cfg *config.UserConfig, credentialsManager *cloud.CredentialsManag
 	views[navigation.NoteUI] = NotesUI.NewModel(manager, ctx.ThemeManager, ctx.FirstTab)
 	views[navigation.DialogUI] = dialog.NewModel(manager, ctx.ThemeManager)
 	views[navigation.ManageUI] = manage.NewModel(manager, ctx.ThemeManager)
+	views[navigation.PaletteUI] = palette.NewModel(manager, ctx.ThemeManager)
+	views[navigation.HelpUI] = help.NewModel(ctx.ThemeManager)
+	views[navigation.EditorUI] = editor.NewModel(manager, ctx.ThemeManager)
+	views[navigation.ConflictUI] = conflict.NewModel(manager, ctx.ThemeManager)
 
 	return Model{
 		state: initialUI,
@@ -72,6 +80,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		view, cmd := m.views[m.state].Update(msg)
 		m.views[m.state] = view
 		return m, tea.Batch(cmd, tea.WindowSize())
+
+	case navigation.OpenEditorMsg:
+		m.state = navigation.EditorUI
+		view, cmd := m.views[m.state].Update(msg)
+		m.views[m.state] = view
+		return m, tea.Batch(cmd, tea.WindowSize())
+
+	case navigation.OpenConflictMsg:
+		m.state = navigation.ConflictUI
+		view, cmd := m.views[m.state].Update(msg)
+		m.views[m.state] = view
+		return m, tea.Batch(cmd, tea.WindowSize())
+
+	case navigation.OpenPaletteMsg:
+		m.state = navigation.PaletteUI
+		view, cmd := m.views[m.state].Update(msg)
+		m.views[m.state] = view
+		return m, tea.Batch(cmd, tea.WindowSize())
+
+	case navigation.OpenHelpMsg:
+		m.state = navigation.HelpUI
+		view, cmd := m.views[m.state].Update(msg)
+		m.views[m.state] = view
+		return m, tea.Batch(cmd, tea.WindowSize())
 	}
 
 	view, cmd := m.views[m.state].Update(msg)
@@ -0,0 +1,74 @@
+// Package actions contains the registry of the actions available in the command palette
+// Views register their actions when created, after the key bindings are loaded, and handle the RunMsg of the actions they registered
+package actions
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type Action struct {
+	ID    string
+	Title string
+	// Optional shortcut of the action, displayed in the palette
+	Binding key.Binding
+}
+
+var registry []Action
+
+// Register adds the action to the palette, the last registration of an ID wins
+func Register(action Action) {
+	for i, registered := range registry {
+		if registered.ID == action.ID {
+			registry[i] = action
+			return
+		}
+	}
+	registry = append(registry, action)
+}
+
+// Unregister removes the action from the palette
+func Unregister(id string) {
+	for i, registered := range registry {
+		if registered.ID == id {
+			registry = append(registry[:i], registry[i+1:]...)
+			return
+		}
+	}
+}
+
+// All returns the registered actions, in registration order
+func All() []Action {
+	return append([]Action{}, registry...)
+}
+
+// Find returns the action with this ID
+func Find(id string) (Action, bool) {
+	for _, action := range registry {
+		if action.ID == id {
+			return action, true
+		}
+	}
+	return Action{}, false
+}
+
+// Msg is sent back to the notes view when the palette closes
+type Msg interface {
+	paletteResult()
+}
+
+// RunMsg asks the view which registered the action to run it
+type RunMsg struct {
+	ID string
+}
+
+// OpenNoteMsg asks to display a note
+type OpenNoteMsg struct {
+	NoteID string
+}
+
+// CancelMsg is sent when the palette is closed without selection
+type CancelMsg struct{}
+
+func (RunMsg) paletteResult()      {}
+func (OpenNoteMsg) paletteResult() {}
+func (CancelMsg) paletteResult()   {}
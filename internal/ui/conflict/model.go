@@ -0,0 +1,250 @@
+// Package conflict implements the choice offered when an edit conflicts with the changes saved meanwhile
+package conflict
+
+import (
+	"fmt"
+	"strings"
+
+	"merlion/internal/controls"
+	"merlion/internal/edit"
+	"merlion/internal/merge"
+	"merlion/internal/styles"
+	"merlion/internal/ui/actions"
+	"merlion/internal/ui/navigation"
+	"merlion/internal/vault"
+	"merlion/internal/vault/cloud"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type choice int
+
+const (
+	keepMine choice = iota
+	keepTheirs
+	saveCopy
+	editMerge
+)
+
+func (c choice) label(deleted bool) string {
+	switch c {
+	case keepMine:
+		return "Keep mine, overwrite the changes made meanwhile"
+	case keepTheirs:
+		if deleted {
+			return "Discard my changes"
+		}
+		return "Keep theirs, discard my changes"
+	case saveCopy:
+		if deleted {
+			return "Save mine as a new note"
+		}
+		return "Save mine as a copy"
+	case editMerge:
+		return "Edit the merge, with the conflicts between markers"
+	default:
+		return "Unknown"
+	}
+}
+
+type Model struct {
+	msg          navigation.OpenConflictMsg
+	choices      []choice
+	cursor       int
+	preview      viewport.Model
+	err          error
+	width        int
+	height       int
+	keys         controls.KeyMap
+	storeManager *vault.Manager
+	themeManager *styles.ThemeManager
+}
+
+func NewModel(storeManager *vault.Manager, themeManager *styles.ThemeManager) navigation.View {
+	return Model{
+		preview:      viewport.New(0, 0),
+		keys:         controls.Keys,
+		storeManager: storeManager,
+		themeManager: themeManager,
+	}
+}
+
+func (m Model) SetCloudClient(client *cloud.Client) navigation.View {
+	m.storeManager.UpdateCloudClient(client)
+	return m
+}
+
+func (m Model) Init(args ...any) tea.Cmd {
+	return tea.WindowSize()
+}
+
+func (m Model) deleted() bool {
+	return m.msg.Result.Outcome == edit.Deleted
+}
+
+func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case navigation.OpenConflictMsg:
+		m.msg = msg
+		m.cursor = 0
+		m.err = nil
+		m.choices = []choice{keepMine, keepTheirs, saveCopy, editMerge}
+		content := msg.Result.Merge.Text
+		if m.deleted() {
+			m.choices = []choice{saveCopy, keepTheirs}
+			content = msg.Mine
+		}
+		m.preview.SetContent(m.highlight(content))
+		m.preview.GotoTop()
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.preview.Width = m.width - 6
+		m.preview.Height = max(m.height-10-len(m.choices), 3)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.msg.Session == nil {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.cursor = max(m.cursor-1, 0)
+		case key.Matches(msg, m.keys.Down):
+			m.cursor = min(m.cursor+1, len(m.choices)-1)
+		case key.Matches(msg, m.keys.PageUp):
+			m.preview.HalfViewUp()
+		case key.Matches(msg, m.keys.PageDown):
+			m.preview.HalfViewDown()
+		case key.Matches(msg, m.keys.Select):
+			return m, m.resolve(m.choices[m.cursor])
+		}
+	}
+	return m, nil
+}
+
+// resolve saves the edit as chosen, then shows the note
+func (m *Model) resolve(c choice) tea.Cmd {
+	session := m.msg.Session
+	noteID := session.NoteID
+	switch c {
+	case keepMine:
+		if _, err := session.KeepMine(m.storeManager, m.msg.Mine); err != nil {
+			log.Error("Failed to save the note", "error", err)
+			m.err = err
+			return nil
+		}
+	case keepTheirs:
+		if m.deleted() {
+			closeSession(session)
+			return navigation.SwitchUICmd(navigation.NoteUI, []any{})
+		}
+	case saveCopy:
+		note, err := session.SaveAsCopy(m.storeManager, m.msg.Mine)
+		if err != nil {
+			log.Error("Failed to save the copy", "error", err)
+			m.err = err
+			return nil
+		}
+		noteID = note.NoteID
+	case editMerge:
+		if err := session.Rebase(*m.msg.Result.Theirs); err != nil {
+			log.Error("Failed to record the edit session", "error", err)
+		}
+		if m.msg.ReturnUI == navigation.EditorUI {
+			return navigation.ContinueEditCmd(session, m.msg.Result.Merge.Text)
+		}
+		// The notes view runs $EDITOR
+		content := m.msg.Result.Merge.Text
+		return navigation.SwitchUICmd(navigation.NoteUI, []any{navigation.OpenEditorMsg{NoteId: noteID, Session: session, Content: &content}})
+	}
+	closeSession(session)
+	return navigation.SwitchUICmd(navigation.NoteUI, []any{actions.OpenNoteMsg{NoteID: noteID}})
+}
+
+// closeSession ends the journal of the resolved edit
+func closeSession(session *edit.Session) {
+	if err := session.Close(); err != nil {
+		log.Error("Failed to close the edit session", "error", err)
+	}
+}
+
+// highlight displays the conflict markers and the sections of each side in their own style
+func (m Model) highlight(content string) string {
+	s := m.themeManager.Styles()
+	lines := strings.Split(content, "\n")
+	section := s.Text
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, merge.MarkerMine):
+			lines[i] = s.Highlight.UnsetPadding().Render(line)
+			section = s.Success.UnsetPadding()
+		case line == merge.MarkerSplit:
+			lines[i] = s.Highlight.UnsetPadding().Render(line)
+			section = s.Error.UnsetPadding()
+		case strings.HasPrefix(line, merge.MarkerTheirs):
+			lines[i] = s.Highlight.UnsetPadding().Render(line)
+			section = s.Text
+		default:
+			lines[i] = section.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) View() string {
+	if m.msg.Session == nil {
+		return ""
+	}
+	s := m.themeManager.Styles()
+	width := m.width - 6
+
+	title := fmt.Sprintf("'%s' changed while you were editing it", m.msg.Session.Base.Title)
+	sections := "1 conflicting section"
+	if m.msg.Result.Merge.Conflicts > 1 {
+		sections = fmt.Sprintf("%d conflicting sections", m.msg.Result.Merge.Conflicts)
+	}
+	subtitle := sections + ", nothing is saved until you choose"
+	if m.deleted() {
+		title = fmt.Sprintf("'%s' was deleted while you were editing it", m.msg.Session.Base.Title)
+		subtitle = "Your version, nothing is saved until you choose"
+	}
+	rows := []string{
+		s.Title.Render(ansi.Truncate(title, width, "…")),
+		s.Muted.Render(ansi.Truncate(subtitle, width, "…")),
+		"",
+		m.preview.View(),
+		"",
+	}
+	for i, c := range m.choices {
+		if i == m.cursor {
+			rows = append(rows, s.SelectedItem.Render("> "+c.label(m.deleted())))
+		} else {
+			rows = append(rows, s.Text.Render("  "+c.label(m.deleted())))
+		}
+	}
+	help := fmt.Sprintf("%s choose • %s/%s scroll", m.keys.Select.Help().Key, firstKey(m.keys.PageUp), firstKey(m.keys.PageDown))
+	rows = append(rows, "", s.Muted.Render(help))
+	if m.err != nil {
+		rows = append(rows, s.Error.UnsetPadding().Render(ansi.Truncate("✗ "+m.err.Error(), width, "…")))
+	}
+	return s.ActiveContent.
+		Padding(0, 1).
+		Width(m.width - 2).
+		Height(m.height - 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func firstKey(binding key.Binding) string {
+	if keys := binding.Keys(); len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
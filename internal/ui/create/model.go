@@ -1,7 +1,12 @@
 package create
 
 import (
+	"fmt"
+	"time"
+
+	"merlion/internal/controls"
 	"merlion/internal/model"
+	"merlion/internal/templates"
 	"merlion/internal/vault"
 	"merlion/internal/vault/cloud"
 	"merlion/internal/styles"
@@ -9,9 +14,11 @@ import (
 	taginput "merlion/internal/styles/components/tagInput"
 	"merlion/internal/ui/navigation"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
 )
 
 type Model struct {
@@ -23,6 +30,13 @@ type Model struct {
 	isWorkLogInput  components.RadioInput
 	themeManager    *styles.ThemeManager
 	storeManager    *vault.Manager
+
+	// Template picker step, skipped when the vault has no template
+	templates       []templates.Template
+	template        *templates.Template
+	templateCursor  int
+	pickingTemplate bool
+	err             error
 }
 
 func (m Model) SetCloudClient(client *cloud.Client) navigation.View {
@@ -64,6 +78,22 @@ func fetchTagsCmd(m *Model) tea.Cmd {
 	}
 }
 
+type fetchedTemplatesMsg struct {
+	templates []templates.Template
+	err       error
+}
+
+func fetchTemplatesCmd(m *Model) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := m.storeManager.TemplatesDir()
+		if err != nil {
+			return fetchedTemplatesMsg{err: err}
+		}
+		tmpls, err := templates.List(dir)
+		return fetchedTemplatesMsg{templates: tmpls, err: err}
+	}
+}
+
 type prefillMsg struct {
 	Title string
 }
@@ -80,6 +110,7 @@ func (m Model) Init(args ...any) tea.Cmd {
 		textinput.Blink,
 		tea.WindowSize(),
 		fetchTagsCmd(&m),
+		fetchTemplatesCmd(&m),
 	}
 	if len(args) > 0 {
 		if title, ok := args[0].(string); ok {
@@ -97,9 +128,27 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 	case fetchedTagsMsg:
 		m.tagInput.SetAvailableTags([]string(msg))
 		return m, nil
+	case fetchedTemplatesMsg:
+		if msg.err != nil {
+			log.Error("Failed to load templates", "error", msg.err)
+		}
+		m.templates = msg.templates
+		m.template = nil
+		m.templateCursor = 0
+		m.pickingTemplate = len(m.templates) > 0
+		m.err = msg.err
+		return m, nil
 	case prefillMsg:
 		m.title.SetValue(msg.Title)
 	case tea.KeyMsg:
+		if m.pickingTemplate {
+			return m.updateTemplatePicker(msg)
+		}
+		// The quit key may be a letter, it is typed in the title and the tags
+		textFocused := m.title.Focused() || m.tagInput.Focused()
+		if key.Matches(msg, controls.Keys.Back) || (key.Matches(msg, controls.Keys.Quit) && !textFocused) {
+			return m, navigation.SwitchUICmd(navigation.NoteUI, []any{})
+		}
 		switch msg.String() {
 		case "tab":
 			if m.title.Focused() {
@@ -132,9 +181,6 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 			}
 			return m, nil
 
-		case "esc", "q":
-			return m, navigation.SwitchUICmd(navigation.NoteUI, []any{})
-
 		case "enter":
 			if m.isFavoriteInput.Focused || m.isWorkLogInput.Focused {
 				// Update radio inputs to handle their own enter key
@@ -155,8 +201,15 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 					IsWorkLog:  m.isWorkLogInput.IsChecked(),
 					Tags:       m.tagInput.GetTags(),
 				}
+				req := note.ToCreateRequest()
+				if m.template != nil {
+					// The form was prefilled with the template defaults, the user has the last word
+					fromTemplate := m.template.Apply(note.Title, time.Now())
+					req.Title = fromTemplate.Title
+					req.Content = fromTemplate.Content
+				}
 				// TODO: Handle potential Error returned
-				m.storeManager.CreateNote(note.ToCreateRequest())
+				m.storeManager.CreateNote(req)
 				return m, navigation.SwitchUICmd(navigation.NoteUI, []any{})
 			}
 		}
@@ -174,7 +227,74 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+func (m Model) updateTemplatePicker(msg tea.KeyMsg) (navigation.View, tea.Cmd) {
+	// The first choice is always an empty note
+	nbChoices := len(m.templates) + 1
+	switch {
+	case key.Matches(msg, controls.Keys.Back), key.Matches(msg, controls.Keys.Quit):
+		return m, navigation.SwitchUICmd(navigation.NoteUI, []any{})
+	case key.Matches(msg, controls.Keys.Up):
+		if m.templateCursor > 0 {
+			m.templateCursor--
+		}
+	case key.Matches(msg, controls.Keys.Down):
+		if m.templateCursor < nbChoices-1 {
+			m.templateCursor++
+		}
+	case key.Matches(msg, controls.Keys.Select):
+		m.pickingTemplate = false
+		if m.templateCursor == 0 {
+			m.template = nil
+			return m, nil
+		}
+		template := m.templates[m.templateCursor-1]
+		m.template = &template
+		m.tagInput.SetCurrentTags(append([]string{}, template.Tags...))
+		m.isFavoriteInput.SetChecked(template.IsFavorite)
+		m.isWorkLogInput.SetChecked(template.IsWorkLog)
+		if m.title.Value() == "" && template.Title != "" {
+			m.title.SetValue(template.NoteTitle("", time.Now()))
+		}
+	}
+	return m, nil
+}
+
+func (m Model) templatePickerView() string {
+	styles := m.themeManager.Styles()
+
+	formStyle := styles.ActiveContent.
+		Padding(1, 2).
+		Width(50)
+
+	choices := []string{"Empty note"}
+	for _, template := range m.templates {
+		choices = append(choices, template.Name)
+	}
+
+	rows := []string{styles.Title.Render("Create Note"), "", styles.Input.Render("Template:")}
+	for i, choice := range choices {
+		if i == m.templateCursor {
+			rows = append(rows, styles.SelectedItem.Render(fmt.Sprintf("> %s", choice)))
+		} else {
+			rows = append(rows, styles.Text.Render(fmt.Sprintf("  %s", choice)))
+		}
+	}
+	rows = append(rows, "", styles.Help.Render("enter: select • ↑/↓: move • esc: cancel"))
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		formStyle.Render(lipgloss.JoinVertical(lipgloss.Left, rows...)),
+	)
+}
+
 func (m Model) View() string {
+	if m.pickingTemplate {
+		return m.templatePickerView()
+	}
+
 	styles := m.themeManager.Styles()
 
 	formStyle := styles.ActiveContent.
@@ -182,7 +302,13 @@ func (m Model) View() string {
 		Width(50)
 
 	title := styles.Title.Render("Create Note")
+	if m.template != nil {
+		title = lipgloss.JoinHorizontal(lipgloss.Left, title, styles.Muted.Render(" from "+m.template.Name))
+	}
 	help := styles.Help.Render("enter: save/toggle • tab: next • esc: cancel")
+	if m.err != nil {
+		help = styles.Error.Render(fmt.Sprintf("Templates: %v", m.err)) + "\n" + help
+	}
 
 	return lipgloss.Place(
 		m.width,
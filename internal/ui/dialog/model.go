@@ -15,6 +15,7 @@ type Model struct {
 	title        string
 	subtitle     string
 	callback     func()
+	confirmMsg   tea.Msg
 	level        navigation.Level
 	themeManager *styles.ThemeManager
 	width        int
@@ -49,6 +50,7 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 		m.subtitle = msg.Subtitle
 		m.level = msg.Level
 		m.callback = msg.OnConfirm
+		m.confirmMsg = msg.ConfirmMsg
 		m.returnUI = msg.ReturnUI
 		m.confirm = false
 
@@ -70,6 +72,9 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 					m.callback()
 					return m, navigation.SwitchUICmd(m.returnUI, []any{})
 				}
+				if m.confirmMsg != nil {
+					return m, navigation.SwitchUICmd(m.returnUI, []any{m.confirmMsg})
+				}
 				log.Fatalf("No OnConfirm provided")
 			} else {
 				return m, navigation.SwitchUICmd(m.returnUI, []any{})
@@ -101,13 +106,13 @@ func (m Model) View() string {
 		levelStyle = styles.Text.PaddingTop(0).PaddingBottom(0)
 	}
 
-	// Render title
-	title := levelStyle.Render(m.title)
+	// Render title, the long texts are wrapped inside the dialog
+	title := levelStyle.Width(46).Align(lipgloss.Center).Render(m.title)
 
 	// Render subtitle if present
 	var subtitleSection string
 	if m.subtitle != "" {
-		subtitleSection = styles.Muted.Render(m.subtitle)
+		subtitleSection = styles.Muted.Width(46).Align(lipgloss.Center).Render(m.subtitle)
 	}
 
 	// Render buttons
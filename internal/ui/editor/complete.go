@@ -0,0 +1,129 @@
+package editor
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"merlion/internal/model"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Wiki Link Completion ---
+
+const maxSuggestions = 6
+
+// completion lists the titles matching the link being typed, e.g. `[[Proj` suggests `Project plan`
+type completion struct {
+	query       string
+	suggestions []string
+	cursor      int
+	dismissed   string // The query of the suggestions closed with esc
+}
+
+func (c completion) active() bool {
+	return len(c.suggestions) > 0
+}
+
+// linkQuery returns the text typed after an unclosed `[[` on the line of the cursor
+func linkQuery(ta textarea.Model) (string, bool) {
+	lines := strings.Split(ta.Value(), "\n")
+	if ta.Line() >= len(lines) {
+		return "", false
+	}
+	info := ta.LineInfo()
+	line := []rune(lines[ta.Line()])
+	col := min(info.StartColumn+info.ColumnOffset, len(line))
+	before := string(line[:col])
+
+	start := strings.LastIndex(before, "[[")
+	if start == -1 {
+		return "", false
+	}
+	query := before[start+2:]
+	// Closed link, or already on the heading or the alias
+	if strings.ContainsAny(query, "]|#") {
+		return "", false
+	}
+	return query, true
+}
+
+// update lists the titles matching the link under the cursor, the prefixes of the titles or of their names first
+func (c *completion) update(ta textarea.Model, notes []model.Note) {
+	query, ok := linkQuery(ta)
+	if !ok || query == c.dismissed {
+		c.close()
+		if !ok {
+			c.dismissed = ""
+		}
+		return
+	}
+	if query == c.query && c.active() {
+		return
+	}
+	c.query = query
+	c.dismissed = ""
+	c.cursor = 0
+
+	lower := strings.ToLower(strings.TrimSpace(query))
+	type match struct {
+		title  string
+		prefix bool
+	}
+	matches := []match{}
+	for _, note := range notes {
+		title := strings.ToLower(note.Title)
+		if strings.Contains(title, lower) && title != lower {
+			prefix := strings.HasPrefix(title, lower) || strings.HasPrefix(path.Base(title), lower)
+			matches = append(matches, match{title: note.Title, prefix: prefix})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].prefix != matches[j].prefix {
+			return matches[i].prefix
+		}
+		return strings.ToLower(matches[i].title) < strings.ToLower(matches[j].title)
+	})
+
+	c.suggestions = []string{}
+	for _, m := range matches[:min(len(matches), maxSuggestions)] {
+		c.suggestions = append(c.suggestions, m.title)
+	}
+}
+
+func (c *completion) move(step int) {
+	c.cursor = (c.cursor + step + len(c.suggestions)) % len(c.suggestions)
+}
+
+// dismiss hides the suggestions until the query changes
+func (c *completion) dismiss() {
+	c.dismissed = c.query
+	c.close()
+}
+
+func (c *completion) close() {
+	c.query = ""
+	c.suggestions = nil
+	c.cursor = 0
+}
+
+// accept replaces the query with the selected title, and closes the link
+func (c *completion) accept(ta *textarea.Model) {
+	title := c.suggestions[c.cursor]
+	for range []rune(c.query) {
+		*ta, _ = ta.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	ta.InsertString(title)
+
+	lines := strings.Split(ta.Value(), "\n")
+	info := ta.LineInfo()
+	after := string([]rune(lines[ta.Line()])[info.StartColumn+info.ColumnOffset:])
+	if strings.HasPrefix(after, "]]") {
+		ta.SetCursor(info.StartColumn + info.ColumnOffset + 2)
+	} else {
+		ta.InsertString("]]")
+	}
+	c.close()
+}
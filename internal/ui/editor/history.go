@@ -0,0 +1,84 @@
+package editor
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+)
+
+// Undo History ---
+
+const maxHistory = 200
+
+// snapshot is the text with the position of the cursor
+type snapshot struct {
+	value string
+	row   int
+	col   int
+}
+
+// history keeps the snapshots taken before the edits.
+// The characters typed in a row are one edit, like in most editors
+type history struct {
+	undo   []snapshot
+	redo   []snapshot
+	typing bool
+}
+
+func takeSnapshot(ta textarea.Model) snapshot {
+	info := ta.LineInfo()
+	return snapshot{value: ta.Value(), row: ta.Line(), col: info.StartColumn + info.ColumnOffset}
+}
+
+// restore sets the text, then moves the cursor back to its position
+func (s snapshot) restore(ta *textarea.Model) {
+	ta.SetValue(s.value)
+	for ta.Line() > s.row {
+		ta.CursorUp()
+	}
+	ta.SetCursor(s.col)
+}
+
+// record keeps the text before an edit, typing tells if the edit inserted a letter
+func (h *history) record(before snapshot, typing bool) {
+	if !(typing && h.typing) {
+		h.undo = append(h.undo, before)
+		if len(h.undo) > maxHistory {
+			h.undo = h.undo[1:]
+		}
+	}
+	h.typing = typing
+	h.redo = nil
+}
+
+func (h *history) Undo(ta *textarea.Model) bool {
+	return h.swap(ta, &h.undo, &h.redo)
+}
+
+func (h *history) Redo(ta *textarea.Model) bool {
+	return h.swap(ta, &h.redo, &h.undo)
+}
+
+// swap restores the last snapshot of from, and keeps the current text in to
+func (h *history) swap(ta *textarea.Model, from *[]snapshot, to *[]snapshot) bool {
+	if len(*from) == 0 {
+		return false
+	}
+	last := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, takeSnapshot(*ta))
+	last.restore(ta)
+	h.typing = false
+	return true
+}
+
+func (h *history) reset() {
+	h.undo = nil
+	h.redo = nil
+	h.typing = false
+}
+
+// isTyping tells if the key inserts a letter, the spaces end a word and so an edit
+func isTyping(runes []rune) bool {
+	return len(runes) == 1 && !strings.ContainsRune(" \t\n", runes[0])
+}
@@ -0,0 +1,431 @@
+// Package editor implements the built-in markdown editor, used instead of $EDITOR when configured
+package editor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"merlion/internal/controls"
+	"merlion/internal/edit"
+	"merlion/internal/model"
+	"merlion/internal/styles"
+	"merlion/internal/ui/actions"
+	"merlion/internal/ui/navigation"
+	"merlion/internal/vault"
+	"merlion/internal/vault/cloud"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/latentdream/merlion/lib/glamour"
+)
+
+// Below this width, the preview replaces the editor instead of being next to it
+const splitBreakpoint = 100
+
+// Delay before the text is written to the draft of the session journal, after an edit
+const draftDelay = 2 * time.Second
+
+// draftMsg writes the draft of the session, if still edited
+type draftMsg struct {
+	session *edit.Session
+}
+
+type Model struct {
+	note         *model.Note
+	session      *edit.Session
+	original     string
+	textarea     textarea.Model
+	history      history
+	completion   completion
+	preview      viewport.Model
+	renderer     *glamour.TermRenderer
+	showPreview  bool
+	confirmQuit  bool
+	draftPending bool
+	err          error
+	width        int
+	height       int
+	keys         controls.KeyMap
+	storeManager *vault.Manager
+	themeManager *styles.ThemeManager
+}
+
+func NewModel(storeManager *vault.Manager, themeManager *styles.ThemeManager) navigation.View {
+	ta := textarea.New()
+	ta.Prompt = ""
+	ta.ShowLineNumbers = false
+	// Whole notes, without the limits of a form input
+	ta.CharLimit = 0
+	ta.MaxHeight = 0
+	// Only the editor keys, the preview is toggled by the KeyMap
+	ta.KeyMap.LinePrevious = key.NewBinding(key.WithKeys("up"))
+	ta.KeyMap.LineNext = key.NewBinding(key.WithKeys("down"))
+
+	return Model{
+		textarea:     ta,
+		preview:      viewport.New(0, 0),
+		keys:         controls.Keys,
+		storeManager: storeManager,
+		themeManager: themeManager,
+	}
+}
+
+func (m Model) SetCloudClient(client *cloud.Client) navigation.View {
+	m.storeManager.UpdateCloudClient(client)
+	return m
+}
+
+func (m Model) Init(args ...any) tea.Cmd {
+	return tea.WindowSize()
+}
+
+// open loads the note in the editor, the cursor at the start.
+// The edit continues the session when given, with its content, e.g. a merge to resolve
+func (m *Model) open(msg navigation.OpenEditorMsg) error {
+	session := msg.Session
+	if session == nil {
+		var err error
+		if session, err = edit.Begin(m.storeManager, msg.NoteId); err != nil {
+			return err
+		}
+	}
+	content := session.BaseContent()
+	if msg.Content != nil {
+		content = *msg.Content
+	}
+	// Recorded, to recover the edit after a crash
+	if !session.Recorded() {
+		if err := session.Record(m.storeManager.Name, false, content); err != nil {
+			log.Error("Failed to record the edit session", "error", err)
+		}
+	} else if err := session.WriteDraft(content); err != nil {
+		log.Error("Failed to write the draft", "error", err)
+	}
+	note := session.Base
+	m.note = &note
+	m.session = session
+	m.draftPending = false
+	m.original = session.BaseContent()
+	m.history.reset()
+	m.completion = completion{}
+	m.confirmQuit = false
+	m.err = nil
+	m.applyTheme()
+	m.textarea.SetValue(content)
+	m.textarea.Focus()
+	for m.textarea.Line() > 0 {
+		m.textarea.CursorUp()
+	}
+	m.textarea.CursorStart()
+	m.renderPreview()
+	return nil
+}
+
+// applyTheme follows the theme, which may have changed since the last note
+func (m *Model) applyTheme() {
+	theme := m.themeManager.Current()
+	s := m.themeManager.Styles()
+	m.textarea.FocusedStyle.Base = lipgloss.NewStyle()
+	m.textarea.FocusedStyle.Text = s.Text.UnsetPadding()
+	m.textarea.FocusedStyle.CursorLine = s.Text.UnsetPadding()
+	m.textarea.FocusedStyle.EndOfBuffer = lipgloss.NewStyle().Foreground(theme.MutedColor)
+	m.textarea.Cursor.Style = lipgloss.NewStyle().Foreground(theme.Primary)
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStyles(m.themeManager.GetRendererStyle()),
+		glamour.WithWordWrap(max(m.paneWidth(), 20)),
+	)
+	if err != nil {
+		log.Errorf("Error while creating the preview renderer %v", err)
+		return
+	}
+	m.renderer = renderer
+}
+
+func (m Model) modified() bool {
+	return m.note != nil && m.textarea.Value() != m.original
+}
+
+// split tells if the preview is displayed next to the editor
+func (m Model) split() bool {
+	return m.showPreview && m.width >= splitBreakpoint
+}
+
+// paneWidth is the width of the editor, and of the preview
+func (m Model) paneWidth() int {
+	inner := m.width - 4 // Border and padding of the container
+	if m.split() {
+		return (inner - 3) / 2
+	}
+	return inner
+}
+
+// paneHeight is the height of the editor without the title, the suggestions and the status lines
+func (m Model) paneHeight() int {
+	return max(m.height-6-len(m.completion.suggestions), 3)
+}
+
+func (m *Model) resize() {
+	m.textarea.SetWidth(m.paneWidth())
+	m.textarea.SetHeight(m.paneHeight())
+	m.preview.Width = m.paneWidth()
+	m.preview.Height = m.paneHeight()
+	if m.renderer != nil {
+		m.renderer.SetWidth(m.paneWidth())
+	}
+	m.renderPreview()
+}
+
+// renderPreview renders the markdown being edited, scrolled along with the cursor
+func (m *Model) renderPreview() {
+	if !m.showPreview || m.renderer == nil {
+		return
+	}
+	rendered, err := m.renderer.Render(m.textarea.Value())
+	if err != nil {
+		rendered = fmt.Sprintf("Error rendering markdown: %v", err)
+	}
+	m.preview.SetContent(rendered)
+
+	lines := max(m.textarea.LineCount()-1, 1)
+	progress := float64(m.textarea.Line()) / float64(lines)
+	m.preview.SetYOffset(int(progress * float64(max(m.preview.TotalLineCount()-m.preview.Height, 0))))
+}
+
+// save writes the content, merged with the changes made meanwhile, then shows the note
+func (m *Model) save() tea.Cmd {
+	content := m.textarea.Value()
+	result, err := m.session.Save(m.storeManager, content)
+	if err != nil {
+		log.Error("Failed to save note", "error", err)
+		m.err = fmt.Errorf("failed to save the note: %w", err)
+		return nil
+	}
+	if result.Outcome == edit.Conflict || result.Outcome == edit.Deleted {
+		session := m.session
+		m.note = nil
+		m.textarea.Blur()
+		return navigation.OpenConflictCmd(session, content, result, navigation.EditorUI)
+	}
+	return m.close()
+}
+
+func (m *Model) close() tea.Cmd {
+	noteID := m.session.NoteID
+	if err := m.session.Close(); err != nil {
+		log.Error("Failed to close the edit session", "error", err)
+	}
+	m.note = nil
+	m.textarea.Blur()
+	return navigation.SwitchUICmd(navigation.NoteUI, []any{actions.OpenNoteMsg{NoteID: noteID}})
+}
+
+func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case navigation.OpenEditorMsg:
+		if err := m.open(msg); err != nil {
+			log.Error("Failed to open note in the editor", "error", err)
+			return m, navigation.SwitchUICmd(navigation.NoteUI, []any{})
+		}
+		m.resize()
+		return m, textarea.Blink
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.resize()
+		return m, nil
+
+	case draftMsg:
+		if msg.session != m.session || m.note == nil {
+			return m, nil
+		}
+		m.draftPending = false
+		if err := m.session.WriteDraft(m.textarea.Value()); err != nil {
+			log.Error("Failed to write the draft", "error", err)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.note == nil {
+			return m, nil
+		}
+		return m.handleKey(msg)
+	}
+
+	var cmd tea.Cmd
+	m.textarea, cmd = m.textarea.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (navigation.View, tea.Cmd) {
+	if m.confirmQuit {
+		switch {
+		case key.Matches(msg, m.keys.Select):
+			return m, m.close()
+		case key.Matches(msg, m.keys.Save):
+			return m, m.save()
+		case key.Matches(msg, m.keys.Back):
+			m.confirmQuit = false
+		}
+		return m, nil
+	}
+
+	if m.completion.active() {
+		switch msg.Type {
+		case tea.KeyUp:
+			m.completion.move(-1)
+			return m, nil
+		case tea.KeyDown:
+			m.completion.move(1)
+			return m, nil
+		case tea.KeyEnter, tea.KeyTab:
+			before := takeSnapshot(m.textarea)
+			m.completion.accept(&m.textarea)
+			m.history.record(before, false)
+			m.resize()
+			return m, m.draftCmd()
+		case tea.KeyEsc:
+			m.completion.dismiss()
+			m.resize()
+			return m, nil
+		}
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Save):
+		return m, m.save()
+	case key.Matches(msg, m.keys.Back):
+		if m.modified() {
+			m.confirmQuit = true
+			return m, nil
+		}
+		return m, m.close()
+	case key.Matches(msg, m.keys.TogglePreview):
+		m.showPreview = !m.showPreview
+		m.applyTheme()
+		m.resize()
+		return m, nil
+	case key.Matches(msg, m.keys.Undo):
+		if m.history.Undo(&m.textarea) {
+			return m, m.afterEdit()
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Redo):
+		if m.history.Redo(&m.textarea) {
+			return m, m.afterEdit()
+		}
+		return m, nil
+	}
+
+	// The preview replaces the editor on small screens, the keys scroll it
+	if m.showPreview && !m.split() {
+		var cmd tea.Cmd
+		m.preview, cmd = m.preview.Update(msg)
+		return m, cmd
+	}
+
+	before := takeSnapshot(m.textarea)
+	var cmd tea.Cmd
+	m.textarea, cmd = m.textarea.Update(msg)
+	if m.textarea.Value() != before.value {
+		m.history.record(before, msg.Type == tea.KeyRunes && !msg.Paste && isTyping(msg.Runes))
+		cmd = tea.Batch(cmd, m.afterEdit())
+	} else {
+		// Moving the cursor ends the edit being typed
+		m.history.typing = false
+		m.completion.update(m.textarea, m.storeManager.Notes)
+		m.resize()
+	}
+	return m, cmd
+}
+
+// afterEdit refreshes the suggestions and the preview, then records the draft
+func (m *Model) afterEdit() tea.Cmd {
+	m.err = nil
+	m.completion.update(m.textarea, m.storeManager.Notes)
+	m.resize()
+	return m.draftCmd()
+}
+
+// draftCmd writes the draft after a delay, once for all the edits made meanwhile
+func (m *Model) draftCmd() tea.Cmd {
+	if m.draftPending || !m.session.Recorded() {
+		return nil
+	}
+	m.draftPending = true
+	session := m.session
+	return tea.Tick(draftDelay, func(time.Time) tea.Msg { return draftMsg{session: session} })
+}
+
+func (m Model) View() string {
+	s := m.themeManager.Styles()
+	if m.note == nil {
+		return ""
+	}
+
+	title := m.note.Title
+	if m.modified() {
+		title += " •"
+	}
+	rows := []string{s.Title.Render(ansi.Truncate(title, m.width-8, "…")), ""}
+
+	var body string
+	switch {
+	case m.split():
+		separator := lipgloss.NewStyle().
+			Foreground(m.themeManager.Current().BorderColor).
+			Render(strings.TrimSuffix(strings.Repeat("│\n", m.paneHeight()), "\n"))
+		body = lipgloss.JoinHorizontal(lipgloss.Top, m.textarea.View(), " ", separator, " ", m.preview.View())
+	case m.showPreview:
+		body = m.preview.View()
+	default:
+		body = m.textarea.View()
+	}
+	rows = append(rows, body)
+
+	for i, suggestion := range m.completion.suggestions {
+		if i == m.completion.cursor {
+			rows = append(rows, s.SelectedItem.Render("> [["+suggestion+"]]"))
+		} else {
+			rows = append(rows, s.Muted.Render("  [["+suggestion+"]]"))
+		}
+	}
+
+	rows = append(rows, "", m.statusLine(s))
+	return s.ActiveContent.
+		Padding(0, 1).
+		Width(m.width - 2).
+		Height(m.height - 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+// statusLine shows the error, the question before closing, or the keys of the editor
+func (m Model) statusLine(s *styles.Styles) string {
+	var line string
+	switch {
+	case m.err != nil:
+		return s.Error.UnsetPadding().Render(ansi.Truncate("✗ "+m.err.Error(), m.width-6, "…"))
+	case m.confirmQuit:
+		line = fmt.Sprintf("Discard the changes? %s discard • %s save • %s keep editing",
+			m.keys.Select.Help().Key, m.keys.Save.Help().Key, m.keys.Back.Help().Key)
+		return s.Highlight.UnsetPadding().Render(ansi.Truncate(line, m.width-6, "…"))
+	case m.completion.active():
+		line = "↑/↓ choose • enter/tab insert • esc dismiss"
+	default:
+		preview := "preview"
+		if m.showPreview {
+			preview = "hide preview"
+		}
+		line = fmt.Sprintf("%s save • %s cancel • %s %s • %s undo • %s redo • [[ link",
+			m.keys.Save.Help().Key, m.keys.Back.Help().Key, m.keys.TogglePreview.Help().Key, preview,
+			m.keys.Undo.Help().Key, m.keys.Redo.Help().Key)
+	}
+	return s.Muted.Render(ansi.Truncate(line, m.width-6, "…"))
+}
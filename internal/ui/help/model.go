@@ -0,0 +1,122 @@
+// Package help implements the help overlay, listing the effective key bindings
+package help
+
+import (
+	"strings"
+
+	"merlion/internal/controls"
+	"merlion/internal/styles"
+	"merlion/internal/ui/actions"
+	"merlion/internal/ui/navigation"
+	"merlion/internal/vault/cloud"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const maxWidth = 72
+
+type Model struct {
+	bindings     []key.Binding
+	offset       int
+	width        int
+	height       int
+	keys         controls.KeyMap
+	themeManager *styles.ThemeManager
+}
+
+func NewModel(themeManager *styles.ThemeManager) navigation.View {
+	return Model{
+		keys:         controls.Keys,
+		themeManager: themeManager,
+	}
+}
+
+func (m Model) SetCloudClient(client *cloud.Client) navigation.View {
+	return m
+}
+
+func (m Model) Init(args ...any) tea.Cmd {
+	return tea.WindowSize()
+}
+
+// visibleRows returns the number of bindings displayed at once
+func (m Model) visibleRows() int {
+	return max(m.height-8, 3)
+}
+
+func (m *Model) scroll(rows int) {
+	m.offset = max(min(m.offset+rows, len(m.bindings)-m.visibleRows()), 0)
+}
+
+func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case navigation.OpenHelpMsg:
+		// Built from the KeyMap, so it follows the config
+		m.bindings = []key.Binding{}
+		for _, binding := range m.keys.ToSlice() {
+			if binding.Enabled() {
+				m.bindings = append(m.bindings, binding)
+			}
+		}
+		m.offset = 0
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scroll(0)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Help), key.Matches(msg, m.keys.Quit):
+			return m, navigation.SwitchUICmd(navigation.NoteUI, []any{actions.CancelMsg{}})
+		case key.Matches(msg, m.keys.Up):
+			m.scroll(-1)
+		case key.Matches(msg, m.keys.Down):
+			m.scroll(1)
+		case key.Matches(msg, m.keys.PageUp):
+			m.scroll(-m.visibleRows())
+		case key.Matches(msg, m.keys.PageDown):
+			m.scroll(m.visibleRows())
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	s := m.themeManager.Styles()
+	width := min(maxWidth, m.width-4)
+
+	title := "Key bindings"
+	if controls.Preset != controls.PresetDefault {
+		title += " (" + controls.Preset + ")"
+	}
+	rows := []string{s.Title.Render(title), ""}
+
+	keyWidth := 0
+	for _, binding := range m.bindings {
+		keyWidth = max(keyWidth, lipgloss.Width(binding.Help().Key))
+	}
+	end := min(m.offset+m.visibleRows(), len(m.bindings))
+	for _, binding := range m.bindings[m.offset:end] {
+		help := binding.Help()
+		padding := strings.Repeat(" ", keyWidth-lipgloss.Width(help.Key)+2)
+		rows = append(rows, s.Highlight.Render(help.Key)+padding+s.Text.Render(help.Desc))
+	}
+
+	footer := "esc close"
+	if len(m.bindings) > m.visibleRows() {
+		footer = "↑/↓ scroll • " + footer
+	}
+	rows = append(rows, "", s.Muted.Render(footer))
+
+	box := s.ActiveContent.
+		Padding(0, 1).
+		Width(width).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
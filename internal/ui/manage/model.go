@@ -1,6 +1,9 @@
 package manage
 
 import (
+	"fmt"
+
+	"merlion/internal/links"
 	"merlion/internal/model"
 	"merlion/internal/vault"
 	"merlion/internal/vault/cloud"
@@ -28,6 +31,9 @@ type Model struct {
 	isFavoriteInput components.RadioInput
 	isWorkLogInput  components.RadioInput
 	tagInput        taginput.Model
+	rename          *links.Rename // Preview of the links rewritten by the rename
+	report          string
+	err             error
 }
 
 func NewModel(
@@ -88,9 +94,29 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 		m.title.SetValue(note.Title)
 		m.tagInput.SetCurrentTags(note.Tags)
 		m.tagInput.SetAvailableTags(m.storeManager.GetTags())
+		m.rename = nil
+		m.report = ""
+		m.err = nil
 		return m, tea.Batch(spinner.Tick, cmd)
 
 	case tea.KeyMsg:
+		if m.report != "" {
+			switch msg.String() {
+			case "enter", "esc", "q":
+				return m, navigation.SwitchUICmd(navigation.NoteUI, []any{})
+			}
+			return m, nil
+		}
+		if m.rename != nil {
+			switch msg.String() {
+			case "enter":
+				return m, m.save()
+			case "esc":
+				m.rename = nil
+			}
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "tab":
 			if m.title.Focused() {
@@ -143,16 +169,22 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 				return m, cmd
 			}
 			if m.title.Focused() {
-				// Save all changes
-				m.note.Title = m.title.Value()
-				m.note.IsFavorite = m.isFavoriteInput.IsChecked()
-				m.note.IsWorkLog = m.isWorkLogInput.IsChecked()
-				m.note.Tags = m.tagInput.GetTags()
-				_, err := m.storeManager.UpdateNote(m.note.NoteID, m.note.ToCreateRequest())
+				if m.title.Value() == m.note.Title {
+					return m, m.save()
+				}
+				// Preview the links to rewrite before renaming
+				rename, err := links.PlanRename(m.storeManager, m.note.NoteID, m.title.Value())
 				if err != nil {
-					log.Error("Failed to update note", "error", err)
+					m.err = err
+					return m, nil
 				}
-				return m, navigation.SwitchUICmd(navigation.NoteUI, []any{})
+				m.err = nil
+				if len(rename.Edits) == 0 {
+					m.rename = rename
+					return m, m.save()
+				}
+				m.rename = rename
+				return m, nil
 			}
 
 		}
@@ -171,6 +203,76 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// save renames the note with its links and updates its metadata in the same write,
+// or only updates its metadata when the title is unchanged
+func (m *Model) save() tea.Cmd {
+	isFavorite := m.isFavoriteInput.IsChecked()
+	isWorkLog := m.isWorkLogInput.IsChecked()
+	tags := m.tagInput.GetTags()
+
+	var err error
+	if m.rename != nil {
+		m.rename.Update = func(req *model.CreateNoteRequest) {
+			req.IsFavorite = &isFavorite
+			req.IsWorkLog = &isWorkLog
+			req.Tags = tags
+		}
+		var renamed *model.Note
+		renamed, err = m.rename.Apply(m.storeManager)
+		if err == nil && len(m.rename.Edits) > 0 {
+			m.report = fmt.Sprintf("Renamed '%s' to '%s', updated %s", m.rename.OldTitle, renamed.Title, m.rename.Summary())
+		}
+		m.rename = nil
+	} else {
+		var note *model.Note
+		note, err = m.storeManager.GetFullNote(m.note.NoteID)
+		if err == nil {
+			note.IsFavorite = isFavorite
+			note.IsWorkLog = isWorkLog
+			note.Tags = tags
+			_, err = m.storeManager.UpdateNote(note.NoteID, note.ToCreateRequest())
+		}
+	}
+	if err != nil {
+		log.Error("Failed to update note", "error", err)
+		m.report = ""
+		m.err = err
+		return nil
+	}
+	if m.report != "" {
+		return nil
+	}
+	return navigation.SwitchUICmd(navigation.NoteUI, []any{})
+}
+
+// renameView previews the notes whose links are rewritten by the rename, or reports them once renamed
+func (m Model) renameView(styles *styles.Styles) []string {
+	if m.report != "" {
+		return []string{
+			styles.Title.Render("Note renamed"),
+			"",
+			styles.Text.Render(m.report),
+			"",
+			styles.Help.Render("enter: close"),
+		}
+	}
+	rows := []string{
+		styles.Title.Render("Rename Note"),
+		"",
+		styles.Text.Render(fmt.Sprintf("'%s' to '%s' updates %s:", m.rename.OldTitle, m.rename.NewTitle, m.rename.Summary())),
+		"",
+	}
+	const maxNotes = 8
+	for i, edit := range m.rename.Edits {
+		if i == maxNotes {
+			rows = append(rows, styles.Muted.Render(fmt.Sprintf("  and %d more notes", len(m.rename.Edits)-i)))
+			break
+		}
+		rows = append(rows, styles.Text.Render(fmt.Sprintf("  %s (%d)", edit.Note.Title, len(edit.Links))))
+	}
+	return append(rows, "", styles.Help.Render("enter: rename • esc: back"))
+}
+
 func (m Model) View() string {
 
 	if m.isLoading {
@@ -198,8 +300,21 @@ func (m Model) View() string {
 		Padding(1, 2).
 		Width(50)
 
+	if m.rename != nil || m.report != "" {
+		return lipgloss.Place(
+			m.width,
+			m.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			formStyle.Render(lipgloss.JoinVertical(lipgloss.Left, m.renameView(styles)...)),
+		)
+	}
+
 	title := styles.Title.Render("Manage Note")
 	help := styles.Help.Render("enter: save/toggle • tab: next • esc: cancel")
+	if m.err != nil {
+		help = lipgloss.JoinVertical(lipgloss.Left, styles.Error.UnsetPadding().Render("✗ "+m.err.Error()), help)
+	}
 
 	return lipgloss.Place(
 		m.width,
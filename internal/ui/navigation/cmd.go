@@ -1,6 +1,7 @@
 package navigation
 
 import (
+	"merlion/internal/edit"
 	"merlion/internal/vault/cloud"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,6 +14,10 @@ const (
 	CreateUI
 	ManageUI
 	DialogUI
+	PaletteUI
+	HelpUI
+	EditorUI
+	ConflictUI
 )
 
 type Level int
@@ -36,13 +41,35 @@ type OpenDialogMsg struct {
 	Subtitle  string
 	Level     Level
 	OnConfirm func()
-	ReturnUI  CurrentUI
+	// ConfirmMsg is passed to the Init of the return view on confirm, for the long operations run by the view
+	ConfirmMsg tea.Msg
+	ReturnUI   CurrentUI
 }
 
 type OpenManageMsg struct {
 	NoteId string
 }
 
+// OpenEditorMsg opens the note in the built-in editor.
+// Session and Content are set to continue an edit, e.g. to resolve the conflicts of a merge
+type OpenEditorMsg struct {
+	NoteId  string
+	Session *edit.Session
+	Content *string
+}
+
+// OpenConflictMsg asks how to save an edit conflicting with the changes made meanwhile
+type OpenConflictMsg struct {
+	Session  *edit.Session
+	Mine     string
+	Result   *edit.Result
+	ReturnUI CurrentUI // The editor of the session
+}
+
+type OpenPaletteMsg struct{}
+
+type OpenHelpMsg struct{}
+
 type View interface {
 	Init(...any) tea.Cmd
 	Update(tea.Msg) (View, tea.Cmd)
@@ -64,8 +91,46 @@ func AskConfirmationCmd(title string, subtitle string, level Level, onConfirm fu
 	}
 }
 
+// AskConfirmationMsgCmd asks a confirmation, then sends confirmMsg to the return view
+func AskConfirmationMsgCmd(title string, subtitle string, level Level, confirmMsg tea.Msg, returnUI CurrentUI) tea.Cmd {
+	return func() tea.Msg {
+		return OpenDialogMsg{Title: title, Subtitle: subtitle, Level: level, ConfirmMsg: confirmMsg, ReturnUI: returnUI}
+	}
+}
+
 func OpenManageViewCmd(noteId string) tea.Cmd {
 	return func() tea.Msg {
 		return OpenManageMsg{NoteId: noteId}
 	}
 }
+
+func OpenEditorCmd(noteId string) tea.Cmd {
+	return func() tea.Msg {
+		return OpenEditorMsg{NoteId: noteId}
+	}
+}
+
+// ContinueEditCmd opens the built-in editor on the content of an edit session
+func ContinueEditCmd(session *edit.Session, content string) tea.Cmd {
+	return func() tea.Msg {
+		return OpenEditorMsg{NoteId: session.NoteID, Session: session, Content: &content}
+	}
+}
+
+func OpenConflictCmd(session *edit.Session, mine string, result *edit.Result, returnUI CurrentUI) tea.Cmd {
+	return func() tea.Msg {
+		return OpenConflictMsg{Session: session, Mine: mine, Result: result, ReturnUI: returnUI}
+	}
+}
+
+func OpenPaletteCmd() tea.Cmd {
+	return func() tea.Msg {
+		return OpenPaletteMsg{}
+	}
+}
+
+func OpenHelpCmd() tea.Cmd {
+	return func() tea.Msg {
+		return OpenHelpMsg{}
+	}
+}
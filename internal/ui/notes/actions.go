@@ -0,0 +1,188 @@
+package Notes
+
+import (
+	"strings"
+	"time"
+
+	"merlion/internal/config"
+	"merlion/internal/controls"
+	"merlion/internal/ui/actions"
+	"merlion/internal/ui/navigation"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Command Palette Actions ---
+
+type actionHandler func(m *Model) tea.Cmd
+
+var actionHandlers = map[string]actionHandler{}
+
+// registerAction adds the action to the command palette, handled by this view
+func registerAction(id string, title string, binding key.Binding, handler actionHandler) {
+	actions.Register(actions.Action{ID: id, Title: title, Binding: binding})
+	actionHandlers[id] = handler
+}
+
+// registerActions adds the actions of the view, with the shortcuts of the loaded key bindings
+func registerActions() {
+	keys := controls.Keys
+	registerAction("note.create", "New note", keys.Create, createNote)
+	registerAction("note.edit", "Edit the current note", keys.Edit, editNote)
+	registerAction("note.edit-builtin", "Edit the current note in the built-in editor", key.Binding{}, editNoteBuiltin)
+	registerAction("note.manage", "Manage the current note info", keys.Manage, manageNote)
+	registerAction("note.delete", "Delete the current note", keys.Delete, deleteNote)
+	registerAction("worklog.today", "Open today's work log", keys.OpenToday, func(m *Model) tea.Cmd {
+		return openWorkLog(m, time.Now())
+	})
+	registerAction("worklog.yesterday", "Open yesterday's work log", keys.OpenYesterday, func(m *Model) tea.Cmd {
+		return openWorkLog(m, time.Now().AddDate(0, 0, -1))
+	})
+	for _, tab := range builtinTabs() {
+		registerAction("tab."+tab.String(), "Show "+tab.String(), key.Binding{}, showTab(tab.String()))
+	}
+	registerAction("tab.new", "New tab from the filter", keys.NewTab, openNewTab)
+	registerAction("tab.edit", "Edit the current tab", keys.EditTab, openEditTab)
+	registerAction("tab.remove", "Remove the current tab", keys.RemoveTab, removeTab)
+	registerAction("tab.move-left", "Move the current tab left", keys.MoveTabLeft, moveTab(-1))
+	registerAction("tab.move-right", "Move the current tab right", keys.MoveTabRight, moveTab(1))
+	registerAction("selection.toggle", "Select the current note", keys.ToggleSelect, toggleSelect)
+	registerAction("selection.all", "Select all the filtered notes", keys.SelectAll, selectAll)
+	registerAction("selection.clear", "Clear the selection", key.Binding{}, clearSelection)
+	registerAction("selection.actions", "Actions on the selected notes", keys.Manage, openBulkMenu)
+	registerAction("note.move-to-vault", "Move to vault…", key.Binding{}, openTransferMenu(bulkMoveToVault))
+	registerAction("note.copy-to-vault", "Copy to vault…", key.Binding{}, openTransferMenu(bulkCopyToVault))
+	registerAction("notes.sort", "Sort notes", keys.SortGroups, openSortMenu)
+	registerAction("vault.next", "Switch to the next vault", keys.ToggleStore, nextStore)
+	registerAction("view.theme", "Toggle theme", keys.ToggleTheme, func(m *Model) tea.Cmd {
+		toggleTheme(m)
+		return nil
+	})
+	registerAction("view.info", "Toggle note info", keys.ToggleInfo, func(m *Model) tea.Cmd {
+		m.noteRenderer.ToggleHideInfo()
+		return nil
+	})
+	registerAction("view.info-position", "Toggle note info position", keys.ToggleInfoPosition, func(m *Model) tea.Cmd {
+		m.noteRenderer.ToggleHidePosition()
+		return nil
+	})
+	registerAction("view.compact", "Toggle compact view", keys.ToggleCompactView, func(m *Model) tea.Cmd {
+		m.ToggleFullscreen()
+		return tea.WindowSize()
+	})
+	registerAction("app.help", "Show the key bindings", keys.Help, func(m *Model) tea.Cmd {
+		return navigation.OpenHelpCmd()
+	})
+	registerAction("app.quit", "Quit", keys.Quit, func(m *Model) tea.Cmd {
+		return tea.Quit
+	})
+}
+
+const userTabActionPrefix = "tab.user."
+
+// registerTabActions adds a palette action to show each user tab, replacing the ones of removed tabs
+func registerTabActions(cfg *config.UserConfig) {
+	for id := range actionHandlers {
+		if strings.HasPrefix(id, userTabActionPrefix) {
+			actions.Unregister(id)
+			delete(actionHandlers, id)
+		}
+	}
+	for _, tab := range cfg.Tabs {
+		registerAction(userTabActionPrefix+tab.Name, "Show "+tab.Name, key.Binding{}, showTab(tab.Name))
+	}
+}
+
+// runAction runs the action selected in the command palette
+func (m *Model) runAction(id string) tea.Cmd {
+	handler, ok := actionHandlers[id]
+	if !ok {
+		return nil
+	}
+	return handler(m)
+}
+
+func createNote(m *Model) tea.Cmd {
+	return navigation.SwitchUICmd(navigation.CreateUI, []any{})
+}
+
+func editNote(m *Model) tea.Cmd {
+	noteToEdit := m.getCurrentNote(true)
+	if noteToEdit == nil {
+		return nil
+	}
+	if noteToEdit.Content != nil {
+		return m.openEditor()
+	}
+	// We don't have the content locally.. fetch
+	m.loading = true
+	return fetchNoteContent(m.storeManager, noteToEdit.NoteID)
+}
+
+// editNoteBuiltin opens the built-in editor whatever the config, e.g. for a quick fix
+func editNoteBuiltin(m *Model) tea.Cmd {
+	note := m.getCurrentNote(true)
+	if note == nil {
+		return nil
+	}
+	return navigation.OpenEditorCmd(note.NoteID)
+}
+
+func manageNote(m *Model) tea.Cmd {
+	noteToManage := m.getCurrentNote(true)
+	if noteToManage == nil {
+		return nil
+	}
+	m.loading = true
+	return navigation.OpenManageViewCmd(noteToManage.NoteID)
+}
+
+func deleteNote(m *Model) tea.Cmd {
+	noteToDelete := m.getCurrentNote(true)
+	if noteToDelete == nil || noteToDelete.NoteID == "" {
+		return nil
+	}
+	storeManager := m.storeManager
+	return navigation.AskConfirmationCmd(
+		"Are you sure you want to delete this note ?",
+		noteToDelete.Title,
+		navigation.DangerLvl,
+		func() {
+			storeManager.DeleteNote(noteToDelete.NoteID)
+		},
+		navigation.NoteUI,
+	)
+}
+
+func openWorkLog(m *Model, day time.Time) tea.Cmd {
+	m.loading = true
+	return openWorkLogCmd(m.storeManager, m.themeManager.Config, day)
+}
+
+func showTab(name string) actionHandler {
+	return func(m *Model) tea.Cmd {
+		m.fileterTabs.SelectTab(name)
+		m.focusedPane = noteList
+		m.refreshNotesView()
+		return nil
+	}
+}
+
+func nextStore(m *Model) tea.Cmd {
+	m.storeManager.NextStore()
+	m.loading = true
+	m.noteRenderer.SetNote(nil)
+	m.noteRenderer.Render()
+	return m.loadNotes()
+}
+
+func openSortMenu(m *Model) tea.Cmd {
+	tab := m.fileterTabs.CurrentTab()
+	if tab.Kind == Tags {
+		return nil
+	}
+	m.focusedPane = noteList
+	m.sortMenu.Open(tab.String(), m.themeManager.Config.NotesSortFor(tab.String()))
+	return nil
+}
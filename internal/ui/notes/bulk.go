@@ -0,0 +1,601 @@
+package Notes
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"merlion/internal/controls"
+	"merlion/internal/styles"
+	"merlion/internal/ui/navigation"
+	"merlion/internal/vault"
+	"merlion/internal/vault/files"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Bulk Actions ---
+
+type bulkAction int
+
+const (
+	bulkAddTags bulkAction = iota
+	bulkRemoveTags
+	bulkFavorite
+	bulkUnfavorite
+	bulkWorkLog
+	bulkUnworkLog
+	bulkMoveToVault
+	bulkCopyToVault
+	bulkMoveToFolder
+	bulkExport
+	bulkDelete
+)
+
+func (a bulkAction) String() string {
+	switch a {
+	case bulkAddTags:
+		return "Add tags"
+	case bulkRemoveTags:
+		return "Remove tags"
+	case bulkFavorite:
+		return "Add to favorites"
+	case bulkUnfavorite:
+		return "Remove from favorites"
+	case bulkWorkLog:
+		return "Mark as work log"
+	case bulkUnworkLog:
+		return "Unmark as work log"
+	case bulkMoveToVault:
+		return "Move to vault"
+	case bulkCopyToVault:
+		return "Copy to vault"
+	case bulkMoveToFolder:
+		return "Move to folder"
+	case bulkExport:
+		return "Export to a folder"
+	case bulkDelete:
+		return "Delete"
+	default:
+		return "Unknown"
+	}
+}
+
+// progressLabel is displayed while the action runs, and doneLabel in the summary
+func (a bulkAction) progressLabel() string {
+	switch a {
+	case bulkAddTags:
+		return "Tagging"
+	case bulkRemoveTags:
+		return "Untagging"
+	case bulkFavorite, bulkUnfavorite:
+		return "Updating favorites"
+	case bulkWorkLog, bulkUnworkLog:
+		return "Updating work logs"
+	case bulkMoveToVault, bulkMoveToFolder:
+		return "Moving"
+	case bulkCopyToVault:
+		return "Copying"
+	case bulkExport:
+		return "Exporting"
+	case bulkDelete:
+		return "Deleting"
+	default:
+		return "Updating"
+	}
+}
+
+func (a bulkAction) doneLabel() string {
+	switch a {
+	case bulkAddTags:
+		return "tagged"
+	case bulkRemoveTags:
+		return "untagged"
+	case bulkMoveToVault, bulkMoveToFolder:
+		return "moved"
+	case bulkCopyToVault:
+		return "copied"
+	case bulkExport:
+		return "exported"
+	case bulkDelete:
+		return "deleted"
+	default:
+		return "updated"
+	}
+}
+
+// input returns the placeholder of the value asked before running the action, empty when not needed
+func (a bulkAction) input() string {
+	switch a {
+	case bulkAddTags, bulkRemoveTags:
+		return "tags, separated by commas"
+	case bulkMoveToFolder:
+		return "folder of the vault, empty for the root"
+	case bulkExport:
+		return "path of the folder"
+	default:
+		return ""
+	}
+}
+
+// bulkOperation is an action with its argument, on the selected notes
+type bulkOperation struct {
+	action  bulkAction
+	arg     string // Tags, vault, folder or export path
+	noteIDs []string
+}
+
+// bulkConfirmedMsg is sent back by the confirmation dialog
+type bulkConfirmedMsg struct {
+	op bulkOperation
+}
+
+func (o bulkOperation) tags() []string {
+	return strings.FieldsFunc(o.arg, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+}
+
+// confirmCmd asks one confirmation for all the notes of the operation
+func (o bulkOperation) confirmCmd(manager *vault.Manager) tea.Cmd {
+	if len(o.noteIDs) == 0 {
+		return nil
+	}
+	count := "1 note"
+	if len(o.noteIDs) > 1 {
+		count = fmt.Sprintf("%d notes", len(o.noteIDs))
+	}
+
+	var title string
+	level := navigation.InfoLvl
+	switch o.action {
+	case bulkAddTags:
+		title = fmt.Sprintf("Add %s to %s ?", strings.Join(o.tags(), ", "), count)
+	case bulkRemoveTags:
+		title = fmt.Sprintf("Remove %s from %s ?", strings.Join(o.tags(), ", "), count)
+	case bulkMoveToVault:
+		title = fmt.Sprintf("Move %s to %s ?", count, o.arg)
+		level = navigation.DangerLvl
+	case bulkCopyToVault:
+		title = fmt.Sprintf("Copy %s to %s ?", count, o.arg)
+	case bulkMoveToFolder:
+		folder := o.arg
+		if folder == "" {
+			folder = "the root of the vault"
+		}
+		title = fmt.Sprintf("Move %s to %s ?", count, folder)
+	case bulkExport:
+		title = fmt.Sprintf("Export %s to %s ?", count, o.arg)
+	case bulkDelete:
+		title = fmt.Sprintf("Are you sure you want to delete %s ?", count)
+		level = navigation.DangerLvl
+	default:
+		title = fmt.Sprintf("%s: %s ?", o.action, count)
+	}
+
+	titles := []string{}
+	for _, id := range o.noteIDs[:min(3, len(o.noteIDs))] {
+		if note := manager.SearchByID(id); note != nil {
+			titles = append(titles, note.Title)
+		}
+	}
+	subtitle := strings.Join(titles, ", ")
+	if more := len(o.noteIDs) - len(titles); more > 0 {
+		subtitle += fmt.Sprintf(" and %d more", more)
+	}
+	return navigation.AskConfirmationMsgCmd(title, subtitle, level, bulkConfirmedMsg{op: o}, navigation.NoteUI)
+}
+
+// apply runs the operation on one note, the notice tells what was done differently, e.g. a renamed copy
+func (o bulkOperation) apply(manager *vault.Manager, noteID string) (string, error) {
+	switch o.action {
+	case bulkDelete:
+		return "", manager.DeleteNote(noteID)
+	case bulkMoveToVault, bulkCopyToVault:
+		transfer := manager.CopyToStore
+		if o.action == bulkMoveToVault {
+			transfer = manager.MoveToStore
+		}
+		result, err := transfer(noteID, o.arg)
+		if result == nil {
+			return "", err
+		}
+		return transferNotice(result), err
+	}
+
+	note, err := manager.GetFullNote(noteID)
+	if err != nil {
+		return "", err
+	}
+	req := note.ToCreateRequest()
+	yes, no := true, false
+	switch o.action {
+	case bulkAddTags:
+		req.Tags = slices.Clone(note.Tags)
+		for _, tag := range o.tags() {
+			if !slices.ContainsFunc(req.Tags, func(existing string) bool { return strings.EqualFold(existing, tag) }) {
+				req.Tags = append(req.Tags, tag)
+			}
+		}
+	case bulkRemoveTags:
+		req.Tags = slices.DeleteFunc(slices.Clone(note.Tags), func(existing string) bool {
+			return slices.ContainsFunc(o.tags(), func(tag string) bool { return strings.EqualFold(existing, tag) })
+		})
+	case bulkFavorite:
+		req.IsFavorite = &yes
+	case bulkUnfavorite:
+		req.IsFavorite = &no
+	case bulkWorkLog:
+		req.IsWorkLog = &yes
+	case bulkUnworkLog:
+		req.IsWorkLog = &no
+	case bulkMoveToFolder:
+		req.Title = path.Join(o.arg, path.Base(note.Title))
+		if req.Title == note.Title {
+			return "", nil
+		}
+	case bulkExport:
+		// The folder is only created once the export is confirmed
+		exportTo, err := files.NewClient(o.arg, "")
+		if err != nil {
+			return "", err
+		}
+		req.Title = files.SafeTitle(note.Title)
+		req.CreatedAt = &note.CreatedAt
+		req.UpdatedAt = &note.UpdatedAt
+		req.Properties = note.Properties
+		req.Aliases = note.Aliases
+		_, err = exportTo.CreateNote(req)
+		return "", err
+	}
+	_, err = manager.UpdateNote(noteID, req)
+	return "", err
+}
+
+// transferNotice describes the title taken in the other vault and the attachments left behind
+func transferNotice(transfer *vault.Transfer) string {
+	notices := []string{}
+	if transfer.Renamed {
+		notices = append(notices, fmt.Sprintf("saved as '%s'", transfer.Note.Title))
+	}
+	if len(transfer.Skipped) > 0 {
+		notices = append(notices, fmt.Sprintf("attachments not copied: %s", strings.Join(transfer.Skipped, ", ")))
+	}
+	return strings.Join(notices, ", ")
+}
+
+// cleanFolder validates a folder of a Files vault, relative to its root
+func cleanFolder(folder string) (string, error) {
+	folder = strings.Trim(filepath.ToSlash(strings.TrimSpace(folder)), "/")
+	if folder == "" {
+		return "", nil
+	}
+	folder = path.Clean(folder)
+	if folder == ".." || strings.HasPrefix(folder, "../") {
+		return "", fmt.Errorf("the folder must be inside the vault")
+	}
+	if folder == "." {
+		return "", nil
+	}
+	return folder, nil
+}
+
+// Bulk Menu ---
+
+// bulkMenu replaces the note list while choosing the action to run on the selected notes
+type bulkMenu struct {
+	open          bool
+	noteIDs       []string
+	actions       []bulkAction
+	cursor        int
+	vaults        []string
+	vaultCursor   int
+	choosingVault bool
+	input         textinput.Model
+	editing       bool
+	err           error
+	keys          controls.KeyMap
+}
+
+func newBulkMenu() bulkMenu {
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 256
+	return bulkMenu{input: input, keys: controls.Keys}
+}
+
+func (b *bulkMenu) Open(noteIDs []string, manager *vault.Manager) {
+	b.open = true
+	b.noteIDs = noteIDs
+	b.cursor = 0
+	b.editing = false
+	b.choosingVault = false
+	b.err = nil
+	b.input.Blur()
+
+	b.vaults = []string{}
+	for _, name := range manager.StoreNames() {
+		if name != manager.Name {
+			b.vaults = append(b.vaults, name)
+		}
+	}
+	b.actions = []bulkAction{bulkAddTags, bulkRemoveTags, bulkFavorite, bulkUnfavorite, bulkWorkLog, bulkUnworkLog}
+	if len(b.vaults) > 0 {
+		b.actions = append(b.actions, bulkMoveToVault, bulkCopyToVault)
+	}
+	// Only the notes of a Files vault are in folders
+	if manager.StoreType() == files.Type {
+		b.actions = append(b.actions, bulkMoveToFolder)
+	}
+	b.actions = append(b.actions, bulkExport, bulkDelete)
+}
+
+// OpenAt opens the menu with the action under the cursor, and its vaults already listed for a transfer
+func (b *bulkMenu) OpenAt(noteIDs []string, manager *vault.Manager, action bulkAction) {
+	b.Open(noteIDs, manager)
+	idx := slices.Index(b.actions, action)
+	if idx < 0 {
+		b.err = fmt.Errorf("no other vault to %s", strings.ToLower(action.String()))
+		return
+	}
+	b.cursor = idx
+	b.choosingVault = action == bulkMoveToVault || action == bulkCopyToVault
+	b.vaultCursor = 0
+}
+
+// Update returns the operation to confirm once the action and its argument are chosen
+func (b *bulkMenu) Update(msg tea.KeyMsg) (*bulkOperation, tea.Cmd) {
+	action := b.actions[b.cursor]
+	if b.editing {
+		switch {
+		case key.Matches(msg, b.keys.Select):
+			op, err := b.operation(action, b.input.Value())
+			if err != nil {
+				b.err = err
+				return nil, nil
+			}
+			b.close()
+			return op, nil
+		case key.Matches(msg, b.keys.Back):
+			b.editing = false
+			b.err = nil
+			b.input.Blur()
+			return nil, nil
+		}
+		var cmd tea.Cmd
+		b.input, cmd = b.input.Update(msg)
+		return nil, cmd
+	}
+
+	if b.choosingVault {
+		switch {
+		case key.Matches(msg, b.keys.Back):
+			b.choosingVault = false
+		case key.Matches(msg, b.keys.Up):
+			b.vaultCursor = max(b.vaultCursor-1, 0)
+		case key.Matches(msg, b.keys.Down):
+			b.vaultCursor = min(b.vaultCursor+1, len(b.vaults)-1)
+		case key.Matches(msg, b.keys.Select):
+			op, _ := b.operation(action, b.vaults[b.vaultCursor])
+			b.close()
+			return op, nil
+		}
+		return nil, nil
+	}
+
+	switch {
+	case key.Matches(msg, b.keys.Back), key.Matches(msg, b.keys.Manage):
+		b.close()
+	case key.Matches(msg, b.keys.Up):
+		b.cursor = max(b.cursor-1, 0)
+	case key.Matches(msg, b.keys.Down):
+		b.cursor = min(b.cursor+1, len(b.actions)-1)
+	case key.Matches(msg, b.keys.Select):
+		if action == bulkMoveToVault || action == bulkCopyToVault {
+			b.choosingVault = true
+			b.vaultCursor = 0
+			return nil, nil
+		}
+		if placeholder := action.input(); placeholder != "" {
+			b.editing = true
+			b.err = nil
+			b.input.Placeholder = placeholder
+			b.input.SetValue("")
+			return nil, b.input.Focus()
+		}
+		op, _ := b.operation(action, "")
+		b.close()
+		return op, nil
+	}
+	return nil, nil
+}
+
+// operation validates the argument of the action
+func (b bulkMenu) operation(action bulkAction, arg string) (*bulkOperation, error) {
+	op := &bulkOperation{action: action, arg: strings.TrimSpace(arg), noteIDs: b.noteIDs}
+	switch action {
+	case bulkAddTags, bulkRemoveTags:
+		if len(op.tags()) == 0 {
+			return nil, fmt.Errorf("expected at least one tag")
+		}
+	case bulkMoveToFolder:
+		folder, err := cleanFolder(op.arg)
+		if err != nil {
+			return nil, err
+		}
+		op.arg = folder
+	case bulkExport:
+		if op.arg == "" {
+			return nil, fmt.Errorf("expected the path of a folder")
+		}
+	}
+	return op, nil
+}
+
+func (b *bulkMenu) close() {
+	b.open = false
+	b.editing = false
+	b.choosingVault = false
+	b.input.Blur()
+}
+
+func (b bulkMenu) View(st *styles.Styles, width int, height int) string {
+	count := "1 note"
+	if len(b.noteIDs) > 1 {
+		count = fmt.Sprintf("%d notes", len(b.noteIDs))
+	}
+	rows := []string{st.Title.Render("Actions on " + count), ""}
+	for i, action := range b.actions {
+		label := action.String()
+		if i == b.cursor && b.editing {
+			label += ": " + b.input.View()
+		}
+		rows = append(rows, renderMenuRow(st, i == b.cursor && !b.choosingVault, label))
+		if i == b.cursor && b.choosingVault {
+			for j, name := range b.vaults {
+				rows = append(rows, renderMenuRow(st, j == b.vaultCursor, "  "+name))
+			}
+		}
+	}
+	if b.err != nil {
+		rows = append(rows, "", st.Error.UnsetPadding().Render(ansi.Truncate("✗ "+b.err.Error(), width-2, "…")))
+	}
+	rows = append(rows, "", st.Muted.Render("enter select • esc close"))
+	return lipgloss.NewStyle().Width(width).Height(height).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func renderMenuRow(st *styles.Styles, selected bool, label string) string {
+	if selected {
+		return st.SelectedItem.Render("> " + label)
+	}
+	return st.Text.Render("  " + label)
+}
+
+// Bulk Progress ---
+
+// bulkRun is the progress of a confirmed operation, then its summary
+type bulkRun struct {
+	op       bulkOperation
+	done     int
+	failures []bulkFailure
+	notices  []bulkNotice
+	finished bool
+	bar      progress.Model
+}
+
+type bulkFailure struct {
+	title string
+	err   error
+}
+
+// bulkNotice is a note the operation handled differently, e.g. copied under another title
+type bulkNotice struct {
+	title  string
+	notice string
+}
+
+// bulkStepMsg is the result of the operation on one note
+type bulkStepMsg struct {
+	index  int
+	title  string
+	notice string
+	err    error
+}
+
+func newBulkRun(op bulkOperation, tm *styles.ThemeManager) *bulkRun {
+	theme := tm.Current()
+	bar := progress.New(progress.WithSolidFill(string(theme.Primary)), progress.WithoutPercentage())
+	bar.EmptyColor = string(theme.MutedColor)
+	return &bulkRun{op: op, bar: bar}
+}
+
+// stepCmd applies the operation on the note at the index, the notes are handled one at a time
+func (r *bulkRun) stepCmd(manager *vault.Manager, index int) tea.Cmd {
+	op := r.op
+	return func() tea.Msg {
+		id := op.noteIDs[index]
+		title := id
+		if note := manager.SearchByID(id); note != nil {
+			title = note.Title
+		}
+		notice, err := op.apply(manager, id)
+		return bulkStepMsg{index: index, title: title, notice: notice, err: err}
+	}
+}
+
+// record the result of a step, returns true when all the notes are done
+func (r *bulkRun) record(msg bulkStepMsg) bool {
+	r.done++
+	if msg.err != nil {
+		r.failures = append(r.failures, bulkFailure{title: msg.title, err: msg.err})
+	} else if msg.notice != "" {
+		r.notices = append(r.notices, bulkNotice{title: msg.title, notice: msg.notice})
+	}
+	r.finished = r.done == len(r.op.noteIDs)
+	return r.finished
+}
+
+func (r bulkRun) View(st *styles.Styles, width int, height int) string {
+	total := len(r.op.noteIDs)
+	rows := []string{}
+	if !r.finished {
+		r.bar.Width = max(width-2, 10)
+		rows = append(rows,
+			st.Title.Render(fmt.Sprintf("%s %d/%d", r.op.action.progressLabel(), r.done, total)),
+			"",
+			r.bar.ViewAs(float64(r.done)/float64(max(total, 1))),
+		)
+		if len(r.failures) > 0 {
+			rows = append(rows, "", st.Error.UnsetPadding().Render(fmt.Sprintf("%d failed", len(r.failures))))
+		}
+		return lipgloss.NewStyle().Width(width).Height(height).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+	}
+
+	succeeded := total - len(r.failures)
+	summary := fmt.Sprintf("%d of %d notes %s", succeeded, total, r.op.action.doneLabel())
+	rows = append(rows, st.Title.Render(ansi.Truncate(summary, width-4, "…")), "")
+	// Keep the room of the title, of the headers and of the help
+	visible := max(height-6, 1)
+	if len(r.notices) > 0 && len(r.failures) > 0 {
+		visible = max((height-8)/2, 1)
+	}
+	if len(r.failures) == 0 {
+		rows = append(rows, st.Success.UnsetPadding().Render("✓ No error"))
+	} else {
+		rows = append(rows, st.Error.UnsetPadding().Render(fmt.Sprintf("✗ %d failed, they are still selected:", len(r.failures))))
+		lines := make([]string, len(r.failures))
+		for i, failure := range r.failures {
+			lines[i] = fmt.Sprintf("%s: %v", failure.title, failure.err)
+		}
+		rows = append(rows, summaryLines(st, lines, visible, width)...)
+	}
+	if len(r.notices) > 0 {
+		rows = append(rows, "", st.Muted.Render(fmt.Sprintf("%d with a notice:", len(r.notices))))
+		lines := make([]string, len(r.notices))
+		for i, notice := range r.notices {
+			lines[i] = fmt.Sprintf("%s: %s", notice.title, notice.notice)
+		}
+		rows = append(rows, summaryLines(st, lines, visible, width)...)
+	}
+	rows = append(rows, "", st.Muted.Render("enter/esc close"))
+	return lipgloss.NewStyle().Width(width).Height(height).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+// summaryLines renders at most visible lines of the summary, then the number of the hidden ones
+func summaryLines(st *styles.Styles, lines []string, visible int, width int) []string {
+	rows := []string{}
+	for i, line := range lines {
+		if i == visible && len(lines) > visible+1 {
+			rows = append(rows, st.Muted.Render(fmt.Sprintf("  and %d more", len(lines)-i)))
+			break
+		}
+		rows = append(rows, st.Text.Render(ansi.Truncate("  "+line, width-2, "…")))
+	}
+	return rows
+}
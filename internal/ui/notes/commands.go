@@ -1,10 +1,17 @@
 package Notes
 
 import (
+	"errors"
 	"fmt"
+	"merlion/internal/config"
+	"merlion/internal/edit"
 	"merlion/internal/model"
+	"merlion/internal/ui/navigation"
 	"merlion/internal/vault"
+	"merlion/internal/worklog"
 	"os"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
@@ -15,89 +22,192 @@ import (
 
 type editorFinishedMsg struct {
 	err error
+	// Set when the edit conflicts with the changes made meanwhile
+	conflict *navigation.OpenConflictMsg
+	// Set when the header is malformed, to fix it in $EDITOR
+	retry *editorRetry
+	// Set when the title changed in the header
+	renamed string
+}
+
+type editorRetry struct {
+	session *edit.Session
+	text    string
 }
 
 func (m *Model) openEditor() tea.Cmd {
 	note := m.getCurrentNote(true)
-	if note == nil {
-		log.Fatalf("Trying to edit a nil note")
-	}
-	if note.Content == nil {
-		log.Fatalf("Trying to open a note with content == nil")
+	if note == nil || note.Content == nil {
+		return func() tea.Msg {
+			return editorFinishedMsg{err: errors.New("no note to edit, or its content is not loaded")}
+		}
 	}
 	log.Info("Editing:", note.NoteID)
+	if m.themeManager.Config.InternalEditor() {
+		return navigation.OpenEditorCmd(note.NoteID)
+	}
 
-	// Create a temporary file for editing
-	tmpfile, err := os.CreateTemp("", "note-*.md")
+	// The version before the edit, to merge the changes made meanwhile
+	session, err := edit.Begin(m.storeManager, note.NoteID)
 	if err != nil {
 		return func() tea.Msg {
-			return editorFinishedMsg{fmt.Errorf("could not create temp file: %w", err)}
+			return editorFinishedMsg{err: fmt.Errorf("could not load the note: %w", err)}
 		}
 	}
+	return m.openExternalEditor(session, m.editorText(session, session.BaseContent()))
+}
 
-	if _, err := tmpfile.WriteString(*note.Content); err != nil {
-		os.Remove(tmpfile.Name())
+// editorText is the content edited in $EDITOR, below the header of the metadata when enabled
+func (m *Model) editorText(session *edit.Session, content string) string {
+	if !m.themeManager.Config.EditorFrontMatter {
+		return content
+	}
+	return edit.WithHeader(session.Header(), content)
+}
+
+// openExternalEditor edits the text in $EDITOR, then saves it through the session.
+// The text is the draft of the session journal, kept until the edit is saved or discarded
+func (m *Model) openExternalEditor(session *edit.Session, content string) tea.Cmd {
+	var err error
+	if session.Recorded() {
+		err = session.WriteDraft(content)
+	} else {
+		err = session.Record(m.storeManager.Name, m.themeManager.Config.EditorFrontMatter, content)
+	}
+	if err != nil {
 		return func() tea.Msg {
-			return editorFinishedMsg{fmt.Errorf("could not write to temp file: %w", err)}
+			return editorFinishedMsg{err: fmt.Errorf("could not record the edit: %w", err)}
 		}
 	}
-	tmpfile.Close()
+	draft := session.DraftPath()
 
 	// Create editor command
-	cmd, err := editor.Cmd("Note", tmpfile.Name())
+	cmd, err := editor.Cmd("Note", draft)
 	if err != nil {
-		os.Remove(tmpfile.Name())
+		closeSession(session)
 		return func() tea.Msg {
-			return editorFinishedMsg{fmt.Errorf("failed to create editor command: %w", err)}
+			return editorFinishedMsg{err: fmt.Errorf("failed to create editor command: %w", err)}
 		}
 	}
 
 	// Return command that will execute editor
 	return tea.ExecProcess(cmd, func(err error) tea.Msg {
 		if err != nil {
-			// Don't delete the file, instead show where it is
-			return editorFinishedMsg{fmt.Errorf("editor failed: %w\nRecovery file location: %s", err, tmpfile.Name())}
+			// The draft is kept in the journal, unless there is nothing to recover in it
+			if text, readErr := os.ReadFile(draft); readErr == nil && string(text) == m.editorText(session, session.BaseContent()) {
+				closeSession(session)
+				return editorFinishedMsg{err: fmt.Errorf("editor failed: %w", err)}
+			}
+			return editorFinishedMsg{err: fmt.Errorf("editor failed: %w\n%s", err, recoverHint)}
 		}
 
 		// Read the edited content
-		newContent, err := os.ReadFile(tmpfile.Name())
+		newContent, err := os.ReadFile(draft)
 		if err != nil {
-			return editorFinishedMsg{fmt.Errorf("failed to read edited content: %w\nRecovery file location: %s", err, tmpfile.Name())}
+			return editorFinishedMsg{err: fmt.Errorf("failed to read edited content: %w\n%s", err, recoverHint)}
 		}
 
-		// Update note content through your API
-		content := string(newContent)
-		note.Content = &content
-
-		// Update the main note list
-		updated := false
-		for _, groundTruthNote := range m.storeManager.Notes {
-			if groundTruthNote.NoteID == note.NoteID {
-				*groundTruthNote.Content = content
-				updated = true
-				break
+		// Save, merged with the changes made meanwhile
+		mine := string(newContent)
+		noteID := session.NoteID
+		result, err := session.SaveDraft(m.storeManager, mine)
+		if errors.Is(err, edit.ErrHeader) {
+			if mine == content {
+				// Quit without saving the fix
+				closeSession(session)
+				return editorFinishedMsg{err: fmt.Errorf("%w, the edit was discarded", err)}
 			}
+			return editorFinishedMsg{err: err, retry: &editorRetry{session: session, text: edit.AnnotateHeader(mine, err)}}
 		}
-		if !updated {
-			log.Fatalf("Master list didn't get updated after Editor Finish")
-		}
-
-		// Update the note to backend
-		req := note.ToCreateRequest()
-		_, err = m.storeManager.UpdateNote(note.NoteID, req)
 		if err != nil {
-			log.Errorf("Not able to save the note %s", note.NoteID)
-			return editorFinishedMsg{fmt.Errorf("failed to save the edited content: %w\nRecovery file location: %s", err, tmpfile.Name())}
+			log.Errorf("Not able to save the note %s", session.NoteID)
+			return editorFinishedMsg{err: fmt.Errorf("failed to save the edited content: %w\n%s", err, recoverHint)}
 		}
 
-		// Only remove the file on successful completion
-		if err := os.Remove(tmpfile.Name()); err != nil {
-			log.Errorf("Failed to remove temporary file %s: %v", tmpfile.Name(), err)
+		if result.Outcome == edit.Conflict || result.Outcome == edit.Deleted {
+			// The journal is closed once the conflict is resolved
+			return editorFinishedMsg{conflict: &navigation.OpenConflictMsg{Session: session, Mine: result.Mine, Result: result, ReturnUI: navigation.NoteUI}}
 		}
-		return editorFinishedMsg{nil}
+		closeSession(session)
+		if session.NoteID != noteID {
+			return editorFinishedMsg{renamed: session.NoteID}
+		}
+		return editorFinishedMsg{}
 	})
 }
 
+// Shown when an edit could not be saved, its draft is kept in the journal
+const recoverHint = "The edit is kept, run `merlion recover` to apply it later"
+
+// closeSession ends the journal of the session, a failure only leaves a session to recover
+func closeSession(session *edit.Session) {
+	if err := session.Close(); err != nil {
+		log.Error("Failed to close the edit session", "error", err)
+	}
+}
+
+// Recovery ---
+
+// recoverConfirmedMsg is sent back by the dialog offering to re-apply the interrupted edits
+type recoverConfirmedMsg struct {
+	entries []edit.Entry
+}
+
+// recoveredMsg is the result of the re-applied edits, the first conflict is resolved in the conflict view
+type recoveredMsg struct {
+	noteID   string
+	conflict *navigation.OpenConflictMsg
+	errs     []string
+}
+
+// askRecoverCmd offers to re-apply the edits of the vault interrupted by a crash
+func (m Model) askRecoverCmd() tea.Cmd {
+	entries, err := edit.Interrupted(m.storeManager.Name)
+	if err != nil {
+		log.Error("Failed to read the edit sessions", "error", err)
+		return nil
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	title := "1 edit was interrupted"
+	if len(entries) > 1 {
+		title = fmt.Sprintf("%d edits were interrupted", len(entries))
+	}
+	titles := []string{}
+	for _, entry := range entries {
+		titles = append(titles, entry.Base.Title)
+	}
+	subtitle := fmt.Sprintf("Re-apply the changes to %s? Otherwise they are kept for `merlion recover`", strings.Join(titles, ", "))
+	return navigation.AskConfirmationMsgCmd(title, subtitle, navigation.InfoLvl, recoverConfirmedMsg{entries: entries}, navigation.NoteUI)
+}
+
+// recoverCmd re-applies the interrupted edits, merged with the changes made since
+func recoverCmd(storeManager *vault.Manager, entries []edit.Entry) tea.Cmd {
+	return func() tea.Msg {
+		msg := recoveredMsg{}
+		for _, entry := range entries {
+			session, result, err := edit.Apply(storeManager, entry)
+			if err != nil {
+				msg.errs = append(msg.errs, fmt.Sprintf("%s: %v", entry.Base.Title, err))
+				continue
+			}
+			switch {
+			case result.Outcome != edit.Conflict && result.Outcome != edit.Deleted:
+				if msg.noteID == "" {
+					msg.noteID = session.NoteID
+				}
+			case msg.conflict == nil:
+				msg.conflict = &navigation.OpenConflictMsg{Session: session, Mine: result.Mine, Result: result, ReturnUI: navigation.NoteUI}
+			default:
+				// Offered again on the next launch
+				msg.errs = append(msg.errs, fmt.Sprintf("%s: conflicts with the changes made since", entry.Base.Title))
+			}
+		}
+		return msg
+	}
+}
+
 // Fetch Notes ---
 
 type noteContentMsg struct {
@@ -115,6 +225,31 @@ func fetchNoteContent(storeManager *vault.Manager, noteId string) tea.Cmd {
 	}
 }
 
+// Open Note ---
+
+type openNoteMsg struct {
+	note *model.Note
+	err  error
+}
+
+// openNoteCmd loads the content of a note to display it, e.g. when selected in the command palette
+func openNoteCmd(storeManager *vault.Manager, noteId string) tea.Cmd {
+	return func() tea.Msg {
+		note, err := storeManager.GetFullNote(noteId)
+		return openNoteMsg{note: note, err: err}
+	}
+}
+
+// Work Log ---
+
+// openWorkLogCmd opens the journal note of the day, creating it if needed
+func openWorkLogCmd(storeManager *vault.Manager, cfg *config.UserConfig, day time.Time) tea.Cmd {
+	return func() tea.Msg {
+		note, err := worklog.Open(storeManager, cfg, day)
+		return openNoteMsg{note: note, err: err}
+	}
+}
+
 type NotesLoadedMsg struct {
 	Err error
 }
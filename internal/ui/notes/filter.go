@@ -0,0 +1,68 @@
+package Notes
+
+import (
+	"time"
+
+	"merlion/internal/query"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// List Filter ---
+
+// queryFilter filters the items with the query language, plain words keep the fuzzy matching of the titles
+func queryFilter(items []list.Item) list.FilterFunc {
+	return func(term string, targets []string) []list.Rank {
+		q, err := query.Parse(term, time.Now())
+		if err != nil {
+			return []list.Rank{}
+		}
+		if q.IsPlainText() {
+			return list.DefaultFilter(term, targets)
+		}
+		ranks := []list.Rank{}
+		for i, listItem := range items {
+			if i >= len(targets) {
+				break
+			}
+			if note, ok := listItem.(item); ok && q.Match(note.note) {
+				ranks = append(ranks, list.Rank{Index: i})
+			}
+		}
+		return ranks
+	}
+}
+
+// checkQuery returns the error of the query typed in the filter
+func checkQuery(term string) error {
+	_, err := query.Parse(term, time.Now())
+	return err
+}
+
+// noteListView displays the error of the filter query, of the query of the tab or of the theme files,
+// and the count of selected notes, above the list
+func (m Model) noteListView() string {
+	err := m.tabErr
+	if err == nil && len(m.themeManager.ThemeErrors) > 0 {
+		err = m.themeManager.ThemeErrors[0]
+	}
+	if m.queryErr != nil && m.noteList.FilterState() != list.Unfiltered {
+		err = m.queryErr
+	}
+	lines := []string{}
+	if err != nil {
+		message := ansi.Truncate("✗ "+err.Error(), m.noteList.Width()-2, "…")
+		lines = append(lines, m.styles.Error.UnsetPadding().Render(message))
+	}
+	if line := m.selectionLine(m.noteList.Width()); line != "" {
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return m.noteList.View()
+	}
+	noteList := m.noteList
+	noteList.SetHeight(noteList.Height() - len(lines))
+	return lipgloss.JoinVertical(lipgloss.Left, append(lines, noteList.View())...)
+}
@@ -2,17 +2,20 @@ package Notes
 
 import (
 	"fmt"
-	"sort"
 	"strings"
+	"time"
 
 	"merlion/internal/controls"
 	"merlion/internal/model"
+	"merlion/internal/query"
+	"merlion/internal/sorting"
 	"merlion/internal/vault"
 	"merlion/internal/vault/cloud"
 	"merlion/internal/styles"
 	styledDelegate "merlion/internal/styles/components/delegate"
 	grouplist "merlion/internal/styles/components/groupList"
 	tabs "merlion/internal/styles/components/tabs"
+	"merlion/internal/ui/actions"
 	"merlion/internal/ui/navigation"
 	"merlion/internal/ui/notes/renderer"
 
@@ -42,7 +45,8 @@ const ViewRatio = 4
 const LargeScreenBreakpoint = 140
 
 type item struct {
-	note model.Note
+	note      model.Note
+	selection selection
 }
 
 type ViewState string
@@ -59,6 +63,7 @@ const (
 	Favorites
 	WorkLogs
 	Tags
+	Custom // Defined in the user config
 )
 
 // String implements the Displayable interface
@@ -79,7 +84,7 @@ func (t TabKind) String() string {
 
 type Model struct {
 	noteList     list.Model
-	fileterTabs  tabs.Tabs[TabKind]
+	fileterTabs  tabs.Tabs[Tab]
 	noteRenderer renderer.Model
 	spinner      spinner.Model
 	keys         controls.KeyMap
@@ -95,10 +100,22 @@ type Model struct {
 	viewType     ViewType
 	compactView  bool
 	tagsList     grouplist.Model
+	sortMenu     sortMenu
+	queryErr     error
+	tabErr       error
+	tabEditor    tabEditor
+	selection    selection
+	// Last note toggled, the start of the range selection
+	selectionAnchor string
+	bulkMenu        bulkMenu
+	bulk         *bulkRun
+	// The interrupted edits are offered once, when the notes are first loaded
+	recoveryChecked bool
 }
 
 func NewModel(vaultManager *vault.Manager, themeManager *styles.ThemeManager, firstTab string) Model {
 	s := themeManager.Styles()
+	registerActions()
 
 	// Initialize spinner with themed color
 	sp := spinner.New()
@@ -116,8 +133,14 @@ func NewModel(vaultManager *vault.Manager, themeManager *styles.ThemeManager, fi
 	l.SetFilteringEnabled(true)
 	l.SetShowHelp(false)
 	l.Styles.Title = s.Title
+	// Follow the key bindings of the config, the help is shown by the help view
+	l.KeyMap.CursorUp = controls.Keys.Up
+	l.KeyMap.CursorDown = controls.Keys.Down
+	l.KeyMap.Quit = controls.Keys.Quit
+	l.KeyMap.ShowFullHelp.SetEnabled(false)
+	l.KeyMap.CloseFullHelp.SetEnabled(false)
 
-	filterTabs := []TabKind{AllNotes, Favorites, WorkLogs, Tags}
+	filterTabs := allTabs(themeManager.Config)
 
 	tabs := tabs.New(filterTabs, themeManager, firstTab)
 
@@ -141,10 +164,31 @@ func NewModel(vaultManager *vault.Manager, themeManager *styles.ThemeManager, fi
 		viewType:     large,
 		compactView:  themeManager.Config.CompactView,
 		tagsList:     gl,
+		sortMenu:     newSortMenu(),
+		tabEditor:    newTabEditor(),
+		selection:    newSelection(),
+		bulkMenu:     newBulkMenu(),
 	}
 }
 
 func (m Model) Init(args ...any) tea.Cmd {
+	// Back from the command palette, the notes are already loaded
+	if len(args) > 0 {
+		if msg, ok := args[0].(actions.Msg); ok {
+			return func() tea.Msg { return msg }
+		}
+		// Confirmed bulk action, run by this view
+		if msg, ok := args[0].(bulkConfirmedMsg); ok {
+			return func() tea.Msg { return msg }
+		}
+		if msg, ok := args[0].(recoverConfirmedMsg); ok {
+			return recoverCmd(m.storeManager, msg.entries)
+		}
+		// Merge to resolve in $EDITOR
+		if msg, ok := args[0].(navigation.OpenEditorMsg); ok && msg.Session != nil && msg.Content != nil {
+			return m.openExternalEditor(msg.Session, m.editorText(msg.Session, *msg.Content))
+		}
+	}
 	if m.storeManager != nil {
 		return tea.Batch(
 			m.spinner.Tick,
@@ -159,38 +203,50 @@ func (m Model) SetCloudClient(client *cloud.Client) navigation.View {
 	return m
 }
 
-func createNoteItems(notes []model.Note, filter TabKind) []list.Item {
+func createNoteItems(notes []model.Note, tab Tab, order sorting.Order, sel selection) ([]list.Item, error) {
 	filteredNotes := make([]model.Note, 0)
-	if filter == Favorites {
+	var err error
+	if tab.Kind == Favorites {
 		for _, note := range notes {
 			if note.IsFavorite {
 				filteredNotes = append(filteredNotes, note)
 			}
 		}
-	} else if filter == WorkLogs {
+	} else if tab.Kind == WorkLogs {
 		for _, note := range notes {
 			if note.IsWorkLog {
 				filteredNotes = append(filteredNotes, note)
 			}
 		}
-		sort.Slice(filteredNotes, func(i, j int) bool {
-			return filteredNotes[j].CreatedAt.Before(filteredNotes[i].CreatedAt)
-		})
+	} else if tab.Kind == Custom {
+		q, parseErr := query.Parse(tab.Query, time.Now())
+		if parseErr != nil {
+			err = fmt.Errorf("invalid query of the tab: %w", parseErr)
+		} else {
+			filteredNotes = q.Filter(notes)
+		}
 	} else {
-		filteredNotes = notes
+		filteredNotes = append(filteredNotes, notes...)
 	}
+	sorting.Sort(filteredNotes, order)
 	items := make([]list.Item, len(filteredNotes))
 	for i, note := range filteredNotes {
-		items[i] = item{note: note}
+		items[i] = item{note: note, selection: sel}
 	}
-	return items
+	return items, err
 }
 
 func (m *Model) refreshNotesView() {
+	m.fileterTabs.SetTabs(allTabs(m.themeManager.Config))
+	registerTabActions(m.themeManager.Config)
 	currTab := m.fileterTabs.CurrentTab()
-	items := createNoteItems(m.storeManager.Notes, currTab)
+	order := m.themeManager.Config.NotesSortFor(currTab.String())
+	items, err := createNoteItems(m.storeManager.Notes, currTab, order, m.selection)
+	m.tabErr = err
+	m.pruneSelection()
+	m.noteList.Filter = queryFilter(items)
 	m.noteList.SetItems(items)
-	groups := createTagGroups(m.storeManager.Notes)
+	groups := createTagGroups(m.storeManager.Notes, m.selection)
 	m.tagsList.SetGroups(groups)
 }
 
@@ -202,7 +258,7 @@ func (m Model) getCurrentNote(considerRenderer bool) *model.Note {
 			currentNote = m.noteRenderer.Note
 		}
 	} else {
-		if m.fileterTabs.CurrentTab() == Tags {
+		if m.fileterTabs.CurrentTab().Kind == Tags {
 			selectedItem := m.tagsList.SelectedItem()
 			if selectedItem != nil {
 				if noteItem, ok := selectedItem.(item); ok {
@@ -224,31 +280,65 @@ func (m Model) getCurrentNote(considerRenderer bool) *model.Note {
 	return currentNote
 }
 
-func createTagGroups(notes []model.Note) []grouplist.Group {
-	groups := make(map[string][]list.Item)
+// tagNode is a level of the tag tree, `work/clients/acme` gives three nested nodes
+type tagNode struct {
+	children map[string]*tagNode
+	items    []list.Item
+	seen     map[string]bool
+}
 
-	for _, note := range notes {
-		for _, tag := range note.Tags {
-			tag = strings.ToLower(tag)
-			newItem := item{note: note}
+func newTagNode() *tagNode {
+	return &tagNode{
+		children: make(map[string]*tagNode),
+		seen:     make(map[string]bool),
+	}
+}
 
-			if items, exists := groups[tag]; exists {
-				groups[tag] = append(items, newItem)
-			} else {
-				groups[tag] = []list.Item{newItem}
-			}
-		}
+// add the note once, even if multiple tags of the note are under this node
+func (n *tagNode) add(note model.Note, sel selection) {
+	if n.seen[note.NoteID] {
+		return
 	}
+	n.seen[note.NoteID] = true
+	n.items = append(n.items, item{note: note, selection: sel})
+}
 
-	result := make([]grouplist.Group, 0, len(groups))
-	for name, items := range groups {
-		result = append(result, grouplist.Group{
-			Name:  name,
-			Items: items,
+func (n *tagNode) toGroups() []grouplist.Group {
+	groups := make([]grouplist.Group, 0, len(n.children))
+	for name, child := range n.children {
+		groups = append(groups, grouplist.Group{
+			Name:     name,
+			Items:    child.items,
+			Children: child.toGroups(),
 		})
 	}
+	return groups
+}
+
+// createTagGroups builds the tag tree, a parent tag contains the notes of all its children
+func createTagGroups(notes []model.Note, sel selection) []grouplist.Group {
+	root := newTagNode()
+
+	for _, note := range notes {
+		for _, tag := range note.Tags {
+			node := root
+			for _, part := range strings.Split(strings.ToLower(tag), "/") {
+				part = strings.TrimSpace(part)
+				if part == "" {
+					continue
+				}
+				child, exists := node.children[part]
+				if !exists {
+					child = newTagNode()
+					node.children[part] = child
+				}
+				child.add(note, sel)
+				node = child
+			}
+		}
+	}
 
-	return result
+	return root.toGroups()
 }
 
 func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
@@ -266,17 +356,100 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 	case notesLoadedMsg:
 		m.refreshNotesView()
 		m.loading = false
+		if !m.recoveryChecked && msg.Err == nil {
+			m.recoveryChecked = true
+			return m, m.askRecoverCmd()
+		}
+		return m, nil
+
+	case recoveredMsg:
+		m.refreshNotesView()
+		if len(msg.errs) > 0 {
+			m.noteRenderer.SetErrorMessage("Error re-applying the interrupted edits:\n" + strings.Join(msg.errs, "\n"))
+		}
+		if msg.conflict != nil {
+			conflict := *msg.conflict
+			return m, func() tea.Msg { return conflict }
+		}
+		if msg.noteID != "" && len(msg.errs) == 0 {
+			opened := actions.OpenNoteMsg{NoteID: msg.noteID}
+			return m, func() tea.Msg { return opened }
+		}
 		return m, nil
 
 	case list.FilterMatchesMsg:
 		m.noteList, cmd = m.noteList.Update(msg)
 		return m, cmd
 
+	case openNoteMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.noteRenderer.SetErrorMessage(fmt.Sprintf("Error opening note: %v", msg.err))
+			return m, nil
+		}
+		m.refreshNotesView()
+		m.noteRenderer.SetNote(msg.note)
+		m.noteRenderer.Render()
+		m.focusedPane = markdown
+		return m, nil
+
+	case actions.RunMsg:
+		return m, m.runAction(msg.ID)
+
+	case actions.OpenNoteMsg:
+		m.loading = true
+		return m, openNoteCmd(m.storeManager, msg.NoteID)
+
+	case actions.CancelMsg:
+		return m, nil
+
+	case removeTabMsg:
+		if err := m.deleteTab(msg.name); err != nil {
+			m.tabErr = fmt.Errorf("failed to remove the tab: %w", err)
+		}
+		return m, nil
+
+	case bulkConfirmedMsg:
+		m.bulk = newBulkRun(msg.op, m.themeManager)
+		m.focusedPane = noteList
+		return m, m.bulk.stepCmd(m.storeManager, 0)
+
+	case bulkStepMsg:
+		if m.bulk == nil {
+			return m, nil
+		}
+		if msg.err == nil {
+			// The failed notes stay selected, to retry them
+			delete(m.selection, m.bulk.op.noteIDs[msg.index])
+		}
+		if !m.bulk.record(msg) {
+			return m, m.bulk.stepCmd(m.storeManager, msg.index+1)
+		}
+		m.refreshNotesView()
+		if m.noteRenderer.Note != nil && m.storeManager.SearchByID(m.noteRenderer.Note.NoteID) == nil {
+			m.noteRenderer.SetNote(nil)
+			m.noteRenderer.Render()
+		}
+		return m, nil
+
 	case editorFinishedMsg:
+		if msg.conflict != nil {
+			conflict := *msg.conflict
+			return m, func() tea.Msg { return conflict }
+		}
+		if msg.retry != nil {
+			log.Warn("Editing the note again", "error", msg.err)
+			return m, m.openExternalEditor(msg.retry.session, msg.retry.text)
+		}
 		if msg.err != nil {
 			m.noteRenderer.SetErrorMessage(fmt.Sprintf("Error editing note: %v", msg.err))
 			return m, nil
 		}
+		if msg.renamed != "" {
+			m.refreshNotesView()
+			renamed := actions.OpenNoteMsg{NoteID: msg.renamed}
+			return m, func() tea.Msg { return renamed }
+		}
 		m.refreshNotesView()
 		if note := m.getCurrentNote(true); note != nil {
 			m.noteRenderer.SetNote(note)
@@ -338,6 +511,46 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 		// If we're actively filtering, don't handle any other keypresses
 		if m.noteList.FilterState() == list.Filtering {
 			m.noteList, cmd = m.noteList.Update(msg)
+			m.queryErr = checkQuery(m.noteList.FilterValue())
+			return m, cmd
+		}
+		if m.bulk != nil {
+			// The keys are ignored until the summary is displayed
+			if m.bulk.finished && (key.Matches(msg, m.keys.Back) || key.Matches(msg, m.keys.Select)) {
+				m.bulk = nil
+			} else if key.Matches(msg, m.keys.Quit) {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if m.bulkMenu.open {
+			op, cmd := m.bulkMenu.Update(msg)
+			if op != nil {
+				return m, op.confirmCmd(m.storeManager)
+			}
+			return m, cmd
+		}
+		if m.tabEditor.open {
+			tab, cmd := m.tabEditor.Update(msg)
+			if tab != nil {
+				if err := m.saveTab(m.tabEditor.previousName, *tab); err != nil {
+					m.tabEditor.err = err
+				} else {
+					m.tabEditor.open = false
+				}
+			}
+			return m, cmd
+		}
+		if m.focusedPane == markdown && m.noteRenderer.CapturesKey(msg) {
+			m.noteRenderer, cmd = m.noteRenderer.Update(msg)
+			return m, cmd
+		}
+		if m.sortMenu.open {
+			changed, cmd := m.sortMenu.Update(msg)
+			if changed {
+				m.themeManager.SetNotesSort(m.sortMenu.tab, m.sortMenu.order)
+				m.refreshNotesView()
+			}
 			return m, cmd
 		}
 
@@ -365,6 +578,12 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 				m.refreshNotesView()
 			}
 
+		case key.Matches(msg, m.keys.CommandPalette):
+			return m, navigation.OpenPaletteCmd()
+
+		case key.Matches(msg, m.keys.Help):
+			return m, navigation.OpenHelpCmd()
+
 		case key.Matches(msg, m.keys.ToggleInfo):
 			m.noteRenderer.ToggleHideInfo()
 
@@ -375,29 +594,33 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 			m.ToggleFullscreen()
 			return m, tea.WindowSize()
 
+		case key.Matches(msg, m.keys.ClearFilter) && len(m.selectedIDs()) > 0 && m.focusedPane == noteList:
+			// Clear the selection before the filter
+			m.selection.clear()
+			return m, nil
+
 		case key.Matches(msg, m.keys.ClearFilter):
 			m.noteList.ResetFilter()
+			m.queryErr = nil
 			if m.focusedPane == markdown {
 				m.focusedPane = noteList
 			}
 			return m, nil
 
+		case key.Matches(msg, m.keys.OpenToday):
+			return m, openWorkLog(&m, time.Now())
+
+		case key.Matches(msg, m.keys.OpenYesterday):
+			return m, openWorkLog(&m, time.Now().AddDate(0, 0, -1))
+
 		case key.Matches(msg, m.keys.Create):
-			cmd = navigation.SwitchUICmd(navigation.CreateUI, []any{})
-			return m, cmd
+			return m, createNote(&m)
+
+		case key.Matches(msg, m.keys.Delete) && len(m.selectedIDs()) > 0:
+			return m, deleteSelection(&m)
 
 		case key.Matches(msg, m.keys.Delete):
-			noteToDelete := m.getCurrentNote(true)
-			if noteToDelete != nil && noteToDelete.NoteID != "" {
-				cmd = navigation.AskConfirmationCmd(
-					"Are you sure you want to delete this note ?",
-					noteToDelete.Title,
-					navigation.DangerLvl,
-					func() {
-						m.storeManager.DeleteNote(noteToDelete.NoteID)
-					},
-					navigation.NoteUI,
-				)
+			if cmd = deleteNote(&m); cmd != nil {
 				return m, cmd
 			}
 
@@ -405,22 +628,16 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 			m.focusedPane = noteList
 
 		case key.Matches(msg, m.keys.Edit):
-			noteToEdit := m.getCurrentNote(true)
-			if noteToEdit != nil {
-				if noteToEdit.Content != nil {
-					return m, m.openEditor()
-				} else {
-					// We don't have the content locally.. fetch
-					m.loading = true
-					return m, fetchNoteContent(m.storeManager, noteToEdit.NoteID)
-				}
+			if cmd = editNote(&m); cmd != nil {
+				return m, cmd
 			}
 
+		case key.Matches(msg, m.keys.Manage) && len(m.selectedIDs()) > 0:
+			return m, openBulkMenu(&m)
+
 		case key.Matches(msg, m.keys.Manage):
-			noteToManage := m.getCurrentNote(true)
-			if noteToManage != nil {
-				m.loading = true
-				return m, navigation.OpenManageViewCmd(noteToManage.NoteID)
+			if cmd = manageNote(&m); cmd != nil {
+				return m, cmd
 			}
 
 		case key.Matches(msg, m.keys.Select):
@@ -438,20 +655,44 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 				}
 			}
 
+		case key.Matches(msg, m.keys.SortGroups) && m.focusedPane == noteList && m.fileterTabs.CurrentTab().Kind != Tags:
+			return m, openSortMenu(&m)
+
+		case key.Matches(msg, m.keys.ToggleSelect) && m.focusedPane == noteList:
+			return m, toggleSelect(&m)
+
+		case key.Matches(msg, m.keys.SelectRange) && m.focusedPane == noteList:
+			return m, selectRange(&m)
+
+		case key.Matches(msg, m.keys.SelectAll) && m.focusedPane == noteList:
+			return m, selectAll(&m)
+
+		case key.Matches(msg, m.keys.NewTab) && m.focusedPane == noteList:
+			return m, openNewTab(&m)
+
+		case key.Matches(msg, m.keys.EditTab) && m.focusedPane == noteList:
+			return m, openEditTab(&m)
+
+		case key.Matches(msg, m.keys.RemoveTab) && m.focusedPane == noteList:
+			if cmd = removeTab(&m); cmd != nil {
+				return m, cmd
+			}
+
+		case key.Matches(msg, m.keys.MoveTabLeft) && m.focusedPane == noteList:
+			return m, moveTab(-1)(&m)
+
+		case key.Matches(msg, m.keys.MoveTabRight) && m.focusedPane == noteList:
+			return m, moveTab(1)(&m)
+
 		case key.Matches(msg, m.keys.ToggleTheme):
 			toggleTheme(&m)
 
 		case key.Matches(msg, m.keys.ToggleStore):
-			m.storeManager.NextStore()
-			m.loading = true
-			loadCmd := m.loadNotes()
-			cmds = append(cmds, loadCmd)
-			m.noteRenderer.SetNote(nil)
-			m.noteRenderer.Render()
+			cmds = append(cmds, nextStore(&m))
 		}
 
 		// Handle navigation based on focused pane
-		if m.focusedPane == noteList && m.fileterTabs.CurrentTab() == Tags {
+		if m.focusedPane == noteList && m.fileterTabs.CurrentTab().Kind == Tags {
 			m.tagsList, cmd = m.tagsList.Update(msg)
 			cmds = append(cmds, cmd)
 		} else if m.focusedPane == noteList {
@@ -475,7 +716,7 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 			log.Debug("Swaping view note for updated note")
 			currentIndex := m.noteList.Index()
 			items := m.noteList.Items()
-			items[currentIndex] = item{note: *updatedNote}
+			items[currentIndex] = item{note: *updatedNote, selection: m.selection}
 			m.noteList.SetItems(items)
 
 			// Display the content in the renderer
@@ -493,10 +734,15 @@ func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
 
 func (i item) Title() string { return i.note.Title }
 func (i item) Description() string {
+	// The marker is not in the title, the filter highlights the matched characters of the title
+	marker := ""
+	if i.selection[i.note.NoteID] {
+		marker = "✓ "
+	}
 	if i.note.IsFavorite {
-		return fmt.Sprintf("★ Created: %s", i.note.CreatedAt.Format("2006-01-02"))
+		return fmt.Sprintf("%s★ Created: %s", marker, i.note.CreatedAt.Format("2006-01-02"))
 	} else {
-		return fmt.Sprintf("Created: %s", i.note.CreatedAt.Format("2006-01-02"))
+		return fmt.Sprintf("%sCreated: %s", marker, i.note.CreatedAt.Format("2006-01-02"))
 	}
 }
 func (i item) FilterValue() string { return i.note.Title }
@@ -523,12 +769,7 @@ func (m Model) desktopView() string {
 		listStyle = m.styles.InactiveContent.Width(m.width / ViewRatio)
 	}
 
-	var listView string
-	if m.fileterTabs.CurrentTab() == Tags {
-		listView = m.tagsList.View()
-	} else {
-		listView = m.noteList.View()
-	}
+	listView := m.listPanelView()
 
 	combinedView := lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -552,6 +793,25 @@ func (m Model) desktopView() string {
 
 }
 
+// listPanelView returns the list of the current tab, or the menu replacing it
+func (m Model) listPanelView() string {
+	width, height := m.noteList.Width(), m.noteList.Height()
+	switch {
+	case m.bulk != nil:
+		return m.bulk.View(m.styles, width, height)
+	case m.bulkMenu.open:
+		return m.bulkMenu.View(m.styles, width, height)
+	case m.tabEditor.open:
+		return m.tabEditor.View(m.styles, width, height)
+	case m.sortMenu.open:
+		return m.sortMenu.View(m.styles, width, height)
+	case m.fileterTabs.CurrentTab().Kind == Tags:
+		return m.tagsListView()
+	default:
+		return m.noteListView()
+	}
+}
+
 func (m Model) mobileView() string {
 	var style lipgloss.Style
 	style = m.styles.MobileContent.
@@ -560,12 +820,7 @@ func (m Model) mobileView() string {
 	if m.focusedPane == markdown {
 		return style.Render(m.noteRenderer.View())
 	} else {
-		var listView string
-		if m.fileterTabs.CurrentTab() == Tags {
-			listView = m.tagsList.View()
-		} else {
-			listView = m.noteList.View()
-		}
+		listView := m.listPanelView()
 
 		combinedView := lipgloss.JoinVertical(
 			lipgloss.Left,
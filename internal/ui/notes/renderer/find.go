@@ -0,0 +1,351 @@
+package renderer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"merlion/internal/controls"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Find ---
+// `/` searches the rendered note: the matches are found in the text of each line, without its styles,
+// then highlighted in the ANSI output, the styles of the text being restored after each match
+
+const (
+	findKey  = "/"
+	sgrReset = "\x1b[0m"
+)
+
+// findMatch is a match in the text of a line, by byte offsets
+type findMatch struct {
+	line  int
+	start int
+	end   int
+}
+
+// find is the state of the find bar
+type find struct {
+	input         textinput.Model
+	typing        bool
+	caseSensitive bool
+	regex         bool
+	matches       []findMatch
+	current       int
+	from          int // Offset of the viewport when the find was opened, restored on cancel
+	err           error
+}
+
+func newFind() find {
+	input := textinput.New()
+	input.Prompt = findKey
+	input.Placeholder = "Find in the note"
+	input.CharLimit = 256
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return find{input: input}
+}
+
+// shown tells if the find bar is displayed, while typing or with a query
+func (f find) shown() bool {
+	return f.typing || f.input.Value() != ""
+}
+
+func (f find) pattern() (*regexp.Regexp, error) {
+	query := f.input.Value()
+	if !f.regex {
+		query = regexp.QuoteMeta(query)
+	}
+	if !f.caseSensitive {
+		query = "(?i)" + query
+	}
+	return regexp.Compile(query)
+}
+
+// findHeight returns the height of the find bar
+func (m Model) findHeight() int {
+	if m.find.shown() {
+		return 1
+	}
+	return 0
+}
+
+// CapturesKey tells if the key is for the outline or the find bar, and not for the notes view
+func (m Model) CapturesKey(msg tea.KeyMsg) bool {
+	return m.outline.open || m.find.typing || (m.find.shown() && key.Matches(msg, controls.Keys.Back))
+}
+
+func (m *Model) openFind() {
+	m.find.typing = true
+	m.find.from = m.viewport.YOffset
+	m.find.input.Focus()
+	m.SetSize(m.width, m.height)
+}
+
+func (m *Model) closeFind() {
+	m.find.typing = false
+	m.find.input.Blur()
+	m.find.input.SetValue("")
+	m.find.matches = nil
+	m.find.err = nil
+	m.SetSize(m.width, m.height)
+}
+
+func (m *Model) updateFind(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, controls.Keys.Back):
+		from := m.find.from
+		m.closeFind()
+		m.viewport.SetYOffset(from)
+		return nil
+	case key.Matches(msg, controls.Keys.Select):
+		m.find.typing = false
+		m.find.input.Blur()
+		if m.find.input.Value() == "" {
+			m.closeFind()
+		}
+		return nil
+	case key.Matches(msg, controls.Keys.ToggleFindCase):
+		m.find.caseSensitive = !m.find.caseSensitive
+	case key.Matches(msg, controls.Keys.ToggleFindRegex):
+		m.find.regex = !m.find.regex
+	default:
+		query := m.find.input.Value()
+		var cmd tea.Cmd
+		m.find.input, cmd = m.find.input.Update(msg)
+		if m.find.input.Value() == query {
+			return cmd
+		}
+	}
+	m.search(true)
+	return nil
+}
+
+// search finds the matches in the rendered note. When typing, the current match is the first one
+// below the position the find was opened from, else the closest to the previous current match
+func (m *Model) search(typing bool) {
+	previous := m.find.current
+	m.find.matches = nil
+	m.find.current = 0
+	m.find.err = nil
+	if m.find.input.Value() == "" {
+		m.viewport.SetContent(m.rendered)
+		return
+	}
+	pattern, err := m.find.pattern()
+	if err != nil {
+		m.find.err = err
+		m.viewport.SetContent(m.rendered)
+		return
+	}
+
+	for i, line := range strings.Split(m.rendered, "\n") {
+		for _, loc := range pattern.FindAllStringIndex(plainText(line), -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			m.find.matches = append(m.find.matches, findMatch{line: i, start: loc[0], end: loc[1]})
+		}
+	}
+	if typing {
+		for i, match := range m.find.matches {
+			if match.line >= m.find.from {
+				m.find.current = i
+				break
+			}
+		}
+	} else {
+		m.find.current = min(previous, max(len(m.find.matches)-1, 0))
+	}
+	m.showMatches(typing)
+}
+
+// stepMatch moves to the previous (step < 0) or next (step > 0) match, around the note
+func (m *Model) stepMatch(step int) {
+	if len(m.find.matches) == 0 {
+		return
+	}
+	m.find.current = (m.find.current + step + len(m.find.matches)) % len(m.find.matches)
+	m.showMatches(true)
+}
+
+// showMatches highlights the matches, and scrolls to the current one when asked
+func (m *Model) showMatches(reveal bool) {
+	theme := m.themeManager.Current()
+	other := sgrOf(lipgloss.NewStyle().Foreground(theme.Background).Background(theme.Tertiary))
+	current := sgrOf(lipgloss.NewStyle().Foreground(theme.Background).Background(theme.Primary).Bold(true))
+
+	lines := strings.Split(m.rendered, "\n")
+	for start := 0; start < len(m.find.matches); {
+		line := m.find.matches[start].line
+		end := start
+		for end < len(m.find.matches) && m.find.matches[end].line == line {
+			end++
+		}
+		styles := make([]string, end-start)
+		for i := range styles {
+			styles[i] = other
+			if start+i == m.find.current {
+				styles[i] = current
+			}
+		}
+		lines[line] = highlightLine(lines[line], m.find.matches[start:end], styles)
+		start = end
+	}
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+
+	if !reveal || len(m.find.matches) == 0 {
+		return
+	}
+	line := m.find.matches[m.find.current].line
+	if line < m.viewport.YOffset || line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(max(line-m.viewport.Height/3, 0))
+	}
+}
+
+func (m Model) findView() string {
+	styles := m.themeManager.Styles()
+	toggle := func(label string, on bool) string {
+		if on {
+			return styles.SelectedItem.Render(label)
+		}
+		return styles.Muted.Render(label)
+	}
+
+	var status string
+	switch {
+	case m.find.err != nil:
+		status = styles.Error.UnsetPadding().Render("Invalid expression")
+	case m.find.input.Value() == "":
+		status = ""
+	case len(m.find.matches) == 0:
+		status = styles.Muted.Render("No match")
+	default:
+		status = styles.Text.Render(fmt.Sprintf("%d/%d", m.find.current+1, len(m.find.matches)))
+	}
+
+	var bar string
+	if m.find.typing {
+		m.find.input.Width = max(m.width/2, 10)
+		bar = lipgloss.JoinHorizontal(lipgloss.Left,
+			m.find.input.View(), " ",
+			toggle("Aa", m.find.caseSensitive), " ",
+			toggle(".*", m.find.regex), "  ",
+			status,
+		)
+	} else {
+		help := fmt.Sprintf("  %s/%s next/previous • %s clear",
+			controls.Keys.FindNext.Help().Key, controls.Keys.FindPrev.Help().Key, controls.Keys.Back.Help().Key)
+		bar = styles.Muted.Render(findKey+m.find.input.Value()+"  ") + status + styles.Muted.Render(help)
+	}
+	return ansi.Truncate(bar, m.width, "…")
+}
+
+// sgrOf returns the escape sequence starting the style, reverse video without colors
+func sgrOf(style lipgloss.Style) string {
+	prefix, _, _ := strings.Cut(style.Render("·"), "·")
+	if prefix == "" {
+		return "\x1b[7m"
+	}
+	return prefix
+}
+
+// sequenceLen returns the length of the escape sequence at the start of s
+func sequenceLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		// CSI, up to its final byte
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		// OSC, up to BEL or ST
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s)
+}
+
+// plainText returns the line without its escape sequences
+func plainText(line string) string {
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			i += sequenceLen(line[i:])
+			continue
+		}
+		b.WriteByte(line[i])
+		i++
+	}
+	return b.String()
+}
+
+// highlightLine wraps the matches of the line in their style. The styles of the rendered text are
+// tracked since the last reset: the highlight is applied again after them, and they are restored after the match
+func highlightLine(line string, matches []findMatch, styles []string) string {
+	var b strings.Builder
+	active := []string{}
+	plain := 0
+	next := 0
+	inMatch := false
+	for i := 0; ; {
+		if inMatch && plain == matches[next].end {
+			b.WriteString(sgrReset + strings.Join(active, ""))
+			inMatch = false
+			next++
+		}
+		if !inMatch && next < len(matches) && plain == matches[next].start {
+			b.WriteString(styles[next])
+			inMatch = true
+		}
+		if i >= len(line) {
+			break
+		}
+
+		if line[i] == '\x1b' {
+			n := sequenceLen(line[i:])
+			seq := line[i : i+n]
+			b.WriteString(seq)
+			if strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+				if seq == sgrReset || seq == "\x1b[m" {
+					active = active[:0]
+				} else {
+					active = append(active, seq)
+				}
+				if inMatch {
+					b.WriteString(styles[next])
+				}
+			}
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(line[i:])
+		b.WriteString(line[i : i+size])
+		i += size
+		plain += size
+	}
+	if inMatch {
+		b.WriteString(sgrReset + strings.Join(active, ""))
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package renderer
+
+import (
+	"github.com/latentdream/merlion/lib/glamour/ansi"
+)
+
+// Footnotes ---
+// enter on a reference to a footnote scrolls the note to the footnote, enter again scrolls
+// back to the reference. The backlinks of the footnote scroll to their reference
+
+// footnoteJump is the last scroll from a reference to its footnote
+type footnoteJump struct {
+	footnote int // Index of the footnote, from 1, 0 when there is no jump to go back from
+	from     int // Offset of the viewport on the reference
+	to       int // Offset of the viewport on the footnote
+}
+
+// followFootnote scrolls the note from the reference of the selector to its footnote and back,
+// or from the backlink of the selector to its reference
+func (m *Model) followFootnote(selector *ansi.Selector) {
+	if selector.Backlink {
+		m.footnote = footnoteJump{}
+		if line := m.renderer.FootnoteReference(selector.Footnote, selector.RefIndex); line >= 0 {
+			m.viewport.SetYOffset(line)
+		}
+		return
+	}
+
+	// Back to the reference, while the note still shows the footnote
+	if m.footnote.footnote == selector.Footnote && m.viewport.YOffset == m.footnote.to {
+		m.viewport.SetYOffset(m.footnote.from)
+		m.footnote = footnoteJump{}
+		return
+	}
+	line := m.renderer.FootnoteDefinition(selector.Footnote)
+	if line < 0 {
+		return
+	}
+	from := m.viewport.YOffset
+	m.viewport.SetYOffset(line)
+	m.footnote = footnoteJump{footnote: selector.Footnote, from: from, to: m.viewport.YOffset}
+}
@@ -2,14 +2,19 @@ package renderer
 
 import (
 	"fmt"
+	"merlion/internal/controls"
+	"merlion/internal/links"
 	"merlion/internal/model"
+	"merlion/internal/ui/actions"
 	"merlion/internal/vault"
 	"merlion/internal/styles"
 	"merlion/internal/ui/navigation"
 	"merlion/internal/utils"
+	"merlion/internal/worklog"
 	"os/exec"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -40,6 +45,10 @@ type Model struct {
 	storeManager *vault.Manager
 	themeManager *styles.ThemeManager
 	spinner      spinner.Model
+	outline      outline
+	find         find
+	footnote     footnoteJump
+	rendered     string // The rendered note, without the highlights of the find
 }
 
 func New(themeManager *styles.ThemeManager, storeManager *vault.Manager) Model {
@@ -55,6 +64,8 @@ func New(themeManager *styles.ThemeManager, storeManager *vault.Manager) Model {
 
 	// Viewport for the Content
 	vp := viewport.New(0, 0)
+	vp.KeyMap.Up = controls.Keys.Up
+	vp.KeyMap.Down = controls.Keys.Down
 
 	// Loading Spinner
 	sp := spinner.New()
@@ -75,6 +86,7 @@ func New(themeManager *styles.ThemeManager, storeManager *vault.Manager) Model {
 		spinner:      sp,
 		infoHide:     themeManager.Config.InfoHidden,
 		infoPos:      position,
+		find:         newFind(),
 	}
 }
 
@@ -99,6 +111,10 @@ func (m *Model) ToggleHidePosition() {
 
 func (m *Model) SetNote(note *model.Note) {
 	m.renderer.ClearSelector()
+	if m.find.shown() && (note == nil || m.Note == nil || note.NoteID != m.Note.NoteID) {
+		m.closeFind()
+	}
+	m.footnote = footnoteJump{}
 	m.Note = note
 }
 
@@ -137,7 +153,11 @@ func (m *Model) Render() {
 	if err != nil {
 		m.SetErrorMessage(fmt.Sprintf("Error rendering markdown: %v", err))
 	} else {
+		m.rendered = rendered
 		m.viewport.SetContent(rendered)
+		if m.find.shown() {
+			m.search(false)
+		}
 	}
 }
 
@@ -149,7 +169,7 @@ func (m *Model) RefreshTheme() {
 
 	renderer, err := glamour.NewTermRenderer(
 		glamour.WithStyles(m.themeManager.GetRendererStyle()),
-		glamour.WithWordWrap(int(m.width)),
+		glamour.WithWordWrap(m.contentWidth()),
 	)
 	if err != nil {
 		log.Errorf("Error while creating new renderer %v", err)
@@ -161,10 +181,10 @@ func (m *Model) RefreshTheme() {
 
 func (m *Model) SetSize(width int, height int) {
 	m.width = width
-	m.viewport.Width = width
+	m.viewport.Width = m.contentWidth()
 	m.height = height
-	m.viewport.Height = height - map[bool]int{false: 2, true: 0}[m.infoHide]
-	m.renderer.SetWidth(width )
+	m.viewport.Height = height - map[bool]int{false: 2, true: 0}[m.infoHide] - m.findHeight()
+	m.renderer.SetWidth(m.contentWidth())
 	m.Render()
 }
 
@@ -177,15 +197,38 @@ func (m *Model) HandleSelector(selector *ansi.Selector) tea.Cmd {
 		log.Debug("selector is nil")
 		return nil
 	}
+	if selector.Footnote > 0 {
+		m.followFootnote(selector)
+		return nil
+	}
 	if selector.Title != "" {
 		log.Debug("Opening: ", "title", selector)
-		note := m.storeManager.SearchByTitle(selector.Title)
+		// [[Title#Heading|Alias]], [[#Heading]] points to the current note
+		target, _, _ := strings.Cut(selector.Title, "|")
+		title, heading, _ := strings.Cut(target, "#")
+		if strings.TrimSpace(title) == "" && m.Note != nil {
+			m.ScrollToHeading(heading)
+			return nil
+		}
+		note := links.Resolve(m.storeManager.Notes, title)
+		if note != nil && note.Content == nil {
+			fullNote, err := m.storeManager.GetFullNote(note.NoteID)
+			if err != nil {
+				m.SetErrorMessage(fmt.Sprintf("Error fetching note: %v", err))
+				return nil
+			}
+			note = fullNote
+		}
 		if note != nil {
 			m.SetNote(note)
 			m.Render()
+			m.viewport.GotoTop()
+			if heading != "" {
+				m.ScrollToHeading(heading)
+			}
 		} else {
 			// Create if doesn't exist
-			createArgs := []any{selector.Title}
+			createArgs := []any{strings.TrimSpace(title)}
 			cmd := navigation.SwitchUICmd(navigation.CreateUI, createArgs)
 			return cmd
 		}
@@ -200,14 +243,63 @@ func (m *Model) HandleSelector(selector *ansi.Selector) tea.Cmd {
 	return nil
 }
 
+// showAdjacentWorkLog moves to the previous (step < 0) or next (step > 0) work log
+// The caller opens it when its content isn't loaded yet
+func (m *Model) showAdjacentWorkLog(step int) tea.Cmd {
+	if m.Note == nil || !m.Note.IsWorkLog {
+		return nil
+	}
+	note := worklog.Adjacent(m.storeManager.Notes, m.themeManager.Config, *m.Note, step)
+	if note == nil {
+		return nil
+	}
+	if note.Content == nil {
+		open := actions.OpenNoteMsg{NoteID: note.NoteID}
+		return func() tea.Msg { return open }
+	}
+	m.SetNote(note)
+	m.Render()
+	return nil
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case m.outline.open:
+			return m, m.updateOutline(msg)
+		case m.find.typing:
+			return m, m.updateFind(msg)
+		case key.Matches(msg, controls.Keys.Back) && m.find.shown():
+			m.closeFind()
+			return m, nil
+		}
+	}
 	m.viewport, cmd = m.viewport.Update(msg)
 	cmds = append(cmds, cmd)
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, controls.Keys.PrevDay):
+			cmds = append(cmds, m.showAdjacentWorkLog(-1))
+		case key.Matches(msg, controls.Keys.NextDay):
+			cmds = append(cmds, m.showAdjacentWorkLog(1))
+		case key.Matches(msg, controls.Keys.Outline) && m.Note != nil:
+			m.ToggleOutline()
+		case key.Matches(msg, controls.Keys.PrevHeading):
+			m.scrollToHeading(-1)
+		case key.Matches(msg, controls.Keys.NextHeading):
+			m.scrollToHeading(1)
+		case msg.String() == findKey && m.Note != nil:
+			m.openFind()
+		case key.Matches(msg, controls.Keys.FindNext):
+			m.stepMatch(1)
+		case key.Matches(msg, controls.Keys.FindPrev):
+			m.stepMatch(-1)
+		}
+
 		switch msg.String() {
 		case "tab":
 			nextIdx := m.renderer.GetIdxToShowAsSelected() + 1
@@ -237,9 +329,9 @@ func (m Model) View() string {
 
 	// Case: Viewport ~~
 	if m.infoHide {
-		m.viewport.Width = m.width
-		m.viewport.Height = m.height
-		return m.viewport.View()
+		m.viewport.Width = m.contentWidth()
+		m.viewport.Height = m.height - m.findHeight()
+		return m.contentView()
 	}
 
 	// Case: Viewport + info ~~~~~~~~~~
@@ -277,21 +369,35 @@ func (m Model) View() string {
 		infoBar = ""
 	}
 
-	m.viewport.Width = m.width
-	m.viewport.Height = m.height - 2
+	m.viewport.Width = m.contentWidth()
+	m.viewport.Height = m.height - 2 - m.findHeight()
 	if m.infoPos == Top {
 		styleWithTopBorder = styleWithTopBorder.BorderTop(false)
 		return lipgloss.JoinVertical(
 			lipgloss.Left,
 			styleWithTopBorder.Render(infoBar),
-			m.viewport.View(),
+			m.contentView(),
 		)
 	} else {
 		styleWithTopBorder = styleWithTopBorder.BorderBottom(false)
 		return lipgloss.JoinVertical(
 			lipgloss.Left,
-			m.viewport.View(),
+			m.contentView(),
 			styleWithTopBorder.Render(infoBar),
 		)
 	}
 }
+
+// contentView returns the note, with its outline when open and the find bar below
+func (m Model) contentView() string {
+	content := m.viewport.View()
+	if m.outline.open && m.outlineBeside() {
+		content = lipgloss.JoinHorizontal(lipgloss.Top, content, m.outlineView(m.viewport.Height))
+	} else if m.outline.open {
+		content = m.outlineView(m.viewport.Height)
+	}
+	if m.find.shown() {
+		return lipgloss.JoinVertical(lipgloss.Left, content, m.findView())
+	}
+	return content
+}
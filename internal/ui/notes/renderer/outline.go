@@ -0,0 +1,172 @@
+package renderer
+
+import (
+	"strings"
+
+	"merlion/internal/controls"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Outline ---
+// The headings of the note, in a panel next to the content: moving in the outline scrolls
+// the note to the heading under the cursor
+
+const outlineWidth = 32
+
+// outline is the state of the outline panel
+type outline struct {
+	open   bool
+	cursor int
+	from   int // Offset of the viewport when the outline was opened, restored on cancel
+}
+
+func (m Model) OutlineOpen() bool {
+	return m.outline.open
+}
+
+// outlineBeside tells if the outline is displayed next to the content, or over it on small screens
+func (m Model) outlineBeside() bool {
+	return m.width >= 2*outlineWidth
+}
+
+// contentWidth returns the width of the rendered note, without the outline
+func (m Model) contentWidth() int {
+	if m.outline.open && m.outlineBeside() {
+		return m.width - outlineWidth
+	}
+	return m.width
+}
+
+func (m *Model) ToggleOutline() {
+	m.outline.open = !m.outline.open
+	current := m.currentHeading()
+	if m.outline.open {
+		m.outline.from = m.viewport.YOffset
+		m.outline.cursor = max(current, 0)
+	}
+	below := 0
+	if current >= 0 {
+		below = m.viewport.YOffset - m.renderer.Headings()[current].Line
+	}
+
+	// The note is wrapped again to its new width, the same section stays on top
+	m.SetSize(m.width, m.height)
+	if current >= 0 && current < len(m.renderer.Headings()) {
+		m.viewport.SetYOffset(m.renderer.Headings()[current].Line + below)
+	}
+}
+
+// currentHeading returns the index of the last heading above the top of the viewport, -1 if none
+func (m Model) currentHeading() int {
+	current := -1
+	for i, heading := range m.renderer.Headings() {
+		if heading.Line > m.viewport.YOffset {
+			break
+		}
+		current = i
+	}
+	return current
+}
+
+// scrollToHeading moves the viewport to the previous (step < 0) or next (step > 0) heading
+func (m *Model) scrollToHeading(step int) {
+	headings := m.renderer.Headings()
+	if step > 0 {
+		for _, heading := range headings {
+			if heading.Line > m.viewport.YOffset {
+				m.viewport.SetYOffset(heading.Line)
+				return
+			}
+		}
+		return
+	}
+	for i := len(headings) - 1; i >= 0; i-- {
+		if headings[i].Line < m.viewport.YOffset {
+			m.viewport.SetYOffset(headings[i].Line)
+			return
+		}
+	}
+}
+
+// ScrollToHeading moves the viewport to the heading with this text, as in [[Title#Heading]]
+func (m *Model) ScrollToHeading(text string) bool {
+	text = strings.TrimSpace(text)
+	for _, heading := range m.renderer.Headings() {
+		if strings.EqualFold(heading.Text, text) {
+			m.viewport.SetYOffset(heading.Line)
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Model) updateOutline(msg tea.KeyMsg) tea.Cmd {
+	headings := m.renderer.Headings()
+	switch {
+	case key.Matches(msg, controls.Keys.Up):
+		m.outline.cursor = max(m.outline.cursor-1, 0)
+	case key.Matches(msg, controls.Keys.Down):
+		m.outline.cursor = min(m.outline.cursor+1, len(headings)-1)
+	case key.Matches(msg, controls.Keys.Back):
+		from := m.outline.from
+		m.ToggleOutline()
+		m.viewport.SetYOffset(from)
+		return nil
+	case key.Matches(msg, controls.Keys.Select), key.Matches(msg, controls.Keys.Outline):
+		m.ToggleOutline()
+		return nil
+	case key.Matches(msg, controls.Keys.Quit):
+		return tea.Quit
+	default:
+		return nil
+	}
+	if m.outline.cursor >= 0 && m.outline.cursor < len(headings) {
+		m.viewport.SetYOffset(headings[m.outline.cursor].Line)
+	}
+	return nil
+}
+
+func (m Model) outlineView(height int) string {
+	styles := m.themeManager.Styles()
+	width := m.width
+	if m.outlineBeside() {
+		width = outlineWidth
+	}
+	panel := lipgloss.NewStyle().
+		Width(width - 1).
+		Height(height).
+		MaxHeight(height).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderLeft(true).
+		BorderForeground(m.themeManager.Current().BorderColor)
+
+	rows := []string{styles.Title.Render("Outline"), ""}
+	headings := m.renderer.Headings()
+	if len(headings) == 0 {
+		rows = append(rows, styles.Muted.Render(" No headings"))
+		return panel.Render(strings.Join(rows, "\n"))
+	}
+
+	top := headings[0].Level
+	for _, heading := range headings {
+		top = min(top, heading.Level)
+	}
+	// Keep the cursor in the visible rows
+	visible := max(height-len(rows), 1)
+	start := max(m.outline.cursor-visible+1, 0)
+	end := min(start+visible, len(headings))
+	for i := start; i < end; i++ {
+		heading := headings[i]
+		line := strings.Repeat("  ", heading.Level-top) + heading.Text
+		if i == m.outline.cursor {
+			rows = append(rows, styles.SelectedItem.Render(ansi.Truncate("> "+line, width-2, "…")))
+		} else {
+			rows = append(rows, styles.Text.Render(ansi.Truncate("  "+line, width-2, "…")))
+		}
+	}
+	return panel.Render(strings.Join(rows, "\n"))
+}
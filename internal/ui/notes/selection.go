@@ -0,0 +1,203 @@
+package Notes
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Multi Selection ---
+
+// selection contains the NoteID of the selected notes.
+// The items share the map of the model, so they display the marker without being rebuilt
+type selection map[string]bool
+
+func newSelection() selection {
+	return selection{}
+}
+
+func (s selection) clear() {
+	for id := range s {
+		delete(s, id)
+	}
+}
+
+// selectedIDs returns the selected notes, in the order of the vault
+func (m Model) selectedIDs() []string {
+	ids := []string{}
+	for _, note := range m.storeManager.Notes {
+		if m.selection[note.NoteID] {
+			ids = append(ids, note.NoteID)
+		}
+	}
+	return ids
+}
+
+// pruneSelection forgets the selected notes which are not in the vault anymore
+func (m *Model) pruneSelection() {
+	existing := make(map[string]bool, len(m.storeManager.Notes))
+	for _, note := range m.storeManager.Notes {
+		existing[note.NoteID] = true
+	}
+	for id := range m.selection {
+		if !existing[id] {
+			delete(m.selection, id)
+		}
+	}
+}
+
+// selectableItems returns the filtered notes of the list, or the notes of the tag under the cursor,
+// with the index of the note under the cursor, -1 on a tag
+func (m Model) selectableItems() ([]list.Item, int) {
+	if m.fileterTabs.CurrentTab().Kind == Tags {
+		cursor := -1
+		if idx := m.tagsList.CurrentItemIdx(); idx != nil {
+			cursor = *idx
+		}
+		return m.tagsList.SelectedGroupItems(), cursor
+	}
+	return m.noteList.VisibleItems(), m.noteList.Index()
+}
+
+// toggleSelect selects the note under the cursor, or all the notes of the tag under the cursor
+func toggleSelect(m *Model) tea.Cmd {
+	items, cursor := m.selectableItems()
+	if cursor < 0 {
+		m.setSelected(items, !m.allSelected(items))
+		return nil
+	}
+	if cursor >= len(items) {
+		return nil
+	}
+	if noteItem, ok := items[cursor].(item); ok {
+		id := noteItem.note.NoteID
+		if m.selection[id] {
+			delete(m.selection, id)
+		} else {
+			m.selection[id] = true
+		}
+		m.selectionAnchor = id
+	}
+	return nil
+}
+
+// selectRange selects the notes between the last toggled note and the cursor
+func selectRange(m *Model) tea.Cmd {
+	items, cursor := m.selectableItems()
+	if cursor < 0 || cursor >= len(items) {
+		return nil
+	}
+	anchor := -1
+	for i, listItem := range items {
+		if noteItem, ok := listItem.(item); ok && noteItem.note.NoteID == m.selectionAnchor {
+			anchor = i
+		}
+	}
+	if anchor < 0 {
+		return toggleSelect(m)
+	}
+	m.setSelected(items[min(anchor, cursor):max(anchor, cursor)+1], true)
+	return nil
+}
+
+// selectAll selects the filtered notes, or the notes of the tag, and clears the selection when they already are
+func selectAll(m *Model) tea.Cmd {
+	items, _ := m.selectableItems()
+	if len(items) > 0 && m.allSelected(items) {
+		m.selection.clear()
+		return nil
+	}
+	m.setSelected(items, true)
+	return nil
+}
+
+func clearSelection(m *Model) tea.Cmd {
+	m.selection.clear()
+	return nil
+}
+
+func (m *Model) setSelected(items []list.Item, selected bool) {
+	for _, listItem := range items {
+		if noteItem, ok := listItem.(item); ok {
+			if selected {
+				m.selection[noteItem.note.NoteID] = true
+			} else {
+				delete(m.selection, noteItem.note.NoteID)
+			}
+		}
+	}
+}
+
+func (m Model) allSelected(items []list.Item) bool {
+	for _, listItem := range items {
+		if noteItem, ok := listItem.(item); ok && !m.selection[noteItem.note.NoteID] {
+			return false
+		}
+	}
+	return true
+}
+
+// actionTargets returns the selected notes, or the note under the cursor without selection
+func (m Model) actionTargets() []string {
+	ids := m.selectedIDs()
+	if len(ids) == 0 {
+		if note := m.getCurrentNote(false); note != nil {
+			ids = []string{note.NoteID}
+		}
+	}
+	return ids
+}
+
+// openBulkMenu opens the actions on the selected notes, or on the note under the cursor without selection
+func openBulkMenu(m *Model) tea.Cmd {
+	ids := m.actionTargets()
+	if len(ids) == 0 {
+		return nil
+	}
+	m.focusedPane = noteList
+	m.bulkMenu.Open(ids, m.storeManager)
+	return nil
+}
+
+// openTransferMenu lists the other vaults to move or copy the selected notes, or the note under the cursor
+func openTransferMenu(action bulkAction) actionHandler {
+	return func(m *Model) tea.Cmd {
+		ids := m.actionTargets()
+		if len(ids) == 0 {
+			return nil
+		}
+		m.focusedPane = noteList
+		m.bulkMenu.OpenAt(ids, m.storeManager, action)
+		return nil
+	}
+}
+
+// deleteSelection asks to delete all the selected notes at once
+func deleteSelection(m *Model) tea.Cmd {
+	op := bulkOperation{action: bulkDelete, noteIDs: m.selectedIDs()}
+	return op.confirmCmd(m.storeManager)
+}
+
+// selectionLine is displayed above the list while notes are selected
+func (m Model) selectionLine(width int) string {
+	count := len(m.selectedIDs())
+	if count == 0 {
+		return ""
+	}
+	line := fmt.Sprintf("%d selected • %s actions • %s clear", count, m.keys.Manage.Help().Key, m.keys.Back.Help().Key)
+	return m.styles.Highlight.UnsetPadding().Render(ansi.Truncate(line, width-2, "…"))
+}
+
+// tagsListView displays the selection line above the tag groups
+func (m Model) tagsListView() string {
+	line := m.selectionLine(m.tagsList.Width())
+	if line == "" {
+		return m.tagsList.View()
+	}
+	tagsList := m.tagsList
+	tagsList.SetHeight(m.noteList.Height() - 1)
+	return lipgloss.JoinVertical(lipgloss.Left, line, tagsList.View())
+}
@@ -0,0 +1,171 @@
+package Notes
+
+import (
+	"fmt"
+	"strings"
+
+	"merlion/internal/controls"
+	"merlion/internal/sorting"
+	"merlion/internal/styles"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Sort Menu ---
+
+// Rows of the menu after the sort fields
+const (
+	directionRow = iota + 4
+	pinFavoritesRow
+	sortMenuRows
+)
+
+// sortMenu replaces the note list while choosing the sort of the current tab
+type sortMenu struct {
+	open            bool
+	tab             string
+	order           sorting.Order
+	cursor          int
+	property        textinput.Model
+	editingProperty bool
+	keys            controls.KeyMap
+}
+
+func newSortMenu() sortMenu {
+	property := textinput.New()
+	property.Placeholder = "property name"
+	property.Prompt = ""
+	property.CharLimit = 64
+	return sortMenu{property: property, keys: controls.Keys}
+}
+
+func (s *sortMenu) Open(tab string, order sorting.Order) {
+	s.open = true
+	s.tab = tab
+	s.order = order
+	s.editingProperty = false
+	s.property.SetValue(order.Property)
+	s.cursor = 0
+	for i, field := range sorting.Fields {
+		if field == order.By {
+			s.cursor = i
+		}
+	}
+}
+
+// Update returns true when the order changed and must be applied
+func (s *sortMenu) Update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if s.editingProperty {
+		switch {
+		case key.Matches(msg, s.keys.Select):
+			name := strings.TrimSpace(s.property.Value())
+			s.editingProperty = false
+			s.property.Blur()
+			if name == "" {
+				return false, nil
+			}
+			s.order.By = sorting.ByProperty
+			s.order.Property = name
+			return true, nil
+		case key.Matches(msg, s.keys.Back):
+			s.editingProperty = false
+			s.property.Blur()
+			s.property.SetValue(s.order.Property)
+			return false, nil
+		}
+		var cmd tea.Cmd
+		s.property, cmd = s.property.Update(msg)
+		return false, cmd
+	}
+
+	switch {
+	case key.Matches(msg, s.keys.Back), key.Matches(msg, s.keys.SortGroups):
+		s.open = false
+	case key.Matches(msg, s.keys.Up):
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case key.Matches(msg, s.keys.Down):
+		if s.cursor < sortMenuRows-1 {
+			s.cursor++
+		}
+	case key.Matches(msg, s.keys.Select), msg.String() == " ":
+		switch s.cursor {
+		case directionRow:
+			s.order.Descending = !s.order.Descending
+			return true, nil
+		case pinFavoritesRow:
+			s.order.PinFavorites = !s.order.PinFavorites
+			return true, nil
+		default:
+			field := sorting.Fields[s.cursor]
+			if field == sorting.ByProperty {
+				s.editingProperty = true
+				return false, s.property.Focus()
+			}
+			if s.order.By == field {
+				return false, nil
+			}
+			// Newest first for dates, alphabetical for titles
+			s.order.By = field
+			s.order.Descending = field != sorting.ByTitle
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s sortMenu) View(st *styles.Styles, width int, height int) string {
+	labels := map[string]string{
+		sorting.ByUpdated:  "Updated",
+		sorting.ByCreated:  "Created",
+		sorting.ByTitle:    "Title",
+		sorting.ByProperty: "Property",
+	}
+	checkbox := func(checked bool) string {
+		if checked {
+			return "(•)"
+		}
+		return "( )"
+	}
+
+	rows := []string{st.Title.Render("Sort " + s.tab), ""}
+	for i, field := range sorting.Fields {
+		label := fmt.Sprintf("%s %s", checkbox(s.order.By == field), labels[field])
+		if field == sorting.ByProperty {
+			if s.editingProperty {
+				label += ": " + s.property.View()
+			} else if s.order.Property != "" {
+				label += ": " + s.order.Property
+			}
+		}
+		rows = append(rows, s.renderRow(st, i, label))
+	}
+
+	direction := "Ascending"
+	if s.order.Descending {
+		direction = "Descending"
+	}
+	pinned := "off"
+	if s.order.PinFavorites {
+		pinned = "on"
+	}
+	rows = append(rows,
+		"",
+		s.renderRow(st, directionRow, "Direction: "+direction),
+		s.renderRow(st, pinFavoritesRow, "Favorites first: "+pinned),
+		"",
+		st.Muted.Render("enter/space select • esc close"),
+	)
+	return lipgloss.NewStyle().Width(width).Height(height).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func (s sortMenu) renderRow(st *styles.Styles, index int, label string) string {
+	if index == s.cursor {
+		return st.SelectedItem.Render("> " + label)
+	}
+	return st.Text.Render("  " + label)
+}
@@ -0,0 +1,273 @@
+package Notes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"merlion/internal/config"
+	"merlion/internal/controls"
+	"merlion/internal/query"
+	"merlion/internal/styles"
+	"merlion/internal/ui/navigation"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Tabs ---
+
+// Tab of the note list, the Custom ones come from the user config
+type Tab struct {
+	Kind  TabKind
+	Name  string
+	Query string
+}
+
+// String implements the Displayable interface
+func (t Tab) String() string {
+	if t.Kind == Custom {
+		return t.Name
+	}
+	return t.Kind.String()
+}
+
+func builtinTabs() []Tab {
+	return []Tab{{Kind: AllNotes}, {Kind: Favorites}, {Kind: WorkLogs}, {Kind: Tags}}
+}
+
+// allTabs returns the built-in tabs followed by the ones of the config
+func allTabs(cfg *config.UserConfig) []Tab {
+	result := builtinTabs()
+	for _, tab := range cfg.Tabs {
+		result = append(result, Tab{Kind: Custom, Name: tab.Name, Query: tab.Query})
+	}
+	return result
+}
+
+// TabNames returns the names of the tabs, accepted as first tab of the app
+func TabNames(cfg *config.UserConfig) []string {
+	names := []string{}
+	for _, tab := range allTabs(cfg) {
+		names = append(names, tab.String())
+	}
+	return names
+}
+
+// validateTab checks a user tab before saving it, previousName is empty for a new tab
+func validateTab(cfg *config.UserConfig, tab config.Tab, previousName string) error {
+	if tab.Name == "" {
+		return fmt.Errorf("the name is required")
+	}
+	for _, name := range TabNames(cfg) {
+		if strings.EqualFold(name, tab.Name) && !strings.EqualFold(name, previousName) {
+			return fmt.Errorf("a tab named %q already exists", name)
+		}
+	}
+	if strings.TrimSpace(tab.Query) == "" {
+		return fmt.Errorf("the query is required")
+	}
+	if _, err := query.Parse(tab.Query, time.Now()); err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	return nil
+}
+
+func openNewTab(m *Model) tea.Cmd {
+	// Start from the filter of the list
+	filter := ""
+	if m.fileterTabs.CurrentTab().Kind != Tags {
+		filter = m.noteList.FilterValue()
+	}
+	m.focusedPane = noteList
+	return m.tabEditor.Open("", config.Tab{Query: filter})
+}
+
+func openEditTab(m *Model) tea.Cmd {
+	tab := m.themeManager.Config.FindTab(m.fileterTabs.CurrentTab().String())
+	if tab == nil {
+		return nil
+	}
+	m.focusedPane = noteList
+	return m.tabEditor.Open(tab.Name, *tab)
+}
+
+// saveTab adds or replaces the tab edited in the tab editor
+func (m *Model) saveTab(previousName string, tab config.Tab) error {
+	cfg := m.themeManager.Config
+	if err := validateTab(cfg, tab, previousName); err != nil {
+		return err
+	}
+
+	tabs := append([]config.Tab{}, cfg.Tabs...)
+	replaced := false
+	for i := range tabs {
+		if previousName != "" && strings.EqualFold(tabs[i].Name, previousName) {
+			tab.Sort = tabs[i].Sort
+			tabs[i] = tab
+			replaced = true
+		}
+	}
+	if !replaced {
+		tabs = append(tabs, tab)
+	}
+	if err := m.themeManager.SetTabs(tabs); err != nil {
+		return err
+	}
+
+	m.fileterTabs.SetTabs(allTabs(cfg))
+	m.fileterTabs.SelectTab(tab.Name)
+	m.noteList.ResetFilter()
+	m.queryErr = nil
+	m.refreshNotesView()
+	return nil
+}
+
+// removeTabMsg is sent back by the confirmation dialog of removeTab
+type removeTabMsg struct {
+	name string
+}
+
+func removeTab(m *Model) tea.Cmd {
+	name := m.fileterTabs.CurrentTab().String()
+	if m.themeManager.Config.FindTab(name) == nil {
+		return nil
+	}
+	return navigation.AskConfirmationMsgCmd(
+		"Are you sure you want to remove this tab ?",
+		name,
+		navigation.DangerLvl,
+		removeTabMsg{name: name},
+		navigation.NoteUI,
+	)
+}
+
+// deleteTab removes the user tab from the config, the error is shown above the note list
+func (m *Model) deleteTab(name string) error {
+	tabs := []config.Tab{}
+	for _, tab := range m.themeManager.Config.Tabs {
+		if !strings.EqualFold(tab.Name, name) {
+			tabs = append(tabs, tab)
+		}
+	}
+	if err := m.themeManager.SetTabs(tabs); err != nil {
+		return err
+	}
+	m.refreshNotesView()
+	return nil
+}
+
+// moveTab moves the current user tab among the other user tabs
+func moveTab(offset int) actionHandler {
+	return func(m *Model) tea.Cmd {
+		name := m.fileterTabs.CurrentTab().String()
+		tabs := append([]config.Tab{}, m.themeManager.Config.Tabs...)
+		for i := range tabs {
+			if !strings.EqualFold(tabs[i].Name, name) {
+				continue
+			}
+			j := i + offset
+			if j < 0 || j >= len(tabs) {
+				return nil
+			}
+			tabs[i], tabs[j] = tabs[j], tabs[i]
+			if err := m.themeManager.SetTabs(tabs); err != nil {
+				m.tabErr = fmt.Errorf("failed to move the tab: %w", err)
+				return nil
+			}
+			m.fileterTabs.SetTabs(allTabs(m.themeManager.Config))
+			return nil
+		}
+		return nil
+	}
+}
+
+// Tab Editor ---
+
+// tabEditor replaces the note list while adding or editing a user tab
+type tabEditor struct {
+	open         bool
+	previousName string
+	inputs       []textinput.Model
+	focused      int
+	err          error
+	keys         controls.KeyMap
+}
+
+const (
+	tabNameInput = iota
+	tabQueryInput
+)
+
+func newTabEditor() tabEditor {
+	name := textinput.New()
+	name.Prompt = "Name:  "
+	name.Placeholder = "On-call"
+	name.CharLimit = 32
+	query := textinput.New()
+	query.Prompt = "Query: "
+	query.Placeholder = "tag:oncall -tag:archived"
+	return tabEditor{inputs: []textinput.Model{name, query}, keys: controls.Keys}
+}
+
+func (e *tabEditor) Open(previousName string, tab config.Tab) tea.Cmd {
+	e.open = true
+	e.previousName = previousName
+	e.err = nil
+	e.inputs[tabNameInput].SetValue(tab.Name)
+	e.inputs[tabQueryInput].SetValue(tab.Query)
+	e.focused = tabNameInput
+	e.inputs[tabQueryInput].Blur()
+	return e.inputs[tabNameInput].Focus()
+}
+
+func (e *tabEditor) focus(index int) tea.Cmd {
+	e.inputs[e.focused].Blur()
+	e.focused = index
+	return e.inputs[index].Focus()
+}
+
+// Update returns the tab to save when submitted
+func (e *tabEditor) Update(msg tea.KeyMsg) (*config.Tab, tea.Cmd) {
+	switch {
+	case key.Matches(msg, e.keys.Back):
+		e.open = false
+		return nil, nil
+	case key.Matches(msg, e.keys.NextTab), msg.String() == "down":
+		return nil, e.focus((e.focused + 1) % len(e.inputs))
+	case key.Matches(msg, e.keys.PrevTab), msg.String() == "up":
+		return nil, e.focus((e.focused + len(e.inputs) - 1) % len(e.inputs))
+	case key.Matches(msg, e.keys.Select):
+		if e.focused == tabNameInput {
+			return nil, e.focus(tabQueryInput)
+		}
+		return &config.Tab{
+			Name:  strings.TrimSpace(e.inputs[tabNameInput].Value()),
+			Query: strings.TrimSpace(e.inputs[tabQueryInput].Value()),
+		}, nil
+	}
+
+	var cmd tea.Cmd
+	e.inputs[e.focused], cmd = e.inputs[e.focused].Update(msg)
+	return nil, cmd
+}
+
+func (e tabEditor) View(st *styles.Styles, width int, height int) string {
+	title := "New tab"
+	if e.previousName != "" {
+		title = "Edit tab " + e.previousName
+	}
+	rows := []string{st.Title.Render(title), ""}
+	for _, input := range e.inputs {
+		input.Width = max(width-len(input.Prompt)-2, 1)
+		rows = append(rows, input.View())
+	}
+	rows = append(rows, "")
+	if e.err != nil {
+		rows = append(rows, st.Error.UnsetPadding().Width(width).Render(e.err.Error()), "")
+	}
+	rows = append(rows, st.Muted.Width(width).Render("enter save • tab next field • esc cancel"))
+	return lipgloss.NewStyle().Width(width).Height(height).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
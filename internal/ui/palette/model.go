@@ -0,0 +1,314 @@
+// Package palette implements the command palette, a fuzzy finder over the notes and the registered actions
+package palette
+
+import (
+	"sort"
+	"strings"
+
+	"merlion/internal/controls"
+	"merlion/internal/styles"
+	"merlion/internal/ui/actions"
+	"merlion/internal/ui/navigation"
+	"merlion/internal/vault"
+	"merlion/internal/vault/cloud"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+const (
+	maxWidth       = 80
+	maxVisibleRows = 12
+)
+
+type entryKind int
+
+const (
+	actionEntry entryKind = iota
+	noteEntry
+)
+
+type entry struct {
+	kind   entryKind
+	id     string
+	title  string
+	detail string // Shortcut of the action, aliases and tags of the note
+	search string // Text matched by the query, starts with the title
+}
+
+// recentID identifies the entry in the recently used list of the config
+func (e entry) recentID() string {
+	if e.kind == actionEntry {
+		return "action:" + e.id
+	}
+	return "note:" + e.id
+}
+
+type result struct {
+	entry   entry
+	matched []int // Indexes of the matched characters in the title
+}
+
+type entries []entry
+
+func (e entries) String(i int) string { return e[i].search }
+func (e entries) Len() int            { return len(e) }
+
+type Model struct {
+	input        textinput.Model
+	entries      entries
+	results      []result
+	cursor       int
+	width        int
+	height       int
+	keys         controls.KeyMap
+	themeManager *styles.ThemeManager
+	storeManager *vault.Manager
+}
+
+func NewModel(storeManager *vault.Manager, themeManager *styles.ThemeManager) navigation.View {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Search notes and actions..."
+
+	return Model{
+		input:        input,
+		keys:         controls.Keys,
+		themeManager: themeManager,
+		storeManager: storeManager,
+	}
+}
+
+func (m Model) SetCloudClient(client *cloud.Client) navigation.View {
+	m.storeManager.UpdateCloudClient(client)
+	return m
+}
+
+func (m Model) Init(args ...any) tea.Cmd {
+	return tea.Batch(textinput.Blink, tea.WindowSize())
+}
+
+func (m Model) loadEntries() entries {
+	result := entries{}
+	for _, action := range actions.All() {
+		detail := action.Binding.Help().Key
+		result = append(result, entry{
+			kind:   actionEntry,
+			id:     action.ID,
+			title:  action.Title,
+			detail: detail,
+			search: action.Title,
+		})
+	}
+
+	notes := append(m.storeManager.Notes[:0:0], m.storeManager.Notes...)
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[j].UpdatedAt.Before(notes[i].UpdatedAt)
+	})
+	for _, note := range notes {
+		details := append([]string{}, note.Aliases...)
+		for _, tag := range note.Tags {
+			details = append(details, "#"+tag)
+		}
+		result = append(result, entry{
+			kind:   noteEntry,
+			id:     note.NoteID,
+			title:  note.Title,
+			detail: strings.Join(details, " "),
+			search: note.Title + " " + strings.Join(details, " "),
+		})
+	}
+	return result
+}
+
+// filter updates the results for the current query, the recently used entries are ranked first
+func (m *Model) filter() {
+	recentRank := make(map[string]int)
+	for i, id := range m.themeManager.Config.RecentCommands {
+		recentRank[id] = i
+	}
+
+	query := strings.TrimSpace(m.input.Value())
+	recent, others := []result{}, []result{}
+	if query == "" {
+		for _, e := range m.entries {
+			if _, ok := recentRank[e.recentID()]; ok {
+				recent = append(recent, result{entry: e})
+			} else {
+				others = append(others, result{entry: e})
+			}
+		}
+	} else {
+		// Sorted by score by the fuzzy library
+		for _, match := range fuzzy.FindFrom(query, m.entries) {
+			e := m.entries[match.Index]
+			matched := []int{}
+			for _, index := range match.MatchedIndexes {
+				if index < len(e.title) {
+					matched = append(matched, index)
+				}
+			}
+			r := result{entry: e, matched: matched}
+			if _, ok := recentRank[e.recentID()]; ok {
+				recent = append(recent, r)
+			} else {
+				others = append(others, r)
+			}
+		}
+	}
+
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recentRank[recent[i].entry.recentID()] < recentRank[recent[j].entry.recentID()]
+	})
+	m.results = append(recent, others...)
+	if m.cursor >= len(m.results) {
+		m.cursor = max(len(m.results)-1, 0)
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (navigation.View, tea.Cmd) {
+	switch msg := msg.(type) {
+	case navigation.OpenPaletteMsg:
+		// Reset the query, the notes may have changed since the last opening
+		m.input.SetValue("")
+		m.input.Focus()
+		m.entries = m.loadEntries()
+		m.cursor = 0
+		m.filter()
+		return m, textinput.Blink
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.input.Width = min(maxWidth, m.width-4) - 8
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.CommandPalette), msg.String() == "ctrl+c":
+			return m, closeCmd(actions.CancelMsg{})
+
+		case key.Matches(msg, m.keys.Select):
+			if len(m.results) == 0 {
+				return m, nil
+			}
+			selected := m.results[m.cursor].entry
+			m.themeManager.AddRecentCommand(selected.recentID())
+			if selected.kind == actionEntry {
+				return m, closeCmd(actions.RunMsg{ID: selected.id})
+			}
+			return m, closeCmd(actions.OpenNoteMsg{NoteID: selected.id})
+
+		case msg.String() == "up" || msg.String() == "ctrl+p":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+
+		case msg.String() == "down" || msg.String() == "ctrl+n":
+			if m.cursor < len(m.results)-1 {
+				m.cursor++
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	previous := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.cursor = 0
+		m.filter()
+	}
+	return m, cmd
+}
+
+// closeCmd goes back to the notes, with the result of the palette
+func closeCmd(result actions.Msg) tea.Cmd {
+	return navigation.SwitchUICmd(navigation.NoteUI, []any{result})
+}
+
+func (m Model) View() string {
+	s := m.themeManager.Styles()
+	width := min(maxWidth, m.width-4)
+	innerWidth := width - 4
+
+	rows := []string{m.input.View(), ""}
+	if len(m.results) == 0 {
+		rows = append(rows, s.Muted.Render("No match"))
+	}
+
+	// Keep the cursor visible
+	visible := min(maxVisibleRows, max(m.height-10, 3))
+	start := 0
+	if m.cursor >= visible {
+		start = m.cursor - visible + 1
+	}
+	end := min(start+visible, len(m.results))
+	for i := start; i < end; i++ {
+		rows = append(rows, m.renderResult(m.results[i], i == m.cursor, innerWidth))
+	}
+
+	help := s.Muted.Render("↑/↓ navigate • enter select • esc close")
+	rows = append(rows, "", help)
+
+	box := s.ActiveContent.
+		Padding(0, 1).
+		Width(width).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m Model) renderResult(r result, selected bool, width int) string {
+	s := m.themeManager.Styles()
+
+	kind := "  "
+	if r.entry.kind == actionEntry {
+		kind = "> "
+	}
+
+	// Highlight the matched characters of the title
+	titleStyle := s.Text
+	if selected {
+		titleStyle = s.SelectedItem
+	}
+	matched := make(map[int]bool, len(r.matched))
+	for _, index := range r.matched {
+		matched[index] = true
+	}
+	var title strings.Builder
+	for i, char := range r.entry.title {
+		if matched[i] {
+			title.WriteString(s.Highlight.Render(string(char)))
+		} else {
+			title.WriteString(titleStyle.Render(string(char)))
+		}
+	}
+
+	left := s.Muted.Render(kind) + title.String()
+	detail := r.entry.detail
+	space := width - lipgloss.Width(left) - 1
+	if space <= 1 || detail == "" {
+		return left
+	}
+	if lipgloss.Width(detail) > space {
+		detail = truncate(detail, space)
+	}
+	padding := strings.Repeat(" ", max(width-lipgloss.Width(left)-lipgloss.Width(detail), 1))
+	return left + padding + s.Muted.Render(detail)
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	if width <= 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
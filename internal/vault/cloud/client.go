@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"merlion/internal/model"
+	"merlion/internal/vault/clientError"
 
 	"github.com/charmbracelet/log"
 )
@@ -82,7 +83,7 @@ func (c *Client) doRequest(method, path string, body interface{}) ([]byte, error
 	url := fmt.Sprintf("%s/%s", baseURL, path)
 	req, err := http.NewRequest(method, url, reqBody)
 	if err != nil {
-		log.Errorf("creating request: %w", err)
+		log.Errorf("creating request: %v", err)
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
@@ -106,6 +107,9 @@ func (c *Client) doRequest(method, path string, body interface{}) ([]byte, error
 		return nil, fmt.Errorf("reading response body: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusNotFound && method != http.MethodPost {
+		return nil, fmt.Errorf("%w: %s", clientError.ErrNoteNotFound, path)
+	}
 	if resp.StatusCode >= 400 {
 		return nil, fmt.Errorf("API error: %s (status: %d)", string(respBody), resp.StatusCode)
 	}
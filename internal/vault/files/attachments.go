@@ -0,0 +1,143 @@
+package files
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"merlion/internal/model"
+)
+
+// Attachments ---
+
+var (
+	// ![[image.png]], [[report.pdf|Report]]
+	wikiAttachmentRegex = regexp.MustCompile(`!?\[\[([^\[\]|#]+)(?:[#|][^\[\]]*)?\]\]`)
+	// ![alt](images/image.png), [Report](report.pdf)
+	markdownAttachmentRegex = regexp.MustCompile(`!?\[[^\[\]]*\]\(<?([^()<>\s]+)>?(?:\s+"[^"]*")?\)`)
+)
+
+// Attachments returns the files of the vault linked or embedded by the note, relative to the root of the vault.
+// Like Obsidian, a name is searched next to the note, at the root, then in the whole vault
+func (c *Client) Attachments(note model.Note) ([]string, error) {
+	if note.Content == nil {
+		return nil, fmt.Errorf("the content of %s is not loaded", note.NoteID)
+	}
+	names := []string{}
+	for _, match := range wikiAttachmentRegex.FindAllStringSubmatch(*note.Content, -1) {
+		names = append(names, strings.TrimSpace(match[1]))
+	}
+	for _, match := range markdownAttachmentRegex.FindAllStringSubmatch(*note.Content, -1) {
+		target := match[1]
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "#") {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(target); err == nil {
+			target = unescaped
+		}
+		names = append(names, target)
+	}
+
+	noteDir := filepath.Dir(filepath.Join(c.root, note.NoteID))
+	attachments := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		// Links to notes are not attachments
+		if ext := strings.ToLower(filepath.Ext(name)); ext == "" || ext == ".md" {
+			continue
+		}
+		path, err := c.findAttachment(noteDir, name)
+		if err != nil {
+			return nil, err
+		}
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		attachments = append(attachments, path)
+	}
+	return attachments, nil
+}
+
+// findAttachment returns the path of the file relative to the root, empty when missing
+func (c *Client) findAttachment(noteDir string, name string) (string, error) {
+	name = filepath.FromSlash(name)
+	for _, candidate := range []string{filepath.Join(noteDir, name), filepath.Join(c.root, name)} {
+		if rel, ok := c.relative(candidate); ok {
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return rel, nil
+			}
+		}
+	}
+
+	found := ""
+	err := filepath.WalkDir(c.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(d.Name(), filepath.Base(name)) {
+			found, _ = c.relative(path)
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search the attachment %s: %w", name, err)
+	}
+	return found, nil
+}
+
+// relative returns the path relative to the root, refusing the paths outside of the vault
+func (c *Client) relative(path string) (string, bool) {
+	rel, err := filepath.Rel(c.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// ReadAttachment returns the content of a file of the vault, relative to its root
+func (c *Client) ReadAttachment(name string) ([]byte, error) {
+	path := filepath.Join(c.root, filepath.FromSlash(name))
+	if _, ok := c.relative(path); !ok {
+		return nil, fmt.Errorf("the attachment %s is outside of the vault", name)
+	}
+	return os.ReadFile(path)
+}
+
+// WriteAttachment adds a file to the vault, relative to its root.
+// An existing file is kept when it has the same content, and refused otherwise
+func (c *Client) WriteAttachment(name string, data []byte) error {
+	path := filepath.Join(c.root, filepath.FromSlash(name))
+	if _, ok := c.relative(path); !ok {
+		return fmt.Errorf("the attachment %s is outside of the vault", name)
+	}
+	existing, err := os.ReadFile(path)
+	if err == nil {
+		if bytes.Equal(existing, data) {
+			return nil
+		}
+		return fmt.Errorf("another attachment named %s already exists", name)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := ensureDirectoryExists(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// DeleteAttachment removes a file of the vault, relative to its root
+func (c *Client) DeleteAttachment(name string) error {
+	path := filepath.Join(c.root, filepath.FromSlash(name))
+	if _, ok := c.relative(path); !ok {
+		return fmt.Errorf("the attachment %s is outside of the vault", name)
+	}
+	return os.Remove(path)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"merlion/internal/model"
+	"merlion/internal/vault/clientError"
 )
 
 type Client struct {
@@ -21,6 +22,19 @@ type Client struct {
 
 const Type = "Files"
 
+// Characters refused in the titles, they are file names
+const forbiddenChars = `/\:*?"<>|`
+
+// SafeTitle replaces the characters refused in the titles, `archive/2026/A` becomes `archive-2026-A`
+func SafeTitle(title string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(forbiddenChars, r) {
+			return '-'
+		}
+		return r
+	}, title)
+}
+
 func NewClient(root string, name string) (*Client, error) {
 	baseFolder, err := validatePath(root)
 	if err != nil {
@@ -45,13 +59,21 @@ func (c *Client) Type() string {
 
 func (c *Client) ListNotes() ([]model.Note, error) {
 	var notes []model.Note
+	// The templates are not notes, they are only listed when creating one
+	templatesDir, err := c.TemplatesDir()
+	if err != nil {
+		return nil, err
+	}
 
-	err := filepath.WalkDir(c.root, func(path string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(c.root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("failed to walk directory: %w", err)
 		}
 
 		if d.IsDir() {
+			if path == templatesDir {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -75,7 +97,7 @@ func (c *Client) GetNote(noteID string) (*model.Note, error) {
 	notePath := filepath.Join(c.root, noteID+".md")
 
 	if _, err := os.Stat(notePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("note not found: %s", noteID)
+		return nil, fmt.Errorf("%w: %s", clientError.ErrNoteNotFound, noteID)
 	}
 
 	note, err := c.parseNoteFile(notePath)
@@ -86,20 +108,18 @@ func (c *Client) GetNote(noteID string) (*model.Note, error) {
 }
 
 func (c *Client) CreateNote(req model.CreateNoteRequest) (*model.Note, error) {
-	// Verify that the title is valid
-	forbiddenChars := []string{"/", "\\", ":", "*", "?", "\"", "<", ">", "|"}
-	for _, char := range forbiddenChars {
-		if strings.Contains(req.Title, char) {
-			return nil, fmt.Errorf("invalid title: %s", req.Title)
-		}
-	}
-
 	noteID := req.Title
-	notePath := filepath.Join(c.root, noteID+".md")
+	notePath, err := c.titlePath(req.Title)
+	if err != nil {
+		return nil, err
+	}
 
 	if _, err := os.Stat(notePath); err == nil {
 		return nil, fmt.Errorf("note already exists: %s", noteID)
 	}
+	if err := ensureDirectoryExists(filepath.Dir(notePath)); err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	createdAt := now
@@ -122,18 +142,45 @@ func (c *Client) CreateNote(req model.CreateNoteRequest) (*model.Note, error) {
 		IsPublic:    getBoolOrDefault(req.IsPublic, false),
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
+		Properties:  req.Properties,
+		Aliases:     req.Aliases,
 	}
 
-	err := c.writeNoteFile(notePath, note)
+	err = c.writeNoteFile(notePath, note)
 	if err != nil {
 		return nil, fmt.Errorf("failed to write note file: %w", err)
 	}
+	// The update date is the modification time of the file
+	if req.UpdatedAt != nil {
+		if err := os.Chtimes(notePath, updatedAt, updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to set the update date: %w", err)
+		}
+	}
 
 	return &note, nil
 }
 
+// titlePath returns the path of the note with the title, the folders of the vault are separated by `/`.
+// The titles with an empty segment, `.`, `..` or a forbidden character are refused
+func (c *Client) titlePath(title string) (string, error) {
+	for _, part := range strings.Split(title, "/") {
+		if part == "" || part == "." || part == ".." || strings.ContainsAny(part, forbiddenChars) {
+			return "", fmt.Errorf("invalid title: %s", title)
+		}
+	}
+	path := filepath.Join(c.root, filepath.FromSlash(title)+".md")
+	if rel, err := filepath.Rel(c.root, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid title, outside of the vault: %s", title)
+	}
+	return path, nil
+}
+
 func (c *Client) UpdateNote(noteID string, req model.CreateNoteRequest) (*model.Note, error) {
-	oldPath := filepath.Join(c.root, noteID+".md")
+	oldPath := filepath.Join(c.root, filepath.FromSlash(noteID)+".md")
+
+	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", clientError.ErrNoteNotFound, noteID)
+	}
 
 	existingNote, err := c.parseNoteFile(oldPath)
 	if err != nil {
@@ -149,14 +196,33 @@ func (c *Client) UpdateNote(noteID string, req model.CreateNoteRequest) (*model.
 	updatedNote.IsWorkLog = getBoolOrDefault(req.IsWorkLog, existingNote.IsWorkLog)
 	updatedNote.IsPublic = getBoolOrDefault(req.IsPublic, existingNote.IsPublic)
 	updatedNote.UpdatedAt = time.Now()
+	if req.Properties != nil {
+		updatedNote.Properties = req.Properties
+	}
+	if req.Aliases != nil {
+		updatedNote.Aliases = req.Aliases
+	}
 
 	if req.Content == nil {
 		c.GetNote(noteID)
 		req.Content = existingNote.Content
 	}
 
-	newPath := filepath.Join(c.root, req.Title+".md")
+	newPath := oldPath
+	if req.Title != noteID {
+		newPath, err = c.titlePath(req.Title)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if oldPath != newPath {
+		// The title may move the note to another folder of the vault
+		if _, err := os.Stat(newPath); err == nil && !strings.EqualFold(oldPath, newPath) {
+			return nil, fmt.Errorf("note already exists: %s", req.Title)
+		}
+		if err := ensureDirectoryExists(filepath.Dir(newPath)); err != nil {
+			return nil, err
+		}
 		err = os.Rename(oldPath, newPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to rename note file: %w", err)
@@ -176,7 +242,7 @@ func (c *Client) DeleteNote(noteID string) error {
 	notePath := filepath.Join(c.root, noteID+".md")
 
 	if _, err := os.Stat(notePath); os.IsNotExist(err) {
-		return fmt.Errorf("note not found: %s", noteID)
+		return fmt.Errorf("%w: %s", clientError.ErrNoteNotFound, noteID)
 	}
 
 	err := moveToTrash(notePath)
@@ -220,6 +286,8 @@ func (c *Client) parseNoteFile(path string) (*model.Note, error) {
 		Title:      noteID,
 		Content:    &noteContent, // We could return a nil -> App will call GetNote to get the content (optional readContent bool arg)
 		Tags:       tags,
+		Aliases:    frontMatterGetList(frontMatter, keyAliases),
+		Properties: frontMatterProperties(frontMatter),
 		IsFavorite: isFavorite,
 		IsWorkLog:  isWorkLog,
 		IsPublic:   false,
@@ -235,17 +303,23 @@ func (c *Client) parseNoteFile(path string) (*model.Note, error) {
 }
 
 func (c *Client) writeNoteFile(path string, note model.Note) error {
-	frontMatter := map[string]any{
-		keyTags:       note.Tags,
-		keyIsFavorite: note.IsFavorite,
-		keyIsWorkLog:  note.IsWorkLog,
-		// keyCreatedAt:  note.CreatedAt.Format(time.RFC3339),
-		// keyUpdatedAt:  note.UpdatedAt.Format(time.RFC3339),
+	frontMatter := map[string]any{}
+	// Keep the properties set by Obsidian or its plugins
+	for key, value := range note.Properties {
+		frontMatter[key] = value
 	}
+	frontMatter[keyTags] = note.Tags
+	frontMatter[keyIsFavorite] = note.IsFavorite
+	frontMatter[keyIsWorkLog] = note.IsWorkLog
+	// frontMatter[keyCreatedAt] = note.CreatedAt.Format(time.RFC3339)
+	// frontMatter[keyUpdatedAt] = note.UpdatedAt.Format(time.RFC3339)
 
 	if note.WorkspaceID != nil {
 		frontMatter[keyWorkspace] = *note.WorkspaceID
 	}
+	if len(note.Aliases) > 0 {
+		frontMatter[keyAliases] = note.Aliases
+	}
 
 	fmBytes, err := json.MarshalIndent(frontMatter, "", "  ")
 	if err != nil {
@@ -0,0 +1,75 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"merlion/internal/model"
+)
+
+func TestUpdateNoteTitle(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "vault")
+	c, err := NewClient(root, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := "Body\n"
+	note, err := c.CreateNote(model.CreateNoteRequest{Title: "Plan", Content: &content, Tags: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, title := range []string{"", "../Plan", "a/../../Plan", "a//Plan", "./Plan", "a/", "Plan?", `a\Plan`} {
+		req := note.ToCreateRequest()
+		req.Title = title
+		if _, err := c.UpdateNote(note.NoteID, req); err == nil {
+			t.Errorf("UpdateNote(%q) expected an error", title)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "Plan.md")); err != nil {
+		t.Fatalf("the note moved after the refused titles: %v", err)
+	}
+
+	req := note.ToCreateRequest()
+	req.Title = "archive/2026/Plan"
+	moved, err := c.UpdateNote(note.NoteID, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.NoteID != "archive/2026/Plan" {
+		t.Errorf("NoteID = %q, expected archive/2026/Plan", moved.NoteID)
+	}
+	if _, err := os.Stat(filepath.Join(root, "archive", "2026", "Plan.md")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestListNotesSkipsTheTemplates(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "vault")
+	c, err := NewClient(root, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"Plan.md", "Templates/Meeting.md", "archive/Old.md"} {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("Body\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	notes, err := c.ListNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	for _, note := range notes {
+		ids[note.NoteID] = true
+	}
+	if len(ids) != 2 || !ids["Plan"] || !ids["archive/Old"] {
+		t.Errorf("ListNotes() = %v, expected Plan and archive/Old", ids)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 const (
 	keyTags       = "tags"
+	keyAliases    = "aliases"
 	keyIsFavorite = "favorite"
 	keyIsWorkLog  = "worklog"
 	keyCreatedAt  = "createdAt"
@@ -17,6 +18,22 @@ const (
 	keyWorkspace  = "workspaceId"
 )
 
+// frontMatterProperties returns the keys of the front matter not mapped to a note field
+func frontMatterProperties(m map[string]any) map[string]any {
+	var properties map[string]any
+	for key, value := range m {
+		switch key {
+		case keyTags, keyAliases, keyIsFavorite, keyIsWorkLog, keyCreatedAt, keyUpdatedAt, keyWorkspace:
+			continue
+		}
+		if properties == nil {
+			properties = make(map[string]any)
+		}
+		properties[key] = value
+	}
+	return properties
+}
+
 // splitFrontMatterContent splits an Obsidian file into front matter and content
 func splitFrontMatterContent(content string) (map[string]any, string, error) {
 	lines := strings.Split(content, "\n")
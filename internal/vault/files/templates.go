@@ -0,0 +1,31 @@
+package files
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+const defaultTemplatesFolder = "Templates"
+
+// obsidianTemplatesSettings is the config of the Obsidian core templates plugin
+// stored in `.obsidian/templates.json`
+type obsidianTemplatesSettings struct {
+	Folder string `json:"folder"`
+}
+
+// TemplatesDir returns the folder of the vault containing the note templates
+// Honors the Obsidian templates plugin setting, default to `Templates`
+func (c *Client) TemplatesDir() (string, error) {
+	folder := defaultTemplatesFolder
+
+	data, err := os.ReadFile(filepath.Join(c.root, ".obsidian", "templates.json"))
+	if err == nil {
+		var settings obsidianTemplatesSettings
+		if err := json.Unmarshal(data, &settings); err == nil && settings.Folder != "" {
+			folder = settings.Folder
+		}
+	}
+
+	return filepath.Join(c.root, filepath.FromSlash(folder)), nil
+}
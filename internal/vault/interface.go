@@ -2,6 +2,7 @@ package vault
 
 import (
 	"merlion/internal/model"
+	"merlion/internal/query"
 )
 
 // Encapsulation to introduce on device local Store
@@ -14,3 +15,17 @@ type Store interface {
 	CreateNote(req model.CreateNoteRequest) (*model.Note, error)
 	DeleteNote(noteID string) error
 }
+
+// Querier is implemented by the stores able to evaluate a query themselves
+type Querier interface {
+	QueryNotes(q query.Query) ([]model.Note, error)
+}
+
+// Attacher is implemented by the stores keeping the files linked by the notes, e.g. images.
+// The names of the attachments are relative to the root of the store
+type Attacher interface {
+	Attachments(note model.Note) ([]string, error)
+	ReadAttachment(name string) ([]byte, error)
+	WriteAttachment(name string, data []byte) error
+	DeleteAttachment(name string) error
+}
@@ -2,10 +2,13 @@
 package vault
 
 import (
+	"fmt"
+	"path/filepath"
 	"strings"
 
 	"merlion/internal/config"
 	"merlion/internal/model"
+	"merlion/internal/query"
 	"merlion/internal/vault/cloud"
 	"merlion/internal/vault/files"
 	sqlite "merlion/internal/vault/sqlite"
@@ -119,12 +122,57 @@ func (m *Manager) NextStore() error {
 	return nil
 }
 
+// UseStore sets the store with the given name as the active one, case insensitive
+// Files vaults can also be referenced by the name of their folder
+// Dev needs to call ListNoteMetadata after calling this, otherwise a panic occur
+func (m *Manager) UseStore(name string) error {
+	store := m.findStore(name)
+	if store == nil {
+		return fmt.Errorf("unknown vault: %s", name)
+	}
+	m.setActiveStore(store)
+	return nil
+}
+
+// findStore returns the store with the given name, case insensitive, or the Files vault in
+// the folder with that name. nil when there is none
+func (m *Manager) findStore(name string) Store {
+	for _, store := range m.stores {
+		if strings.EqualFold(store.Name(), name) || strings.EqualFold(filepath.Base(store.Name()), name) {
+			return store
+		}
+	}
+	return nil
+}
+
+// StoreNames returns the name of all the registered stores
+func (m *Manager) StoreNames() []string {
+	names := make([]string, 0, len(m.stores))
+	for _, store := range m.stores {
+		names = append(names, store.Name())
+	}
+	return names
+}
+
 func (m *Manager) setActiveStore(store Store) {
 	m.activeStore = store
 	m.Name = m.activeStore.Name()
 	log.Infof("Set active store to %s", m.Name)
 }
 
+// TemplatesDir returns the folder containing the note templates of the active store
+// Stores without their own templates folder use ~/.config/merlion/templates/<store name>
+func (m *Manager) TemplatesDir() (string, error) {
+	if provider, ok := m.activeStore.(interface{ TemplatesDir() (string, error) }); ok {
+		return provider.TemplatesDir()
+	}
+	configDir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "templates", strings.ToLower(m.activeStore.Name())), nil
+}
+
 // GetFullNote retrieves a specific note by ID with its complete content.
 // This method guarantees that the returned note will have its content field populated.
 func (m *Manager) GetFullNote(noteID string) (*model.Note, error) {
@@ -189,6 +237,17 @@ func (m *Manager) SearchByTitle(title string) *model.Note {
 	return nil
 }
 
+// Query returns the notes matching the query. The stores implementing Querier evaluate it,
+// the cached notes are filtered otherwise, their content is only searched when loaded
+func (m *Manager) Query(q query.Query) ([]model.Note, error) {
+	assert.Eq(m.internal__notesStore, m.activeStore.Name(), panic__consistency_msg)
+
+	if querier, ok := m.activeStore.(Querier); ok {
+		return querier.QueryNotes(q)
+	}
+	return q.Filter(m.Notes), nil
+}
+
 // GetTags returns all available tags from the cached notes.
 func (m *Manager) GetTags() []string {
 	assert.Eq(m.internal__notesStore, m.activeStore.Name(), panic__consistency_msg)
@@ -260,6 +319,11 @@ func (m *Manager) DeleteNote(noteID string) error {
 	if idx == -1 {
 		log.Fatalf("Deleted a note which wasn't cached locally - Should not happen")
 	}
-	utils.Remove(m.Notes, idx)
+	m.Notes = utils.Remove(m.Notes, idx)
 	return nil
 }
+
+// StoreType returns the type of the active store, e.g. files.Type
+func (m *Manager) StoreType() string {
+	return m.activeStore.Type()
+}
@@ -95,6 +95,14 @@ func (c *Client) GetNote(noteID string) (*model.Note, error) {
 func (c *Client) CreateNote(req model.CreateNoteRequest) (*model.Note, error) {
 	noteID := uuid.New().String()
 	now := time.Now()
+	// Kept when the note comes from another vault
+	createdAt, updatedAt := now, now
+	if req.CreatedAt != nil {
+		createdAt = *req.CreatedAt
+	}
+	if req.UpdatedAt != nil {
+		updatedAt = *req.UpdatedAt
+	}
 
 	var tagsJSON []byte
 	var err error
@@ -130,7 +138,7 @@ func (c *Client) CreateNote(req model.CreateNoteRequest) (*model.Note, error) {
 
 	_, err = stmt.Exec(
 		noteID, req.Title, req.Content, string(tagsJSON),
-		isFavorite, isWorkLog, now, now,
+		isFavorite, isWorkLog, createdAt, updatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute statement for create: %w", err)
@@ -143,8 +151,8 @@ func (c *Client) CreateNote(req model.CreateNoteRequest) (*model.Note, error) {
 		Tags:       req.Tags,
 		IsFavorite: isFavorite,
 		IsWorkLog:  isWorkLog,
-		CreatedAt:  now,
-		UpdatedAt:  now,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
 	}, nil
 }
 
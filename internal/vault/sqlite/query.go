@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"merlion/internal/model"
+	"merlion/internal/query"
+)
+
+// QueryNotes returns the notes matching the query, most recently updated first
+// The terms which can be expressed in SQL filter the rows, the query is then evaluated on the result
+func (c *Client) QueryNotes(q query.Query) ([]model.Note, error) {
+	where, args := toSQL(q)
+	conditions := append([]string{"is_trash = false"}, where...)
+	rows, err := c.db.Query(`
+		SELECT note_id, title, content, tags, is_favorite,
+			   is_work_log, created_at, updated_at
+		FROM notes
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY updated_at DESC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []model.Note{}
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan note during query: %w", err)
+		}
+		if q.Match(*note) {
+			notes = append(notes, *note)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return notes, nil
+}
+
+// toSQL translates the terms of the query to SQL conditions
+// A term is skipped when SQL can't match it exactly: the aliases don't exist in this store, and
+// LIKE and lower() only ignore the case of ASCII letters
+func toSQL(q query.Query) ([]string, []any) {
+	where := []string{}
+	args := []any{}
+	add := func(term query.Term, condition string, values ...any) {
+		if term.Negated {
+			condition = "NOT (" + condition + ")"
+		}
+		where = append(where, condition)
+		args = append(args, values...)
+	}
+
+	for _, term := range q.Terms {
+		switch term.Field {
+		case query.FieldTitle:
+			if isASCII(term.Value) {
+				add(term, `title LIKE ? ESCAPE '\'`, likePattern(term.Value))
+			}
+		case query.FieldContent:
+			if isASCII(term.Value) {
+				add(term, `coalesce(content, '') LIKE ? ESCAPE '\'`, likePattern(term.Value))
+			}
+		case query.FieldTag:
+			if isASCII(term.Value) {
+				add(term, `json_valid(tags) AND EXISTS (
+					SELECT 1 FROM json_each(notes.tags)
+					WHERE lower(json_each.value) = ? OR lower(json_each.value) LIKE ? ESCAPE '\'
+				)`, term.Value, escapeLike(term.Value)+"/%")
+			}
+		case query.FieldIs:
+			switch term.Value {
+			case query.IsFavorite:
+				add(term, "is_favorite")
+			case query.IsWorkLog:
+				add(term, "is_work_log")
+			case query.IsUntagged:
+				add(term, "(NOT json_valid(tags) OR json_array_length(tags) = 0)")
+			}
+		case query.FieldCreated, query.FieldUpdated:
+			column := "created_at"
+			if term.Field == query.FieldUpdated {
+				column = "updated_at"
+			}
+			conditions := []string{}
+			values := []any{}
+			if !term.From.IsZero() {
+				conditions = append(conditions, "julianday("+column+") >= julianday(?)")
+				values = append(values, term.From)
+			}
+			if !term.To.IsZero() {
+				conditions = append(conditions, "julianday("+column+") < julianday(?)")
+				values = append(values, term.To)
+			}
+			if len(conditions) > 0 {
+				add(term, strings.Join(conditions, " AND "), values...)
+			}
+		}
+		// Words and phrases also match the aliases, evaluated after the query
+	}
+	return where, args
+}
+
+func isASCII(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
+
+func likePattern(text string) string {
+	return "%" + escapeLike(text) + "%"
+}
@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"merlion/internal/model"
+	"merlion/internal/query"
+
+	"github.com/charmbracelet/log"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	log.SetOutput(io.Discard)
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("MERLION_DB_PATH", filepath.Join(tmp, "notes.db"))
+	return NewClient()
+}
+
+// TestToSQL checks that the SQL conditions select the notes matched by the query, the terms
+// translated to SQL must select exactly the same notes
+func TestToSQL(t *testing.T) {
+	client := newTestClient(t)
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, zone)
+	day := func(year int, month time.Month, d int, hour int) time.Time {
+		return time.Date(year, month, d, hour, 0, 0, 0, zone)
+	}
+	notes := []struct {
+		title, content string
+		tags           []string
+		favorite       bool
+		workLog        bool
+		created        time.Time
+		updated        time.Time
+	}{
+		{"Deploy checklist", "Run the migrations", []string{"work/infra"}, true, false, day(2025, 1, 2, 10), now.Add(-2 * time.Hour)},
+		{"2025-06-14", "Met Alice about the DEPLOY", []string{"journal"}, false, true, day(2025, 6, 14, 9), day(2025, 6, 14, 18)},
+		{"Old ideas", "100% done_soon", []string{"Archived", "workshop"}, false, false, day(2024, 3, 1, 0), day(2024, 12, 31, 23)},
+		{"Inbox", "", nil, false, false, day(2025, 6, 15, 1), day(2025, 6, 15, 1)},
+		{"Café", "Crème brûlée", []string{"Food"}, false, false, day(2025, 6, 1, 0), day(2025, 6, 2, 0)},
+	}
+	for _, note := range notes {
+		content := note.content
+		favorite, workLog := note.favorite, note.workLog
+		created, updated := note.created, note.updated
+		_, err := client.CreateNote(model.CreateNoteRequest{
+			Title: note.title, Content: &content, Tags: note.tags,
+			IsFavorite: &favorite, IsWorkLog: &workLog, CreatedAt: &created, UpdatedAt: &updated,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := client.ListNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := []string{
+		"", "title:deploy", "content:deploy", "-content:deploy", "title:DEPLOY -is:fav",
+		`content:"100%"`, "content:e_s", "content:done_", "title:café", "content:brûlée",
+		"tag:work", "tag:WORK/infra", "tag:wor", "tag:archived", "-tag:archived", "tag:food",
+		"is:fav", "-is:fav", "is:worklog", "is:untagged", "-is:untagged",
+		"created:2025-06-14", "created:<2025-01-02", "created:<=2025-01-02", "created:>2025-01-02",
+		"created:>=2025-01-02", "-created:>=2025-01-02", "updated:today", "updated:yesterday",
+		"updated:<1d", "updated:>1d", "updated:>=5m", "updated:12h",
+		"deploy", "-deploy", `"the migrations"`, "created:>=2025-01-01 -tag:journal -is:untagged",
+	}
+	for _, input := range queries {
+		t.Run(input, func(t *testing.T) {
+			q, err := query.Parse(input, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := titles(q.Filter(all))
+
+			where, args := toSQL(q)
+			conditions := append([]string{"is_trash = false"}, where...)
+			rows, err := client.db.Query(`
+				SELECT note_id, title, content, tags, is_favorite,
+					   is_work_log, created_at, updated_at
+				FROM notes
+				WHERE `+strings.Join(conditions, " AND ")+`
+				ORDER BY updated_at DESC
+			`, args...)
+			if err != nil {
+				t.Fatalf("invalid SQL %v: %v", where, err)
+			}
+			defer rows.Close()
+			selected := []model.Note{}
+			for rows.Next() {
+				note, err := scanNote(rows)
+				if err != nil {
+					t.Fatal(err)
+				}
+				selected = append(selected, *note)
+			}
+
+			if translated(q) {
+				if got := titles(selected); got != expected {
+					t.Errorf("SQL selects [%s], the query matches [%s]", got, expected)
+				}
+			} else if got := titles(q.Filter(selected)); got != expected {
+				t.Errorf("SQL misses notes: [%s], the query matches [%s]", got, expected)
+			}
+
+			result, err := client.QueryNotes(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := titles(result); got != expected {
+				t.Errorf("QueryNotes returns [%s], the query matches [%s]", got, expected)
+			}
+		})
+	}
+}
+
+// translated returns true when toSQL translates every term of the query
+func translated(q query.Query) bool {
+	for _, term := range q.Terms {
+		switch {
+		case term.Field == query.FieldText:
+			return false
+		case term.Field == query.FieldIs && term.Value == query.IsPublic:
+			return false
+		case !isASCII(term.Value):
+			return false
+		}
+	}
+	return true
+}
+
+func titles(notes []model.Note) string {
+	result := []string{}
+	for _, note := range notes {
+		result = append(result, note.Title)
+	}
+	return strings.Join(result, ", ")
+}
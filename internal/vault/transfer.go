@@ -0,0 +1,170 @@
+package vault
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"merlion/internal/model"
+	"merlion/internal/utils/assert"
+	"merlion/internal/vault/files"
+
+	"github.com/charmbracelet/log"
+)
+
+// Transfers between vaults ---
+
+// Transfer is the result of a note copied or moved to another vault
+type Transfer struct {
+	Note        *model.Note // The note created in the other vault
+	Renamed     bool        // The title was taken in the other vault
+	Attachments []string    // The attachments copied with the note
+	Skipped     []string    // The attachments the other vault can't keep
+}
+
+// CopyToStore creates the note in the store with the given name, with its tags, flags, timestamps and attachments.
+// A note with the same title in the other store is kept, the copy gets a title like `Title (2)`
+func (m *Manager) CopyToStore(noteID string, storeName string) (*Transfer, error) {
+	assert.Eq(m.internal__notesStore, m.activeStore.Name(), panic__consistency_msg)
+
+	target := m.findStore(storeName)
+	if target == nil {
+		return nil, fmt.Errorf("unknown vault: %s", storeName)
+	}
+	if target.Name() == m.activeStore.Name() {
+		return nil, fmt.Errorf("the note is already in %s", storeName)
+	}
+
+	note, err := m.GetFullNote(noteID)
+	if err != nil {
+		return nil, err
+	}
+	transfer := &Transfer{Attachments: []string{}, Skipped: []string{}}
+
+	title, err := availableTitle(target, m.transferTitle(note.Title, target))
+	if err != nil {
+		return nil, err
+	}
+	transfer.Renamed = title != m.transferTitle(note.Title, target)
+
+	// Attachments first, the note is only created once its files are in the other vault.
+	// The files it didn't have are removed when the transfer fails
+	written, err := m.copyAttachments(*note, target, transfer)
+	if err != nil {
+		removeAttachments(target, written)
+		return nil, err
+	}
+
+	req := note.ToCreateRequest()
+	req.Title = title
+	req.CreatedAt = &note.CreatedAt
+	req.UpdatedAt = &note.UpdatedAt
+	req.Properties = note.Properties
+	req.Aliases = note.Aliases
+	created, err := target.CreateNote(req)
+	if err != nil {
+		removeAttachments(target, written)
+		return nil, fmt.Errorf("creating the note in %s: %w", target.Name(), err)
+	}
+	transfer.Note = created
+	return transfer, nil
+}
+
+// copyAttachments writes the attachments of the note to the target store, and returns the ones it didn't have
+func (m *Manager) copyAttachments(note model.Note, target Store, transfer *Transfer) ([]string, error) {
+	written := []string{}
+	source, ok := m.activeStore.(Attacher)
+	if !ok {
+		return written, nil
+	}
+	names, err := source.Attachments(note)
+	if err != nil {
+		return written, err
+	}
+	destination, ok := target.(Attacher)
+	for _, name := range names {
+		if !ok {
+			transfer.Skipped = append(transfer.Skipped, name)
+			continue
+		}
+		data, err := source.ReadAttachment(name)
+		if err != nil {
+			return written, fmt.Errorf("reading the attachment %s: %w", name, err)
+		}
+		_, err = destination.ReadAttachment(name)
+		existed := err == nil
+		if err := destination.WriteAttachment(name, data); err != nil {
+			return written, fmt.Errorf("copying the attachment %s to %s: %w", name, target.Name(), err)
+		}
+		if !existed {
+			written = append(written, name)
+		}
+		transfer.Attachments = append(transfer.Attachments, name)
+	}
+	return written, nil
+}
+
+// removeAttachments deletes the attachments written by a failed transfer
+func removeAttachments(target Store, names []string) {
+	destination, ok := target.(Attacher)
+	if !ok {
+		return
+	}
+	for _, name := range names {
+		if err := destination.DeleteAttachment(name); err != nil {
+			log.Error("Failed to remove the attachment of a failed transfer", "name", name, "error", err)
+		}
+	}
+}
+
+// MoveToStore copies the note to the store with the given name, then deletes it from the active one.
+// The note is only deleted once created in the other store, and goes to the trash of its vault
+func (m *Manager) MoveToStore(noteID string, storeName string) (*Transfer, error) {
+	transfer, err := m.CopyToStore(noteID, storeName)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.DeleteNote(noteID); err != nil {
+		return transfer, fmt.Errorf("copied to %s, but not deleted: %w", storeName, err)
+	}
+	return transfer, nil
+}
+
+// transferTitle adapts the title to the other store: the folders of a Files vault are kept in another
+// Files vault, so the relative links stay valid, and dropped otherwise.
+// The characters a Files vault can't use in a name are replaced
+func (m *Manager) transferTitle(title string, target Store) string {
+	fromFiles, toFiles := m.activeStore.Type() == files.Type, target.Type() == files.Type
+	switch {
+	case fromFiles && toFiles:
+		return title
+	case fromFiles:
+		return path.Base(title)
+	case toFiles:
+		return files.SafeTitle(title)
+	default:
+		return title
+	}
+}
+
+// UniqueTitle returns the title, or the first of `Title (2)`, `Title (3)`... not used in the active store
+func (m *Manager) UniqueTitle(title string) (string, error) {
+	return availableTitle(m.activeStore, title)
+}
+
+// availableTitle returns the title, or the first of `Title (2)`, `Title (3)`... not used in the store
+func availableTitle(store Store, title string) (string, error) {
+	notes, err := store.ListNotes()
+	if err != nil {
+		return "", fmt.Errorf("listing the notes of %s: %w", store.Name(), err)
+	}
+	taken := make(map[string]bool, len(notes))
+	for _, note := range notes {
+		taken[strings.ToLower(note.Title)] = true
+	}
+	candidate := title
+	for i := 2; taken[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s (%d)", title, i)
+	}
+	return candidate, nil
+}
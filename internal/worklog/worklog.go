@@ -0,0 +1,124 @@
+// Package worklog implements the daily journal, one work log note per day
+// The title of a journal note is the day formatted with the configured pattern
+package worklog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"merlion/internal/config"
+	"merlion/internal/model"
+	"merlion/internal/templates"
+	"merlion/internal/vault"
+)
+
+const (
+	DefaultTitleFormat = "2006-01-02"
+	entryTimeFormat    = "15:04"
+)
+
+// TitleFormat returns the Go time layout used to name the journal notes
+func TitleFormat(cfg *config.UserConfig) string {
+	if cfg == nil || cfg.WorkLogTitleFormat == "" {
+		return DefaultTitleFormat
+	}
+	return cfg.WorkLogTitleFormat
+}
+
+// Title returns the title of the journal note of the day
+func Title(cfg *config.UserConfig, day time.Time) string {
+	return day.Format(TitleFormat(cfg))
+}
+
+// Date returns the day of a journal note, based on its title
+func Date(cfg *config.UserConfig, note model.Note) (time.Time, bool) {
+	day, err := time.ParseInLocation(TitleFormat(cfg), strings.TrimSpace(note.Title), time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+// Open returns the journal note of the day, creating it from the configured template if needed
+// ListNoteMetadata needs to be called on the manager before calling Open
+func Open(m *vault.Manager, cfg *config.UserConfig, day time.Time) (*model.Note, error) {
+	title := Title(cfg, day)
+	if note := m.SearchByTitle(title); note != nil {
+		return m.GetFullNote(note.NoteID)
+	}
+
+	req := model.CreateNoteRequest{Title: title}
+	if cfg != nil && cfg.WorkLogTemplate != "" {
+		dir, err := m.TemplatesDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find templates directory: %w", err)
+		}
+		template, err := templates.Load(dir, cfg.WorkLogTemplate)
+		if err != nil {
+			return nil, err
+		}
+		req = template.Apply(title, day)
+		req.Title = title
+	}
+
+	isWorkLog := true
+	req.IsWorkLog = &isWorkLog
+	if req.Content == nil {
+		empty := ""
+		req.Content = &empty
+	}
+	createdAt := day
+	req.CreatedAt = &createdAt
+
+	note, err := m.CreateNote(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work log %s: %w", title, err)
+	}
+	return note, nil
+}
+
+// Append adds a timestamped bullet at the end of the note
+func Append(m *vault.Manager, note *model.Note, text string, now time.Time) (*model.Note, error) {
+	content := ""
+	if note.Content != nil {
+		content = *note.Content
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += fmt.Sprintf("- %s %s\n", now.Format(entryTimeFormat), strings.TrimSpace(text))
+
+	req := note.ToCreateRequest()
+	req.Content = &content
+	return m.UpdateNote(note.NoteID, req)
+}
+
+// Adjacent returns the closest journal note before (step < 0) or after (step > 0) the note
+// Days without a journal note are skipped
+func Adjacent(notes []model.Note, cfg *config.UserConfig, from model.Note, step int) *model.Note {
+	fromDay, ok := Date(cfg, from)
+	if !ok {
+		return nil
+	}
+
+	var closest *model.Note
+	var closestDay time.Time
+	for i := range notes {
+		day, ok := Date(cfg, notes[i])
+		if !ok || !notes[i].IsWorkLog {
+			continue
+		}
+		if step < 0 && day.Before(fromDay) && (closest == nil || day.After(closestDay)) {
+			closest, closestDay = &notes[i], day
+		}
+		if step > 0 && day.After(fromDay) && (closest == nil || day.Before(closestDay)) {
+			closest, closestDay = &notes[i], day
+		}
+	}
+	if closest == nil {
+		return nil
+	}
+	note := *closest
+	return &note
+}
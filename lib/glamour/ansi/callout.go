@@ -0,0 +1,86 @@
+package ansi
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// calloutAliases maps the other names of the callout types of Obsidian to their type.
+var calloutAliases = map[string]string{
+	"summary":   "abstract",
+	"tldr":      "abstract",
+	"hint":      "tip",
+	"important": "tip",
+	"check":     "success",
+	"done":      "success",
+	"help":      "question",
+	"faq":       "question",
+	"caution":   "warning",
+	"attention": "warning",
+	"fail":      "failure",
+	"missing":   "failure",
+	"error":     "danger",
+	"cite":      "quote",
+}
+
+// A CalloutElement is used to render the callouts of Obsidian, a blockquote
+// with a title and a bar in the color of its type.
+type CalloutElement struct {
+	BlockElement
+	Type     string
+	Title    string
+	Foldable bool
+	Folded   bool
+}
+
+// calloutType returns the style of the callout type, the note one for the unknown types.
+func calloutType(ctx RenderContext, name string) StyleCalloutType {
+	types := ctx.options.Styles.Callout.Types
+	if s, ok := types[name]; ok {
+		return s
+	}
+	if s, ok := types[calloutAliases[name]]; ok {
+		return s
+	}
+	return types["note"]
+}
+
+// Render renders a CalloutElement.
+func (e *CalloutElement) Render(w io.Writer, ctx RenderContext) error {
+	bs := ctx.blockStack
+	rules := ctx.options.Styles.Callout
+	kind := calloutType(ctx, e.Type)
+
+	// The bar takes the color of the type
+	e.Style = cascadeStyle(bs.Current().Style, rules.StyleBlock, false)
+	if e.Style.IndentToken != nil {
+		var token bytes.Buffer
+		renderText(&token, ctx.options.ColorProfile, kind.StylePrimitive, *e.Style.IndentToken)
+		t := token.String()
+		e.Style.IndentToken = &t
+	}
+	if err := e.BlockElement.Render(w, ctx); err != nil {
+		return err
+	}
+
+	title := e.Title
+	if title == "" {
+		title = cases.Title(language.English).String(e.Type)
+	}
+	if kind.Icon != "" {
+		title = kind.Icon + " " + title
+	}
+	if e.Folded {
+		title += rules.Folded
+	} else if e.Foldable {
+		title += rules.Unfolded
+	}
+	style := cascadeStylePrimitives(bs.Current().Style.StylePrimitive, rules.Title, kind.StylePrimitive)
+	renderText(bs.Current().Block, ctx.options.ColorProfile, style, strings.TrimSpace(title))
+	_, _ = io.WriteString(bs.Current().Block, "\n")
+	return nil
+}
@@ -14,7 +14,9 @@ type RenderContext struct {
 	blockStack *BlockStack
 	table      *TableElement
 
-	Selector *SelectorContext
+	Selector  *SelectorContext
+	Outline   *OutlineContext
+	Footnotes *FootnoteContext
 
 	stripper *bluemonday.Policy
 }
@@ -26,6 +28,8 @@ func NewRenderContext(options Options) RenderContext {
 		blockStack: &BlockStack{},
 		table:      &TableElement{},
 		Selector:   &SelectorContext{idxToShowAsDisplay: -1},
+		Outline:    &OutlineContext{},
+		Footnotes:  &FootnoteContext{},
 		stripper:   bluemonday.StrictPolicy(),
 	}
 }
@@ -47,4 +51,6 @@ func (ctx *RenderContext) SetWordWrap(wordwrap int) {
 // Reset the context state - should be use before a render
 func (ctx *RenderContext) Reset() {
 	ctx.Selector.resetASTWalkState()
+	ctx.Outline.resetASTWalkState()
+	ctx.Footnotes.resetASTWalkState()
 }
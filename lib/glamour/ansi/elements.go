@@ -60,6 +60,7 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 		n := node.(*ast.Heading)
 		he := &HeadingElement{
 			Level: n.Level,
+			Text:  headingText(n, source),
 			First: node.PreviousSibling() == nil,
 		}
 		return Element{
@@ -75,6 +76,17 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 			if kind == ast.KindListItem {
 				return Element{}
 			}
+			// The first paragraph of a footnote follows its number, the next ones are spaced
+			if kind == astext.KindFootnote {
+				if node.PreviousSibling() == nil {
+					return Element{}
+				}
+				return Element{
+					Entering: "\n",
+					Renderer: &ParagraphElement{},
+					Finisher: &ParagraphElement{},
+				}
+			}
 		}
 		return Element{
 			Renderer: &ParagraphElement{
@@ -96,6 +108,24 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 			Finisher: e,
 		}
 
+	case ext.KindCallout:
+		n := node.(*ext.Callout)
+		e := &CalloutElement{
+			BlockElement: BlockElement{
+				Block:  &bytes.Buffer{},
+				Margin: true,
+			},
+			Type:     n.CalloutType,
+			Title:    n.Title,
+			Foldable: n.Foldable,
+			Folded:   n.Folded,
+		}
+		return Element{
+			Entering: "\n",
+			Renderer: e,
+			Finisher: e,
+		}
+
 	// Lists
 	case ast.KindList:
 		s := ctx.options.Styles.List.StyleBlock
@@ -210,6 +240,21 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 			},
 		}
 
+	case ext.KindHighlight:
+		n := node.(*ext.Highlight)
+		s := string(n.Text(source)) //nolint: staticcheck
+
+		return Element{
+			Renderer: &BaseElement{
+				Token: html.UnescapeString(s),
+				Style: ctx.options.Styles.Highlight,
+			},
+		}
+
+	// Comments are hidden
+	case ext.KindComment, ext.KindInlineComment:
+		return Element{}
+
 	case ast.KindThematicBreak:
 		return Element{
 			Entering: "",
@@ -370,6 +415,30 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 			},
 		}
 
+	// Math
+	case ext.KindMathBlock:
+		n := node.(*ext.MathBlock)
+		var s strings.Builder
+		for i := 0; i < n.Lines().Len(); i++ {
+			line := n.Lines().At(i)
+			s.Write(line.Value(source))
+		}
+		return Element{
+			Entering: "\n",
+			Renderer: &MathElement{
+				Formula: s.String(),
+				Display: true,
+			},
+		}
+
+	case ext.KindInlineMath:
+		n := node.(*ext.InlineMath)
+		return Element{
+			Renderer: &MathElement{
+				Formula: n.Formula,
+			},
+		}
+
 	// Tables
 	case astext.KindTable:
 		table := node.(*astext.Table)
@@ -430,30 +499,95 @@ func (tr *ANSIRenderer) NewElement(node ast.Node, source []byte) Element {
 
 	// Definition Lists
 	case astext.KindDefinitionList:
+		// The descriptions end their line
+		e := &BlockElement{
+			Block:  &bytes.Buffer{},
+			Style:  cascadeStyle(ctx.blockStack.Current().Style, ctx.options.Styles.DefinitionList, false),
+			Margin: true,
+		}
+		return Element{
+			Entering: "\n",
+			Renderer: e,
+			Finisher: e,
+		}
+
+	case astext.KindDefinitionTerm:
+		// The text of the term takes its style from the block
+		e := &BlockElement{
+			Block: &bytes.Buffer{},
+			Style: cascadeStyle(ctx.blockStack.Current().Style, StyleBlock{StylePrimitive: ctx.options.Styles.DefinitionTerm}, false),
+		}
+		entering := ""
+		if node.PreviousSibling() != nil {
+			entering = "\n"
+		}
+		return Element{
+			Entering: entering,
+			Exiting:  "\n",
+			Renderer: e,
+			Finisher: e,
+		}
+
+	case astext.KindDefinitionDescription:
+		// The paragraphs of the loose lists end their line, and are spaced
+		_, loose := node.LastChild().(*ast.Paragraph)
 		e := &BlockElement{
 			Block:   &bytes.Buffer{},
-			Style:   cascadeStyle(ctx.blockStack.Current().Style, ctx.options.Styles.DefinitionList, false),
+			Style:   cascadeStyle(ctx.blockStack.Current().Style, ctx.options.Styles.DefinitionDescription, false),
 			Margin:  true,
-			Newline: true,
+			Newline: !loose,
+		}
+		entering := ""
+		if loose && node.PreviousSibling() != nil && node.PreviousSibling().Kind() == astext.KindDefinitionDescription {
+			entering = "\n"
 		}
 		return Element{
+			Entering: entering,
 			Renderer: e,
 			Finisher: e,
 		}
 
-	case astext.KindDefinitionTerm:
+	// Footnotes
+	case astext.KindFootnoteLink:
+		n := node.(*astext.FootnoteLink)
+		return Element{
+			Renderer: &FootnoteLinkElement{
+				Index:    n.Index,
+				RefIndex: n.RefIndex,
+			},
+		}
+
+	case astext.KindFootnoteList:
+		e := &BlockElement{
+			Block:   &bytes.Buffer{},
+			Style:   cascadeStyle(ctx.blockStack.Current().Style, ctx.options.Styles.FootnoteList, false),
+			Margin:  true,
+			Newline: true,
+		}
 		return Element{
 			Entering: "\n",
-			Renderer: &BaseElement{
-				Style: ctx.options.Styles.DefinitionTerm,
+			Renderer: e,
+			Finisher: e,
+		}
+
+	case astext.KindFootnote:
+		post := "\n"
+		if node.NextSibling() == nil {
+			post = ""
+		}
+		return Element{
+			Exiting: post,
+			Renderer: &FootnoteElement{
+				Index: node.(*astext.Footnote).Index,
 			},
 		}
 
-	case astext.KindDefinitionDescription:
+	case astext.KindFootnoteBacklink:
+		n := node.(*astext.FootnoteBacklink)
 		return Element{
-			Exiting: "\n",
-			Renderer: &BaseElement{
-				Style: ctx.options.Styles.DefinitionDescription,
+			Renderer: &FootnoteBacklinkElement{
+				Index:    n.Index,
+				RefIndex: n.RefIndex,
 			},
 		}
 
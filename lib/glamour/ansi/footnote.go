@@ -0,0 +1,125 @@
+package ansi
+
+import (
+	"io"
+	"strconv"
+)
+
+// The definitions of the footnotes and their references are marked like the headings,
+// with their own sequence: `ESC [ 4243 ; <index> z`
+const footnoteMarkPrefix = "\x1b[4243;"
+
+// A footnoteAnchor is the definition of a footnote, or one of its references, at
+// the line of the output where it is
+type footnoteAnchor struct {
+	index      int
+	refIndex   int
+	definition bool
+	line       int
+}
+
+// Context to use in the rendering process to locate the footnotes of the document
+// and their references
+type FootnoteContext struct {
+	anchors []footnoteAnchor
+}
+
+func (ctx *FootnoteContext) resetASTWalkState() {
+	ctx.anchors = nil
+}
+
+// mark registers the definition of the footnote, or its refIndex reference, and returns its mark
+func (ctx *FootnoteContext) mark(index, refIndex int, definition bool) string {
+	ctx.anchors = append(ctx.anchors, footnoteAnchor{index: index, refIndex: refIndex, definition: definition, line: -1})
+	return footnoteMarkPrefix + strconv.Itoa(len(ctx.anchors)-1) + string(outlineMarkSuffix)
+}
+
+// Locate sets the line of the footnotes and references from their marks, and returns the output without them
+func (ctx *FootnoteContext) Locate(out []byte) []byte {
+	return locateMarks(out, footnoteMarkPrefix, func(idx, line int) {
+		if idx >= 0 && idx < len(ctx.anchors) && ctx.anchors[idx].line == -1 {
+			ctx.anchors[idx].line = line
+		}
+	})
+}
+
+// Definition returns the line of the definition of the footnote, -1 when it isn't rendered
+func (ctx *FootnoteContext) Definition(index int) int {
+	for _, anchor := range ctx.anchors {
+		if anchor.definition && anchor.index == index {
+			return anchor.line
+		}
+	}
+	return -1
+}
+
+// Reference returns the line of the refIndex reference to the footnote, -1 when it isn't rendered
+func (ctx *FootnoteContext) Reference(index, refIndex int) int {
+	for _, anchor := range ctx.anchors {
+		if !anchor.definition && anchor.index == index && anchor.refIndex == refIndex {
+			return anchor.line
+		}
+	}
+	return -1
+}
+
+// A FootnoteLinkElement is used to render the references to the footnotes.
+type FootnoteLinkElement struct {
+	Index    int
+	RefIndex int
+}
+
+// Render renders a FootnoteLinkElement.
+func (e *FootnoteLinkElement) Render(w io.Writer, ctx RenderContext) error {
+	isSelected := ctx.Selector.isSelected(&Selector{Footnote: e.Index, RefIndex: e.RefIndex})
+	_, _ = io.WriteString(w, ctx.Footnotes.mark(e.Index, e.RefIndex, false))
+	el := &BaseElement{
+		Token: strconv.Itoa(e.Index),
+		Style: footnoteStyle(ctx, ctx.options.Styles.FootnoteLink, isSelected),
+	}
+	return el.Render(w, ctx)
+}
+
+// A FootnoteElement is used to render the number of a footnote, before its definition.
+type FootnoteElement struct {
+	Index int
+}
+
+// Render renders a FootnoteElement.
+func (e *FootnoteElement) Render(w io.Writer, ctx RenderContext) error {
+	_, _ = io.WriteString(w, ctx.Footnotes.mark(e.Index, 0, true))
+	el := &BaseElement{
+		Token: strconv.Itoa(e.Index),
+		Style: ctx.options.Styles.Footnote,
+	}
+	return el.Render(w, ctx)
+}
+
+// A FootnoteBacklinkElement is used to render the links from the definition of a
+// footnote back to its references.
+type FootnoteBacklinkElement struct {
+	Index    int
+	RefIndex int
+}
+
+// Render renders a FootnoteBacklinkElement.
+func (e *FootnoteBacklinkElement) Render(w io.Writer, ctx RenderContext) error {
+	isSelected := ctx.Selector.isSelected(&Selector{Footnote: e.Index, RefIndex: e.RefIndex, Backlink: true})
+	el := &BaseElement{
+		Token: "↩",
+		Style: footnoteStyle(ctx, ctx.options.Styles.FootnoteBacklink, isSelected),
+	}
+	return el.Render(w, ctx)
+}
+
+// footnoteStyle returns the style of a footnote marker, in the colors of the selector when it is selected
+func footnoteStyle(ctx RenderContext, style StylePrimitive, isSelected bool) StylePrimitive {
+	if !isSelected {
+		return style
+	}
+	selected := ctx.options.Styles.Selector
+	selected.BlockPrefix, selected.BlockSuffix = style.BlockPrefix, style.BlockSuffix
+	selected.Prefix, selected.Suffix = style.Prefix, style.Suffix
+	selected.Format = style.Format
+	return selected
+}
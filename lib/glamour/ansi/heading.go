@@ -11,6 +11,7 @@ import (
 // A HeadingElement is used to render headings.
 type HeadingElement struct {
 	Level int
+	Text  string
 	First bool
 }
 
@@ -54,6 +55,8 @@ func (e *HeadingElement) Render(w io.Writer, ctx RenderContext) error {
 	bs.Push(be)
 
 	renderText(w, ctx.options.ColorProfile, bs.Parent().Style.StylePrimitive, rules.BlockPrefix)
+	// Invisible, the outline locates the heading in the output with it
+	_, _ = bs.Current().Block.WriteString(ctx.Outline.mark(e.Level, e.Text))
 	renderText(bs.Current().Block, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.Prefix)
 	return nil
 }
@@ -0,0 +1,761 @@
+package ansi
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// A mathBox is a formula laid out on lines, aligned with its neighbours on
+// its base line. The display formulas stack the fractions, the limits and the
+// matrices on several lines, the inline ones always fit on a line.
+type mathBox struct {
+	lines []string
+	base  int
+}
+
+func textBox(s string) mathBox {
+	return mathBox{lines: []string{s}}
+}
+
+func (b mathBox) width() int {
+	w := 0
+	for _, l := range b.lines {
+		w = max(w, ansi.StringWidth(l))
+	}
+	return w
+}
+
+// text returns the formula of a box on a line, its lines being joined when it has several.
+func (b mathBox) text() string {
+	return strings.Join(b.lines, " ")
+}
+
+func (b mathBox) isSpace() bool {
+	return len(b.lines) == 1 && b.lines[0] != "" && strings.TrimSpace(b.lines[0]) == ""
+}
+
+// pad returns the box with the width, aligned on the left (l), the center (c)
+// or the right (r).
+func (b mathBox) pad(width int, align byte) mathBox {
+	extra := width - b.width()
+	left := 0
+	switch align {
+	case 'c':
+		left = extra / 2
+	case 'r':
+		left = extra
+	}
+	lines := make([]string, len(b.lines))
+	for i, l := range b.lines {
+		lines[i] = strings.Repeat(" ", left) + l + strings.Repeat(" ", width-left-ansi.StringWidth(l))
+	}
+	return mathBox{lines: lines, base: b.base}
+}
+
+// hcat puts the boxes next to each other, on the same base line.
+func hcat(boxes ...mathBox) mathBox {
+	above, below := 0, 0
+	for _, b := range boxes {
+		above = max(above, b.base)
+		below = max(below, len(b.lines)-b.base-1)
+	}
+	lines := make([]string, above+below+1)
+	for _, b := range boxes {
+		w := b.width()
+		for i := range lines {
+			j := i - above + b.base
+			if j >= 0 && j < len(b.lines) {
+				lines[i] += b.lines[j] + strings.Repeat(" ", w-ansi.StringWidth(b.lines[j]))
+			} else {
+				lines[i] += strings.Repeat(" ", w)
+			}
+		}
+	}
+	return mathBox{lines: lines, base: above}
+}
+
+// vcat stacks the boxes, centered, the base line being the one of the box at index base.
+func vcat(boxes []mathBox, base int) mathBox {
+	w := 0
+	for _, b := range boxes {
+		w = max(w, b.width())
+	}
+	var out mathBox
+	for i, b := range boxes {
+		if i == base {
+			out.base = len(out.lines) + b.base
+		}
+		out.lines = append(out.lines, b.pad(w, 'c').lines...)
+	}
+	return out
+}
+
+// renderLatex lays out the LaTeX formula in Unicode, on several lines for the
+// display formulas. The constructs it doesn't know return an error.
+func renderLatex(formula string, display bool) ([]string, error) {
+	p := &latexParser{src: []rune(formula), display: display}
+	rows, err := p.parseRows()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("glamour: unexpected %q in formula", string(p.src[p.pos]))
+	}
+
+	box := rows[0][0]
+	if len(rows) > 1 || len(rows[0]) > 1 {
+		// Lines of equations, aligned on their & if any
+		box = p.layoutRows(rows, func(col int) byte {
+			if col%2 == 0 {
+				return 'r'
+			}
+			return 'l'
+		}, " ")
+	}
+	lines := make([]string, len(box.lines))
+	empty := true
+	for i, l := range box.lines {
+		lines[i] = strings.TrimRight(l, " ")
+		empty = empty && lines[i] == ""
+	}
+	// Nothing to show, e.g. $&$ or $_{}$, the formula is written as it is
+	if empty {
+		return nil, fmt.Errorf("glamour: empty formula")
+	}
+	return lines, nil
+}
+
+// latexParser reads a formula of LaTeX, in math mode.
+type latexParser struct {
+	src     []rune
+	pos     int
+	display bool
+}
+
+func (p *latexParser) peek() rune {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *latexParser) skipSpaces() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// readCommand reads the name of the command at \.
+func (p *latexParser) readCommand() string {
+	p.pos++
+	start := p.pos
+	for p.pos < len(p.src) && unicode.IsLetter(p.src[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.pos < len(p.src) {
+			p.pos++
+		}
+		return string(p.src[start:p.pos])
+	}
+	return string(p.src[start:p.pos])
+}
+
+// peekCommand returns the name of the command at \, without reading it.
+func (p *latexParser) peekCommand() string {
+	pos := p.pos
+	name := p.readCommand()
+	p.pos = pos
+	return name
+}
+
+// expect reads the character, or returns an error.
+func (p *latexParser) expect(c rune) error {
+	p.skipSpaces()
+	if p.peek() != c {
+		return fmt.Errorf("glamour: missing %q in formula", c)
+	}
+	p.pos++
+	return nil
+}
+
+// readText reads the text between braces, as written.
+func (p *latexParser) readText() (string, error) {
+	if err := p.expect('{'); err != nil {
+		return "", err
+	}
+	start := p.pos
+	for depth := 1; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return string(p.src[start : p.pos-1]), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("glamour: missing %q in formula", '}')
+}
+
+// atEnd tells if the sequence being read ends here: a closing brace, the
+// next cell or row, the end of an environment or of a \left.
+func (p *latexParser) atEnd(bracket bool) bool {
+	switch c := p.peek(); {
+	case c == 0, c == '}', c == '&', bracket && c == ']':
+		return true
+	case c == '\\':
+		cmd := p.peekCommand()
+		return cmd == "\\" || cmd == "end" || cmd == "right"
+	}
+	return false
+}
+
+// parseSeq reads the atoms up to the end of the sequence. The spaces between
+// them are kept, as a space.
+func (p *latexParser) parseSeq(bracket bool) (mathBox, error) {
+	var atoms []mathBox
+	space := false
+	for !p.atEnd(bracket) {
+		if unicode.IsSpace(p.peek()) {
+			p.pos++
+			space = len(atoms) > 0
+			continue
+		}
+		atom, err := p.parseAtom()
+		if err != nil {
+			return mathBox{}, err
+		}
+		if space && !atom.isSpace() && !atoms[len(atoms)-1].isSpace() {
+			atoms = append(atoms, textBox(" "))
+		}
+		space = false
+		atoms = append(atoms, atom)
+	}
+	if len(atoms) == 0 {
+		return textBox(""), nil
+	}
+	return hcat(atoms...), nil
+}
+
+// parseRows reads the cells separated by & and the rows separated by \\.
+func (p *latexParser) parseRows() ([][]mathBox, error) {
+	rows := [][]mathBox{{}}
+	for {
+		cell, err := p.parseSeq(false)
+		if err != nil {
+			return nil, err
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], cell)
+		switch {
+		case p.peek() == '&':
+			p.pos++
+		case p.peek() == '\\' && p.peekCommand() == "\\":
+			p.readCommand()
+			rows = append(rows, []mathBox{})
+		default:
+			// The \\ ending the last row is optional
+			if last := rows[len(rows)-1]; len(rows) > 1 && len(last) == 1 && last[0].width() == 0 {
+				rows = rows[:len(rows)-1]
+			}
+			return rows, nil
+		}
+	}
+}
+
+// layoutRows lays the cells out in columns, aligned by align and separated by sep.
+// The inline formulas are written on a line, the rows being separated by a semicolon.
+func (p *latexParser) layoutRows(rows [][]mathBox, align func(col int) byte, sep string) mathBox {
+	if !p.display {
+		texts := make([]string, len(rows))
+		for i, row := range rows {
+			cells := make([]string, len(row))
+			for j, cell := range row {
+				cells[j] = cell.text()
+			}
+			texts[i] = strings.Join(cells, " ")
+		}
+		return textBox(strings.Join(texts, "; "))
+	}
+
+	var widths []int
+	for _, row := range rows {
+		for j, cell := range row {
+			if j >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], cell.width())
+		}
+	}
+	lines := make([]mathBox, len(rows))
+	for i, row := range rows {
+		var cells []mathBox
+		for j := range widths {
+			if j > 0 {
+				cells = append(cells, textBox(sep))
+			}
+			cell := textBox("")
+			if j < len(row) {
+				cell = row[j]
+			}
+			cells = append(cells, cell.pad(widths[j], align(j)))
+		}
+		lines[i] = hcat(cells...)
+	}
+	box := vcat(lines, 0)
+	box.base = (len(box.lines) - 1) / 2
+	return box
+}
+
+// parseAtom reads an atom and its scripts.
+func (p *latexParser) parseAtom() (mathBox, error) {
+	nucleus, limits, err := p.parseNucleus()
+	if err != nil {
+		return mathBox{}, err
+	}
+	var sup, sub *mathBox
+	for {
+		pos := p.pos
+		p.skipSpaces()
+		if p.peek() == '\\' {
+			switch p.peekCommand() {
+			case "limits":
+				p.readCommand()
+				limits = true
+				continue
+			case "nolimits":
+				p.readCommand()
+				limits = false
+				continue
+			}
+		}
+		c := p.peek()
+		if c != '^' && c != '_' {
+			p.pos = pos
+			break
+		}
+		p.pos++
+		script, err := p.parseScript()
+		if err != nil {
+			return mathBox{}, err
+		}
+		if c == '^' {
+			if sup != nil {
+				return mathBox{}, fmt.Errorf("glamour: double superscript in formula")
+			}
+			sup = &script
+		} else {
+			if sub != nil {
+				return mathBox{}, fmt.Errorf("glamour: double subscript in formula")
+			}
+			sub = &script
+		}
+	}
+
+	switch {
+	case sup == nil && sub == nil:
+		return nucleus, nil
+	case limits && p.display:
+		// Above and below the operator
+		boxes := []mathBox{}
+		if sup != nil {
+			boxes = append(boxes, *sup)
+		}
+		boxes = append(boxes, nucleus)
+		if sub != nil {
+			boxes = append(boxes, *sub)
+		}
+		base := 0
+		if sup != nil {
+			base = 1
+		}
+		return vcat(boxes, base), nil
+	}
+
+	var supText, subText string
+	if sup != nil {
+		supText = scriptText(sup.text(), superscripts, "^")
+	}
+	if sub != nil {
+		subText = scriptText(sub.text(), subscripts, "_")
+	}
+	if len(nucleus.lines) == 1 {
+		return hcat(nucleus, textBox(subText+supText)), nil
+	}
+	// Next to the top and the bottom of a tall atom
+	scripts := make([]string, len(nucleus.lines))
+	scripts[0] = supText
+	scripts[len(scripts)-1] = subText
+	return hcat(nucleus, mathBox{lines: scripts, base: nucleus.base}), nil
+}
+
+// parseScript reads a script, always laid out on a line.
+func (p *latexParser) parseScript() (mathBox, error) {
+	display := p.display
+	p.display = false
+	defer func() { p.display = display }()
+	return p.parseArg()
+}
+
+// parseArg reads the argument of a command: a group or a single atom, without its scripts.
+func (p *latexParser) parseArg() (mathBox, error) {
+	p.skipSpaces()
+	if p.peek() == 0 {
+		return mathBox{}, fmt.Errorf("glamour: missing argument in formula")
+	}
+	box, _, err := p.parseNucleus()
+	return box, err
+}
+
+// parseNucleus reads an atom, without its scripts. Limits tells if its scripts go above and below it.
+func (p *latexParser) parseNucleus() (box mathBox, limits bool, err error) {
+	c := p.peek()
+	switch c {
+	case '{':
+		p.pos++
+		box, err = p.parseSeq(false)
+		if err == nil {
+			err = p.expect('}')
+		}
+		return box, false, err
+	case '\\':
+		return p.parseCommand()
+	case '^', '_':
+		// A script of nothing, as {}^2
+		return textBox(""), false, nil
+	case '\'':
+		n := 0
+		for p.peek() == '\'' {
+			p.pos++
+			n++
+		}
+		return textBox(strings.Repeat("′", n)), false, nil
+	case '#', '%':
+		return mathBox{}, false, fmt.Errorf("glamour: unexpected %q in formula", c)
+	}
+	p.pos++
+	if s, ok := latexCharacters[c]; ok {
+		return textBox(s), false, nil
+	}
+	return textBox(string(c)), false, nil
+}
+
+// parseCommand reads a command and its arguments.
+func (p *latexParser) parseCommand() (mathBox, bool, error) {
+	name := p.readCommand()
+	if s, ok := latexSymbols[name]; ok {
+		return textBox(s), false, nil
+	}
+	if s, ok := latexOperators[name]; ok {
+		return textBox(s.text), s.limits, nil
+	}
+
+	switch name {
+	case "frac", "dfrac", "tfrac", "cfrac":
+		num, err := p.parseArg()
+		if err != nil {
+			return mathBox{}, false, err
+		}
+		den, err := p.parseArg()
+		if err != nil {
+			return mathBox{}, false, err
+		}
+		return p.fraction(num, den), false, nil
+
+	case "sqrt":
+		index := ""
+		if p.peek() == '[' {
+			p.pos++
+			box, err := p.parseSeq(true)
+			if err != nil {
+				return mathBox{}, false, err
+			}
+			if err := p.expect(']'); err != nil {
+				return mathBox{}, false, err
+			}
+			index = scriptText(box.text(), superscripts, "")
+		}
+		arg, err := p.parseArg()
+		if err != nil {
+			return mathBox{}, false, err
+		}
+		if len(arg.lines) > 1 {
+			return hcat(textBox(index+"√"), p.fence("(", arg, ")")), false, nil
+		}
+		return textBox(index + "√" + parenthesize(arg.text())), false, nil
+
+	case "text", "textrm", "textit", "textbf", "textsf", "texttt", "textnormal", "mbox":
+		s, err := p.readText()
+		return textBox(s), false, err
+
+	case "mathrm", "mathit", "mathbf", "mathsf", "mathtt", "mathnormal", "boldsymbol", "operatorname":
+		arg, err := p.parseArg()
+		return arg, false, err
+
+	case "mathbb", "mathcal", "mathscr":
+		arg, err := p.parseArg()
+		if err != nil || len(arg.lines) > 1 {
+			return arg, false, err
+		}
+		return textBox(mathAlphabet(arg.text(), name == "mathbb")), false, nil
+
+	case "hat", "widehat", "bar", "overline", "vec", "dot", "ddot", "tilde", "widetilde", "check", "breve", "acute", "grave", "underline":
+		arg, err := p.parseArg()
+		if err != nil {
+			return mathBox{}, false, err
+		}
+		if len(arg.lines) > 1 {
+			return mathBox{}, false, fmt.Errorf("glamour: unsupported \\%s in formula", name)
+		}
+		var b strings.Builder
+		for _, r := range arg.text() {
+			b.WriteRune(r)
+			b.WriteRune(latexAccents[name])
+		}
+		return textBox(b.String()), false, nil
+
+	case "not":
+		arg, err := p.parseArg()
+		if err != nil {
+			return mathBox{}, false, err
+		}
+		switch s := arg.text(); s {
+		case "=":
+			return textBox("≠"), false, nil
+		case "∈":
+			return textBox("∉"), false, nil
+		default:
+			return textBox(s + "̸"), false, nil
+		}
+
+	case "left":
+		left, err := p.parseDelimiter()
+		if err != nil {
+			return mathBox{}, false, err
+		}
+		inner, err := p.parseSeq(false)
+		if err != nil {
+			return mathBox{}, false, err
+		}
+		if p.peek() != '\\' || p.readCommand() != "right" {
+			return mathBox{}, false, fmt.Errorf("glamour: missing \\right in formula")
+		}
+		right, err := p.parseDelimiter()
+		if err != nil {
+			return mathBox{}, false, err
+		}
+		return p.fence(left, inner, right), false, nil
+
+	case "big", "Big", "bigg", "Bigg", "bigl", "Bigl", "biggl", "Biggl", "bigr", "Bigr", "biggr", "Biggr", "bigm", "Bigm":
+		d, err := p.parseDelimiter()
+		return textBox(d), false, err
+
+	case "begin":
+		env, err := p.readText()
+		if err != nil {
+			return mathBox{}, false, err
+		}
+		box, err := p.parseEnvironment(env)
+		return box, false, err
+
+	case "label", "tag":
+		_, err := p.readText()
+		return textBox(""), false, err
+
+	case "displaystyle", "textstyle", "scriptstyle", "nonumber", "notag", "limits", "nolimits":
+		return textBox(""), false, nil
+	}
+	return mathBox{}, false, fmt.Errorf("glamour: unsupported \\%s in formula", name)
+}
+
+// parseDelimiter reads the delimiter after \left, \right or \big, "." being none.
+func (p *latexParser) parseDelimiter() (string, error) {
+	p.skipSpaces()
+	switch c := p.peek(); c {
+	case 0:
+		return "", fmt.Errorf("glamour: missing delimiter in formula")
+	case '.':
+		p.pos++
+		return "", nil
+	case '\\':
+		name := p.readCommand()
+		if s, ok := latexSymbols[name]; ok {
+			return s, nil
+		}
+		return "", fmt.Errorf("glamour: unsupported delimiter \\%s in formula", name)
+	default:
+		p.pos++
+		return string(c), nil
+	}
+}
+
+// parseEnvironment reads the cells of the environment, up to its \end.
+func (p *latexParser) parseEnvironment(env string) (mathBox, error) {
+	var left, right, sep string
+	align := func(int) byte { return 'c' }
+	switch env {
+	case "matrix", "smallmatrix", "array":
+		sep = "  "
+	case "pmatrix":
+		left, right, sep = "(", ")", "  "
+	case "bmatrix":
+		left, right, sep = "[", "]", "  "
+	case "Bmatrix":
+		left, right, sep = "{", "}", "  "
+	case "vmatrix":
+		left, right, sep = "|", "|", "  "
+	case "Vmatrix":
+		left, right, sep = "‖", "‖", "  "
+	case "cases":
+		left, sep = "{", "  "
+		align = func(int) byte { return 'l' }
+	case "aligned", "align", "align*", "split", "alignedat":
+		sep = " "
+		align = func(col int) byte {
+			if col%2 == 0 {
+				return 'r'
+			}
+			return 'l'
+		}
+	case "gathered", "gather", "gather*", "equation", "equation*":
+		sep = " "
+	default:
+		return mathBox{}, fmt.Errorf("glamour: unsupported environment %s in formula", env)
+	}
+	if env == "array" || env == "alignedat" {
+		// The columns or their number, the cells being laid out the same way
+		if _, err := p.readText(); err != nil {
+			return mathBox{}, err
+		}
+	}
+
+	rows, err := p.parseRows()
+	if err != nil {
+		return mathBox{}, err
+	}
+	if p.peek() != '\\' || p.readCommand() != "end" {
+		return mathBox{}, fmt.Errorf("glamour: missing \\end{%s} in formula", env)
+	}
+	if end, err := p.readText(); err != nil || end != env {
+		return mathBox{}, fmt.Errorf("glamour: missing \\end{%s} in formula", env)
+	}
+	return p.fence(left, p.layoutRows(rows, align, sep), right), nil
+}
+
+// fraction stacks the numerator over the denominator in the display formulas, else writes num/den.
+func (p *latexParser) fraction(num, den mathBox) mathBox {
+	if p.display {
+		w := max(num.width(), den.width())
+		return vcat([]mathBox{num, textBox(strings.Repeat("─", w)), den}, 1)
+	}
+	n, d := num.text(), den.text()
+	if s, ok := vulgarFractions[n+"/"+d]; ok {
+		return textBox(s)
+	}
+	return textBox(parenthesize(n) + "/" + parenthesize(d))
+}
+
+// fence puts the delimiters around the box, as tall as it.
+func (p *latexParser) fence(left string, inner mathBox, right string) mathBox {
+	if len(inner.lines) == 1 {
+		return textBox(left + inner.lines[0] + right)
+	}
+	return hcat(delimiter(left, inner), inner, delimiter(right, inner))
+}
+
+// delimiter returns the delimiter as tall as the box.
+func delimiter(d string, inner mathBox) mathBox {
+	h := len(inner.lines)
+	lines := make([]string, h)
+	pieces, ok := tallDelimiters[d]
+	for i := range lines {
+		switch {
+		case !ok:
+			if i == inner.base {
+				lines[i] = d
+			}
+		case i == 0:
+			lines[i] = pieces[0]
+		case i == h-1:
+			lines[i] = pieces[2]
+		case (d == "{" || d == "}") && i == h/2:
+			// The middle of a brace
+			lines[i] = map[string]string{"{": "⎨", "}": "⎬"}[d]
+		default:
+			lines[i] = pieces[1]
+		}
+	}
+	return mathBox{lines: lines, base: inner.base}
+}
+
+// parenthesize puts the text in parentheses when it is more than a number or a name.
+func parenthesize(s string) string {
+	if utf8.RuneCountInString(s) <= 1 {
+		return s
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' {
+			return "(" + s + ")"
+		}
+	}
+	return s
+}
+
+// scriptText writes the text in the superscripts or subscripts, or after the
+// mark when one of its characters has none. The scripts in the text are kept.
+func scriptText(s string, scripts map[rune]rune, mark string) string {
+	var b strings.Builder
+	for _, r := range s {
+		c, ok := scripts[r]
+		if !ok && (isScript(r, superscripts) || isScript(r, subscripts)) {
+			c, ok = r, true
+		}
+		if !ok {
+			if mark == "" {
+				return s
+			}
+			return mark + parenthesize(s)
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func isScript(r rune, scripts map[rune]rune) bool {
+	for _, c := range scripts {
+		if c == r && c != '*' && c != '′' {
+			return true
+		}
+	}
+	return false
+}
+
+// mathAlphabet writes the letters in the double-struck alphabet, or the script one.
+func mathAlphabet(s string, doubleStruck bool) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case doubleStruck && strings.ContainsRune("CHNPQRZ", r):
+			b.WriteRune(map[rune]rune{'C': 'ℂ', 'H': 'ℍ', 'N': 'ℕ', 'P': 'ℙ', 'Q': 'ℚ', 'R': 'ℝ', 'Z': 'ℤ'}[r])
+		case doubleStruck && r >= 'A' && r <= 'Z':
+			b.WriteRune(0x1D538 + r - 'A')
+		case doubleStruck && r >= '0' && r <= '9':
+			b.WriteRune(0x1D7D8 + r - '0')
+		case !doubleStruck && strings.ContainsRune("BEFHILMR", r):
+			b.WriteRune(map[rune]rune{'B': 'ℬ', 'E': 'ℰ', 'F': 'ℱ', 'H': 'ℋ', 'I': 'ℐ', 'L': 'ℒ', 'M': 'ℳ', 'R': 'ℛ'}[r])
+		case !doubleStruck && r >= 'A' && r <= 'Z':
+			b.WriteRune(0x1D49C + r - 'A')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package ansi
+
+import (
+	"strings"
+	"testing"
+
+	xansi "github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderLatex(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula string
+		display bool
+		lines   []string
+	}{
+		{"inline fraction", `\frac{a}{b}`, false, []string{"a/b"}},
+		{"display fraction", `\frac{a+1}{b}`, true, []string{"a+1", "───", " b"}},
+		{"superscript", `x^2`, false, []string{"x²"}},
+		{"subscript", `x_i`, false, []string{"xᵢ"}},
+		{"both scripts", `x_{ij}^{2n}`, false, []string{"xᵢⱼ²ⁿ"}},
+		{"script without a Unicode letter", `x^{q}`, false, []string{"x^q"}},
+		{"symbols", `\alpha + \beta`, false, []string{"α + β"}},
+		{"square root", `\sqrt{x}`, false, []string{"√x"}},
+		{"inline sum", `\sum_{i=1}^{n} i`, false, []string{"∑ᵢ₌₁ⁿ i"}},
+		{"sum with limits", `\sum_{i=1}^{n} i`, true, []string{" n", " ∑  i", "i=1"}},
+		{"inline matrix", `\begin{pmatrix} a & b \\ c & d \end{pmatrix}`, false, []string{"(a b; c d)"}},
+		{"display matrix", `\begin{pmatrix} a & b \\ c & d \end{pmatrix}`, true, []string{"⎛a  b⎞", "⎝c  d⎠"}},
+		{"aligned equations", `a &= b \\ c &= d`, true, []string{"a = b", "c = d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := renderLatex(tt.formula, tt.display)
+			if err != nil {
+				t.Fatalf("renderLatex(%q): %v", tt.formula, err)
+			}
+			if strings.Join(lines, "\n") != strings.Join(tt.lines, "\n") {
+				t.Errorf("renderLatex(%q) = %q, expected %q", tt.formula, lines, tt.lines)
+			}
+		})
+	}
+}
+
+func TestRenderLatexErrors(t *testing.T) {
+	for _, formula := range []string{`\frac{`, `&`, `_{}`, `x^2^3`, `x_1_2`, `\unknown`, `\begin{foo}x\end{foo}`, `a}`, `#`} {
+		if lines, err := renderLatex(formula, false); err == nil {
+			t.Errorf("renderLatex(%q) = %q, expected an error", formula, lines)
+		}
+	}
+}
+
+// The formulas which can't be laid out are written as they are
+func TestMathRawFallback(t *testing.T) {
+	for _, in := range []string{`$\frac{$`, `$&$`, `$_{}$`} {
+		out := xansi.Strip(renderExtended(t, "Before "+in+" after\n"))
+		if !strings.Contains(out, "Before "+in+" after") {
+			t.Errorf("%s is not written as it is: %q", in, out)
+		}
+	}
+	out := xansi.Strip(renderExtended(t, "$$\n\\frac{\n$$\n"))
+	if !strings.Contains(out, `\frac{`) {
+		t.Errorf("the display formula is not written as it is: %q", out)
+	}
+}
@@ -0,0 +1,134 @@
+package ansi
+
+// latexCharacters are the characters written differently in the formulas.
+var latexCharacters = map[rune]string{
+	'-': "−",
+	'*': "∗",
+	'~': " ",
+}
+
+// latexSymbols are the commands written as a symbol.
+var latexSymbols = map[string]string{
+	// Greek letters
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ", "varepsilon": "ε",
+	"zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ", "iota": "ι", "kappa": "κ",
+	"lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "omicron": "ο", "pi": "π", "varpi": "ϖ",
+	"rho": "ρ", "varrho": "ϱ", "sigma": "σ", "varsigma": "ς", "tau": "τ", "upsilon": "υ",
+	"phi": "ϕ", "varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+
+	// Binary operators
+	"pm": "±", "mp": "∓", "times": "×", "div": "÷", "cdot": "·", "ast": "∗", "star": "⋆",
+	"circ": "∘", "bullet": "∙", "oplus": "⊕", "ominus": "⊖", "otimes": "⊗", "odot": "⊙",
+	"cup": "∪", "cap": "∩", "setminus": "∖", "wedge": "∧", "land": "∧", "vee": "∨",
+	"lor": "∨", "neg": "¬", "lnot": "¬",
+
+	// Relations
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠", "approx": "≈",
+	"equiv": "≡", "sim": "∼", "simeq": "≃", "cong": "≅", "propto": "∝", "ll": "≪", "gg": "≫",
+	"subset": "⊂", "supset": "⊃", "subseteq": "⊆", "supseteq": "⊇", "in": "∈", "notin": "∉",
+	"ni": "∋", "perp": "⊥", "parallel": "∥", "mid": "∣", "coloneqq": "≔", "prec": "≺",
+	"succ": "≻", "models": "⊨", "vdash": "⊢",
+
+	// Arrows
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "gets": "←", "leftrightarrow": "↔",
+	"Rightarrow": "⇒", "Leftarrow": "⇐", "Leftrightarrow": "⇔", "implies": "⟹",
+	"impliedby": "⟸", "iff": "⟺", "mapsto": "↦", "longrightarrow": "⟶",
+	"longleftarrow": "⟵", "longmapsto": "⟼", "uparrow": "↑", "downarrow": "↓",
+	"Uparrow": "⇑", "Downarrow": "⇓", "nearrow": "↗", "searrow": "↘",
+	"hookrightarrow": "↪", "rightleftharpoons": "⇌",
+
+	// Delimiters
+	"langle": "⟨", "rangle": "⟩", "lfloor": "⌊", "rfloor": "⌋", "lceil": "⌈", "rceil": "⌉",
+	"lvert": "|", "rvert": "|", "vert": "|", "Vert": "‖", "lVert": "‖", "rVert": "‖",
+	"|": "‖", "{": "{", "}": "}",
+
+	// Others
+	"infty": "∞", "partial": "∂", "nabla": "∇", "forall": "∀", "exists": "∃", "nexists": "∄",
+	"emptyset": "∅", "varnothing": "∅", "ldots": "…", "dots": "…", "cdots": "⋯", "vdots": "⋮",
+	"ddots": "⋱", "prime": "′", "angle": "∠", "triangle": "△", "hbar": "ℏ", "ell": "ℓ",
+	"Re": "ℜ", "Im": "ℑ", "aleph": "ℵ", "wp": "℘", "top": "⊤", "bot": "⊥", "degree": "°",
+	"checkmark": "✓", "dagger": "†", "backslash": "\\",
+	"%": "%", "$": "$", "&": "&", "#": "#", "_": "_",
+
+	// Spaces
+	",": " ", ":": " ", ";": " ", ">": " ", " ": " ", "!": "", "quad": "  ", "qquad": "    ",
+}
+
+// A latexOperator is written as text, its scripts going above and below it
+// in the display formulas when it has limits.
+type latexOperator struct {
+	text   string
+	limits bool
+}
+
+// latexOperators are the big operators and the functions.
+var latexOperators = map[string]latexOperator{
+	"sum": {"∑", true}, "prod": {"∏", true}, "coprod": {"∐", true}, "bigcup": {"⋃", true},
+	"bigcap": {"⋂", true}, "bigoplus": {"⨁", true}, "bigotimes": {"⨂", true},
+	"bigvee": {"⋁", true}, "bigwedge": {"⋀", true},
+	"int": {"∫", false}, "iint": {"∬", false}, "iiint": {"∭", false}, "oint": {"∮", false},
+
+	"lim": {"lim", true}, "liminf": {"lim inf", true}, "limsup": {"lim sup", true},
+	"max": {"max", true}, "min": {"min", true}, "sup": {"sup", true}, "inf": {"inf", true},
+	"det": {"det", true}, "gcd": {"gcd", true}, "Pr": {"Pr", true},
+	"sin": {"sin", false}, "cos": {"cos", false}, "tan": {"tan", false}, "cot": {"cot", false},
+	"sec": {"sec", false}, "csc": {"csc", false}, "arcsin": {"arcsin", false},
+	"arccos": {"arccos", false}, "arctan": {"arctan", false}, "sinh": {"sinh", false},
+	"cosh": {"cosh", false}, "tanh": {"tanh", false}, "log": {"log", false}, "ln": {"ln", false},
+	"lg": {"lg", false}, "exp": {"exp", false}, "dim": {"dim", false}, "ker": {"ker", false},
+	"arg": {"arg", false}, "deg": {"deg", false}, "hom": {"hom", false},
+}
+
+// latexAccents are the combining characters of the accents.
+var latexAccents = map[string]rune{
+	"hat": '̂', "widehat": '̂', "bar": '̅', "overline": '̅',
+	"vec": '⃗', "dot": '̇', "ddot": '̈', "tilde": '̃',
+	"widetilde": '̃', "check": '̌', "breve": '̆', "acute": '́',
+	"grave": '̀', "underline": '̲',
+}
+
+// tallDelimiters are the top, middle and bottom pieces of the delimiters on several lines.
+var tallDelimiters = map[string][3]string{
+	"(": {"⎛", "⎜", "⎝"},
+	")": {"⎞", "⎟", "⎠"},
+	"[": {"⎡", "⎢", "⎣"},
+	"]": {"⎤", "⎥", "⎦"},
+	"{": {"⎧", "⎪", "⎩"},
+	"}": {"⎫", "⎪", "⎭"},
+	"|": {"│", "│", "│"},
+	"‖": {"‖", "‖", "‖"},
+	"⌊": {"⎢", "⎢", "⎣"},
+	"⌋": {"⎥", "⎥", "⎦"},
+	"⌈": {"⎡", "⎢", "⎢"},
+	"⌉": {"⎤", "⎥", "⎥"},
+}
+
+// vulgarFractions are the fractions with a character of their own.
+var vulgarFractions = map[string]string{
+	"1/2": "½", "1/3": "⅓", "2/3": "⅔", "1/4": "¼", "3/4": "¾", "1/5": "⅕", "2/5": "⅖",
+	"3/5": "⅗", "4/5": "⅘", "1/6": "⅙", "5/6": "⅚", "1/8": "⅛", "3/8": "⅜", "5/8": "⅝",
+	"7/8": "⅞",
+}
+
+var superscripts = map[rune]rune{
+	'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸',
+	'9': '⁹', '+': '⁺', '-': '⁻', '−': '⁻', '=': '⁼', '(': '⁽', ')': '⁾',
+	'a': 'ᵃ', 'b': 'ᵇ', 'c': 'ᶜ', 'd': 'ᵈ', 'e': 'ᵉ', 'f': 'ᶠ', 'g': 'ᵍ', 'h': 'ʰ', 'i': 'ⁱ',
+	'j': 'ʲ', 'k': 'ᵏ', 'l': 'ˡ', 'm': 'ᵐ', 'n': 'ⁿ', 'o': 'ᵒ', 'p': 'ᵖ', 'r': 'ʳ', 's': 'ˢ',
+	't': 'ᵗ', 'u': 'ᵘ', 'v': 'ᵛ', 'w': 'ʷ', 'x': 'ˣ', 'y': 'ʸ', 'z': 'ᶻ',
+	'A': 'ᴬ', 'B': 'ᴮ', 'D': 'ᴰ', 'E': 'ᴱ', 'G': 'ᴳ', 'H': 'ᴴ', 'I': 'ᴵ', 'J': 'ᴶ', 'K': 'ᴷ',
+	'L': 'ᴸ', 'M': 'ᴹ', 'N': 'ᴺ', 'O': 'ᴼ', 'P': 'ᴾ', 'R': 'ᴿ', 'T': 'ᵀ', 'U': 'ᵁ', 'V': 'ⱽ',
+	'W': 'ᵂ',
+	'α': 'ᵅ', 'β': 'ᵝ', 'γ': 'ᵞ', 'δ': 'ᵟ', 'θ': 'ᶿ', 'φ': 'ᵠ', 'ϕ': 'ᵠ', 'χ': 'ᵡ',
+	'′': '′', '∗': '*', '*': '*', '∘': '°',
+}
+
+var subscripts = map[rune]rune{
+	'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆', '7': '₇', '8': '₈',
+	'9': '₉', '+': '₊', '-': '₋', '−': '₋', '=': '₌', '(': '₍', ')': '₎',
+	'a': 'ₐ', 'e': 'ₑ', 'h': 'ₕ', 'i': 'ᵢ', 'j': 'ⱼ', 'k': 'ₖ', 'l': 'ₗ', 'm': 'ₘ', 'n': 'ₙ',
+	'o': 'ₒ', 'p': 'ₚ', 'r': 'ᵣ', 's': 'ₛ', 't': 'ₜ', 'u': 'ᵤ', 'v': 'ᵥ', 'x': 'ₓ',
+	'β': 'ᵦ', 'γ': 'ᵧ', 'ρ': 'ᵨ', 'φ': 'ᵩ', 'ϕ': 'ᵩ', 'χ': 'ᵪ',
+}
@@ -0,0 +1,58 @@
+package ansi
+
+import (
+	"io"
+	"strings"
+
+	"github.com/muesli/reflow/indent"
+)
+
+// A MathElement is used to render the formulas of LaTeX, in Unicode. The
+// formulas it can't lay out are written as they are, in the raw style.
+type MathElement struct {
+	Formula string
+	Display bool
+}
+
+// Render renders a MathElement.
+func (e *MathElement) Render(w io.Writer, ctx RenderContext) error {
+	bs := ctx.blockStack
+	styles := ctx.options.Styles
+	lines, err := renderLatex(e.Formula, e.Display)
+
+	if !e.Display {
+		style := cascadeStylePrimitive(bs.Current().Style.StylePrimitive, styles.MathInline, false)
+		text := strings.Join(lines, " ")
+		if err != nil {
+			style = cascadeStylePrimitive(bs.Current().Style.StylePrimitive, styles.MathRaw, false)
+			text = "$" + e.Formula + "$"
+		}
+		renderText(w, ctx.options.ColorProfile, style, style.Prefix+text+style.Suffix)
+		return nil
+	}
+
+	rules := styles.Math
+	style := cascadeStylePrimitive(bs.Current().Style.StylePrimitive, rules.StylePrimitive, false)
+	if err != nil {
+		style = cascadeStylePrimitive(bs.Current().Style.StylePrimitive, styles.MathRaw, false)
+		lines = strings.Split(strings.TrimSpace(e.Formula), "\n")
+	}
+	var indentation uint
+	if rules.Indent != nil {
+		indentation = *rules.Indent
+	}
+	if rules.Margin != nil {
+		indentation += *rules.Margin
+	}
+	iw := indent.NewWriterPipe(w, indentation, func(_ io.Writer) {
+		renderText(w, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, " ")
+	})
+
+	renderText(iw, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockPrefix)
+	for _, line := range lines {
+		renderText(iw, ctx.options.ColorProfile, style, style.Prefix+line+style.Suffix)
+		_, _ = io.WriteString(iw, "\n")
+	}
+	renderText(iw, ctx.options.ColorProfile, bs.Current().Style.StylePrimitive, rules.BlockSuffix)
+	return nil
+}
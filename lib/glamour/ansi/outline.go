@@ -0,0 +1,113 @@
+package ansi
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// A Heading of the rendered document, at the Line of the output where it starts
+type Heading struct {
+	Level int
+	Text  string
+	Line  int
+}
+
+// The headings are marked with an invisible escape sequence while walking the AST,
+// the marks are replaced by the line of the heading once the document is rendered
+// and wrapped: `ESC [ 4242 ; <index> z`
+const (
+	outlineMarkPrefix = "\x1b[4242;"
+	outlineMarkSuffix = 'z'
+)
+
+// Context to use in the rendering process to list the headings of the document
+type OutlineContext struct {
+	headings []Heading
+}
+
+func (ctx *OutlineContext) resetASTWalkState() {
+	ctx.headings = nil
+}
+
+// Headings returns the headings of the last rendered document
+func (ctx *OutlineContext) Headings() []Heading {
+	return ctx.headings
+}
+
+// mark registers the heading and returns its mark
+func (ctx *OutlineContext) mark(level int, text string) string {
+	ctx.headings = append(ctx.headings, Heading{Level: level, Text: text, Line: -1})
+	return outlineMarkPrefix + strconv.Itoa(len(ctx.headings)-1) + string(outlineMarkSuffix)
+}
+
+// Locate sets the line of the headings from their marks, and returns the output without them
+func (ctx *OutlineContext) Locate(out []byte) []byte {
+	return locateMarks(out, outlineMarkPrefix, func(idx, line int) {
+		// The writers may repeat the mark on the following lines, the first one is kept
+		if idx >= 0 && idx < len(ctx.headings) && ctx.headings[idx].Line == -1 {
+			ctx.headings[idx].Line = line
+		}
+	})
+}
+
+// locateMarks removes the marks `<prefix><index>z` from the output, calling locate with
+// the index and the line of each of them
+func locateMarks(out []byte, prefix string, locate func(idx, line int)) []byte {
+	if !bytes.Contains(out, []byte(prefix)) {
+		return out
+	}
+	located := make([]byte, 0, len(out))
+	line := 0
+	for len(out) > 0 {
+		i := bytes.Index(out, []byte(prefix))
+		if i == -1 {
+			located = append(located, out...)
+			break
+		}
+		line += bytes.Count(out[:i], []byte("\n"))
+		located = append(located, out[:i]...)
+		out = out[i+len(prefix):]
+
+		end := bytes.IndexByte(out, outlineMarkSuffix)
+		if end == -1 {
+			break
+		}
+		idx, err := strconv.Atoi(string(out[:end]))
+		out = out[end+1:]
+		if err == nil {
+			locate(idx, line)
+		}
+	}
+	return located
+}
+
+// headingText returns the text of the heading, without its formatting
+func headingText(node ast.Node, source []byte) string {
+	var text strings.Builder
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Text:
+			text.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() {
+				text.WriteByte(' ')
+			}
+		case *ast.String:
+			text.Write(n.Value)
+		case *ast.CodeSpan:
+			for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+				if t, ok := c.(*ast.Text); ok {
+					text.Write(t.Segment.Value(source))
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(text.String())
+}
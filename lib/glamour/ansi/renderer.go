@@ -1,6 +1,7 @@
 package ansi
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net/url"
@@ -30,6 +31,9 @@ type Options struct {
 // ANSIRenderer renders markdown content as ANSI escaped sequences.
 type ANSIRenderer struct { //nolint: revive
 	Context RenderContext
+
+	// The rendered document, flushed once its headings are located
+	document bytes.Buffer
 }
 
 // NewRenderer returns a new ANSIRenderer with style and options set.
@@ -92,6 +96,16 @@ func (r *ANSIRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 
 	// wikilink
 	reg.Register(ext.KindWikiLink, r.renderNode)
+
+	// obsidian
+	reg.Register(ext.KindCallout, r.renderNode)
+	reg.Register(ext.KindHighlight, r.renderNode)
+	reg.Register(ext.KindComment, r.renderNode)
+	reg.Register(ext.KindInlineComment, r.renderNode)
+
+	// math
+	reg.Register(ext.KindMathBlock, r.renderNode)
+	reg.Register(ext.KindInlineMath, r.renderNode)
 }
 
 func (r *ANSIRenderer) renderNode(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
@@ -109,6 +123,10 @@ func (r *ANSIRenderer) renderNode(w util.BufWriter, source []byte, node ast.Node
 		if bs.Len() > 0 {
 			writeTo = io.Writer(bs.Current().Block)
 		}
+		if node.Type() == ast.TypeDocument {
+			r.document.Reset()
+			writeTo = &r.document
+		}
 
 		_, _ = io.WriteString(writeTo, e.Entering)
 		if e.Renderer != nil {
@@ -124,9 +142,9 @@ func (r *ANSIRenderer) renderNode(w util.BufWriter, source []byte, node ast.Node
 		}
 
 		// if we're finished rendering the entire document,
-		// flush to the real writer
+		// flush to the real writer, once the headings and footnotes are located
 		if node.Type() == ast.TypeDocument {
-			writeTo = w
+			writeTo = &r.document
 		}
 
 		if e.Finisher != nil {
@@ -135,6 +153,13 @@ func (r *ANSIRenderer) renderNode(w util.BufWriter, source []byte, node ast.Node
 				return ast.WalkStop, fmt.Errorf("glamour: error finishing render: %w", err)
 			}
 		}
+		if node.Type() == ast.TypeDocument {
+			located := r.Context.Footnotes.Locate(r.Context.Outline.Locate(r.document.Bytes()))
+			if _, err := w.Write(located); err != nil {
+				return ast.WalkStop, fmt.Errorf("glamour: error writing bytes: %w", err)
+			}
+			r.document.Reset()
+		}
 
 		_, _ = io.WriteString(bs.Current().Block, e.Exiting)
 	}
@@ -146,8 +171,13 @@ func isChild(node ast.Node) bool {
 	for n := node.Parent(); n != nil; n = n.Parent() {
 		// These types are already rendered by their parent
 		switch n.Kind() {
-		case ast.KindCodeSpan, ast.KindAutoLink, ast.KindLink, ast.KindImage, ast.KindEmphasis, astext.KindStrikethrough, astext.KindTableCell:
+		case ast.KindCodeSpan, ast.KindAutoLink, ast.KindLink, ast.KindImage, ast.KindEmphasis, astext.KindStrikethrough, astext.KindTableCell, ext.KindHighlight:
 			return true
+		case ext.KindCallout:
+			// The content of a folded callout is hidden
+			if n.(*ext.Callout).Folded {
+				return true
+			}
 		}
 	}
 
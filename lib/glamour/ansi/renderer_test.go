@@ -8,7 +8,9 @@ import (
 	"strings"
 	"testing"
 
+	xansi "github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/exp/golden"
+	ext "github.com/latentdream/merlion/lib/glamour/extension"
 	"github.com/muesli/termenv"
 	"github.com/yuin/goldmark"
 	emoji "github.com/yuin/goldmark-emoji"
@@ -69,6 +71,7 @@ func TestRenderer(t *testing.T) {
 				goldmark.WithExtensions(
 					extension.GFM,
 					extension.DefinitionList,
+					extension.Footnote,
 					emoji.Emoji,
 				),
 				goldmark.WithParserOptions(
@@ -126,6 +129,7 @@ func TestRendererIssues(t *testing.T) {
 				goldmark.WithExtensions(
 					extension.GFM,
 					extension.DefinitionList,
+					extension.Footnote,
 					emoji.Emoji,
 				),
 				goldmark.WithParserOptions(
@@ -147,3 +151,182 @@ func TestRendererIssues(t *testing.T) {
 		})
 	}
 }
+
+func TestFootnotes(t *testing.T) {
+	in, err := os.ReadFile(examplesDir + "footnote.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+		),
+	)
+	ar := NewRenderer(Options{
+		WordWrap:     80,
+		ColorProfile: termenv.TrueColor,
+	})
+	md.SetRenderer(
+		renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(ar, 1000))))
+
+	render := func() string {
+		ar.Context.Reset()
+		var buf bytes.Buffer
+		if err := md.Convert(in, &buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	out := render()
+	if strings.Contains(out, footnoteMarkPrefix) {
+		t.Errorf("the marks of the footnotes are in the output: %q", out)
+	}
+	for _, tc := range []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"reference to 1", ar.Context.Footnotes.Reference(1, 0), 0},
+		{"reference to 2", ar.Context.Footnotes.Reference(2, 0), 0},
+		{"definition of 1", ar.Context.Footnotes.Definition(1), 2},
+		{"definition of 2", ar.Context.Footnotes.Definition(2), 3},
+		{"missing footnote", ar.Context.Footnotes.Definition(3), -1},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s: expected line %d, got %d", tc.name, tc.want, tc.got)
+		}
+	}
+
+	// The references come first, then the backlinks of the footnotes
+	for idx, want := range []Selector{
+		{Footnote: 1},
+		{Footnote: 2},
+		{Footnote: 1, Backlink: true},
+		{Footnote: 2, Backlink: true},
+	} {
+		ar.Context.Selector.SetElementSelectedIdx(idx)
+		render()
+		if got := ar.Context.Selector.GetSelector(); got == nil || *got != want {
+			t.Errorf("selector %d: expected %+v, got %+v", idx, want, got)
+		}
+	}
+}
+
+// renderExtended renders the markdown with the extensions of Obsidian, in the dark style
+func renderExtended(t *testing.T, in string) string {
+	t.Helper()
+	b, err := os.ReadFile("../styles/dark.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := Options{
+		WordWrap:     80,
+		ColorProfile: termenv.ANSI256,
+	}
+	if err := json.Unmarshal(b, &options.Styles); err != nil {
+		t.Fatal(err)
+	}
+
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			&ext.ExtendedParser{},
+		),
+	)
+	ar := NewRenderer(options)
+	md.SetRenderer(
+		renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(ar, 1000))))
+
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(in), &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestCallouts(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		in     string
+		want   []string
+		hidden []string
+	}{
+		{
+			name: "typed",
+			in:   "> [!warning] Careful\n> Body text\n",
+			want: []string{"│ ⚠ Careful", "│ Body text"},
+		},
+		{
+			name: "title of the type",
+			in:   "> [!faq]\n> Question\n",
+			want: []string{"│ ? Faq", "│ Question"},
+		},
+		{
+			name:   "folded",
+			in:     "> [!tip]- Folded tip\n> Hidden body\n",
+			want:   []string{"│ ✦ Folded tip ▸"},
+			hidden: []string{"Hidden body"},
+		},
+		{
+			name: "foldable",
+			in:   "> [!tip]+ Open tip\n> Shown body\n",
+			want: []string{"│ ✦ Open tip ▾", "│ Shown body"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := xansi.Strip(renderExtended(t, tc.in))
+			for _, want := range tc.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("%q is not in the output: %q", want, out)
+				}
+			}
+			for _, hidden := range tc.hidden {
+				if strings.Contains(out, hidden) {
+					t.Errorf("%q is in the output: %q", hidden, out)
+				}
+			}
+			if strings.Contains(out, "[!") {
+				t.Errorf("the header of the callout is in the output: %q", out)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	out := renderExtended(t, "Some ==marked== text\n")
+	// Black on yellow, the colors of the highlight style
+	if !strings.Contains(out, "\x1b[30;48;5;220mmarked\x1b[0m") {
+		t.Errorf("the text is not highlighted: %q", out)
+	}
+	if strings.Contains(out, "==") {
+		t.Errorf("the markers of the highlight are in the output: %q", out)
+	}
+}
+
+func TestComments(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"inline", "Before %%secret%% after\n", []string{"Before  after"}},
+		{"multi-line", "First\n\n%%\nsecret\nlines\n%%\n\nLast\n", []string{"First", "Last"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := xansi.Strip(renderExtended(t, tc.in))
+			for _, want := range tc.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("%q is not in the output: %q", want, out)
+				}
+			}
+			if strings.Contains(out, "secret") || strings.Contains(out, "%%") {
+				t.Errorf("the comment is in the output: %q", out)
+			}
+		})
+	}
+}
@@ -3,6 +3,12 @@ package ansi
 type Selector struct {
 	Link  string
 	Title string
+
+	// Footnote is the index of the footnote of a reference, from 1. The
+	// Backlink goes from the footnote to its RefIndex reference
+	Footnote int
+	RefIndex int
+	Backlink bool
 }
 
 // Context to use in the rendering process to display the selected element
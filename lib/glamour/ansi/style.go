@@ -94,12 +94,29 @@ type StyleTable struct {
 	RowSeparator    *string `json:"row_separator,omitempty"`
 }
 
+// StyleCalloutType holds the style settings for a type of callout, its color
+// applying to the bar and the title.
+type StyleCalloutType struct {
+	StylePrimitive
+	Icon string `json:"icon,omitempty"`
+}
+
+// StyleCallout holds the style settings for the callouts of Obsidian.
+type StyleCallout struct {
+	StyleBlock
+	Title    StylePrimitive              `json:"title,omitempty"`
+	Folded   string                      `json:"folded,omitempty"`
+	Unfolded string                      `json:"unfolded,omitempty"`
+	Types    map[string]StyleCalloutType `json:"types,omitempty"`
+}
+
 // StyleConfig is used to configure the styling behavior of an ANSIRenderer.
 type StyleConfig struct {
-	Document   StyleBlock `json:"document,omitempty"`
-	BlockQuote StyleBlock `json:"block_quote,omitempty"`
-	Paragraph  StyleBlock `json:"paragraph,omitempty"`
-	List       StyleList  `json:"list,omitempty"`
+	Document   StyleBlock   `json:"document,omitempty"`
+	BlockQuote StyleBlock   `json:"block_quote,omitempty"`
+	Callout    StyleCallout `json:"callout,omitempty"`
+	Paragraph  StyleBlock   `json:"paragraph,omitempty"`
+	List       StyleList    `json:"list,omitempty"`
 
 	Heading StyleBlock `json:"heading,omitempty"`
 	H1      StyleBlock `json:"h1,omitempty"`
@@ -113,6 +130,7 @@ type StyleConfig struct {
 	Strikethrough  StylePrimitive `json:"strikethrough,omitempty"`
 	Emph           StylePrimitive `json:"emph,omitempty"`
 	Strong         StylePrimitive `json:"strong,omitempty"`
+	Highlight      StylePrimitive `json:"highlight,omitempty"`
 	HorizontalRule StylePrimitive `json:"hr,omitempty"`
 
 	Item        StylePrimitive `json:"item,omitempty"`
@@ -132,9 +150,18 @@ type StyleConfig struct {
 
 	Table StyleTable `json:"table,omitempty"`
 
+	Math       StyleBlock     `json:"math,omitempty"`
+	MathInline StylePrimitive `json:"math_inline,omitempty"`
+	MathRaw    StylePrimitive `json:"math_raw,omitempty"`
+
 	DefinitionList        StyleBlock     `json:"definition_list,omitempty"`
 	DefinitionTerm        StylePrimitive `json:"definition_term,omitempty"`
-	DefinitionDescription StylePrimitive `json:"definition_description,omitempty"`
+	DefinitionDescription StyleBlock     `json:"definition_description,omitempty"`
+
+	FootnoteLink     StylePrimitive `json:"footnote_link,omitempty"`
+	FootnoteList     StyleBlock     `json:"footnote_list,omitempty"`
+	Footnote         StylePrimitive `json:"footnote,omitempty"`
+	FootnoteBacklink StylePrimitive `json:"footnote_backlink,omitempty"`
 
 	HTMLBlock StyleBlock `json:"html_block,omitempty"`
 	HTMLSpan  StyleBlock `json:"html_span,omitempty"`
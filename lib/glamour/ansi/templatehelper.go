@@ -53,6 +53,7 @@ var (
 		"Last": func(values ...interface{}) string {
 			return values[0].([]string)[len(values[0].([]string))-1]
 		},
+		"Superscript": superscript,
 		// strings functions
 		"Compare":      strings.Compare, // 1.5+ only
 		"Contains":     strings.Contains,
@@ -84,3 +85,15 @@ var (
 		"TrimSuffix":   strings.TrimSuffix,
 	}
 )
+
+// superscript writes the characters of s in superscript, when they have one.
+func superscript(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if sup, ok := superscripts[r]; ok {
+			r = sup
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package extension
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Callout is a blockquote of Obsidian starting with its type, e.g.
+//
+//	> [!warning]- Title
+//	> Content
+//
+// The `-` folds it, and the `+` makes it foldable but expanded
+type Callout struct {
+	ast.BaseBlock
+	CalloutType string // Lower case, as written
+	Title       string // Empty for the default title
+	Foldable    bool
+	Folded      bool
+}
+
+func (n *Callout) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Type":   n.CalloutType,
+		"Title":  n.Title,
+		"Folded": fmt.Sprint(n.Folded),
+	}, nil)
+}
+
+var KindCallout = ast.NewNodeKind("Callout")
+
+func (n *Callout) Kind() ast.NodeKind { return KindCallout }
+
+var calloutHeader = regexp.MustCompile(`^\[!([\w-]+)\]([+-]?)\s*(.*)$`)
+
+// Custom parser for callouts, before the blockquotes
+type calloutParser struct{}
+
+var CalloutParser = &calloutParser{}
+
+func (p *calloutParser) Trigger() []byte {
+	return []byte{'>'}
+}
+
+func (p *calloutParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	w, pos := util.IndentWidth(line, reader.LineOffset())
+	if w > 3 || pos >= len(line) || line[pos] != '>' {
+		return nil, parser.NoChildren
+	}
+	header := calloutHeader.FindSubmatch(util.TrimRightSpace(util.TrimLeftSpace(line[pos+1:])))
+	if header == nil {
+		return nil, parser.NoChildren
+	}
+
+	// The header is the whole line, the content starts on the next one
+	reader.Advance(segment.Len() - util.TrimRightSpaceLength(line))
+	return &Callout{
+		CalloutType: strings.ToLower(string(header[1])),
+		Title:       strings.TrimSpace(string(header[3])),
+		Foldable:    len(header[2]) > 0,
+		Folded:      string(header[2]) == "-",
+	}, parser.HasChildren
+}
+
+// Continue reads the next line of the callout as a blockquote does
+func (p *calloutParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, _ := reader.PeekLine()
+	w, pos := util.IndentWidth(line, reader.LineOffset())
+	if w > 3 || pos >= len(line) || line[pos] != '>' {
+		return parser.Close
+	}
+	pos++
+	if pos >= len(line) || line[pos] == '\n' {
+		reader.Advance(pos)
+		return parser.Continue | parser.HasChildren
+	}
+	reader.Advance(pos)
+	if line[pos] == ' ' || line[pos] == '\t' {
+		padding := 0
+		if line[pos] == '\t' {
+			padding = util.TabWidth(reader.LineOffset()) - 1
+		}
+		reader.AdvanceAndSetPadding(1, padding)
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+func (p *calloutParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p *calloutParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *calloutParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// Create a renderer for callouts
+type calloutRenderer struct{}
+
+func CalloutHtmlRenderer() renderer.NodeRenderer {
+	return &calloutRenderer{}
+}
+
+func (r *calloutRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindCallout, r.renderCallout)
+}
+
+func (r *calloutRenderer) renderCallout(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*Callout)
+	if entering {
+		_, _ = w.WriteString(fmt.Sprintf(`<div class="callout" data-callout="%s"><div class="callout-title">%s</div>`,
+			html.EscapeString(n.CalloutType), html.EscapeString(n.Title)))
+	} else {
+		_, _ = w.WriteString("</div>")
+	}
+	return ast.WalkContinue, nil
+}
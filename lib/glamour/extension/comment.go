@@ -0,0 +1,125 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var commentDelimiter = []byte("%%")
+
+// Comment is a %% hidden comment %% of Obsidian, on its own lines
+type Comment struct {
+	ast.BaseBlock
+	closed bool // The closing %% was read
+}
+
+func (n *Comment) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+var KindComment = ast.NewNodeKind("Comment")
+
+func (n *Comment) Kind() ast.NodeKind { return KindComment }
+
+// InlineComment is a %% hidden comment %% in a line of text
+type InlineComment struct {
+	ast.BaseInline
+}
+
+func (n *InlineComment) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+var KindInlineComment = ast.NewNodeKind("InlineComment")
+
+func (n *InlineComment) Kind() ast.NodeKind { return KindInlineComment }
+
+// Custom parser for the comments starting a line, up to the line with the closing %%
+type commentParser struct{}
+
+var CommentParser = &commentParser{}
+
+func (p *commentParser) Trigger() []byte {
+	return []byte{'%'}
+}
+
+func (p *commentParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	w, pos := util.IndentWidth(line, reader.LineOffset())
+	if w > 3 || !bytes.HasPrefix(line[pos:], commentDelimiter) {
+		return nil, parser.NoChildren
+	}
+	rest := line[pos+len(commentDelimiter):]
+	closed := bytes.Index(rest, commentDelimiter)
+	if closed != -1 && len(util.TrimRightSpace(rest[closed+len(commentDelimiter):])) > 0 {
+		// Text after the comment, it is inline
+		return nil, parser.NoChildren
+	}
+	reader.Advance(segment.Len() - util.TrimRightSpaceLength(line))
+	return &Comment{closed: closed != -1}, parser.NoChildren
+}
+
+func (p *commentParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if line == nil || node.(*Comment).closed {
+		return parser.Close
+	}
+	reader.Advance(segment.Len() - util.TrimRightSpaceLength(line))
+	if bytes.Contains(line, commentDelimiter) {
+		return parser.Close
+	}
+	return parser.Continue | parser.NoChildren
+}
+
+func (p *commentParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p *commentParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *commentParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// Custom parser for the comments closed in the same line
+type inlineCommentParser struct{}
+
+var InlineCommentParser = &inlineCommentParser{}
+
+func (p *inlineCommentParser) Trigger() []byte {
+	return []byte{'%'}
+}
+
+func (p *inlineCommentParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if !bytes.HasPrefix(line, commentDelimiter) {
+		return nil
+	}
+	end := bytes.Index(line[len(commentDelimiter):], commentDelimiter)
+	if end == -1 {
+		return nil
+	}
+	block.Advance(end + 2*len(commentDelimiter))
+	return &InlineComment{}
+}
+
+// Create a renderer for comments, they are never rendered
+type commentRenderer struct{}
+
+func CommentHtmlRenderer() renderer.NodeRenderer {
+	return &commentRenderer{}
+}
+
+func (r *commentRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindComment, r.renderComment)
+	reg.Register(KindInlineComment, r.renderComment)
+}
+
+func (r *commentRenderer) renderComment(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
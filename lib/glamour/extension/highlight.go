@@ -0,0 +1,83 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Highlight is the ==highlighted text== of Obsidian
+type Highlight struct {
+	ast.BaseInline
+}
+
+func (n *Highlight) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+var KindHighlight = ast.NewNodeKind("Highlight")
+
+func (n *Highlight) Kind() ast.NodeKind { return KindHighlight }
+
+type highlightDelimiterProcessor struct{}
+
+func (p *highlightDelimiterProcessor) IsDelimiter(b byte) bool {
+	return b == '='
+}
+
+func (p *highlightDelimiterProcessor) CanOpenCloser(opener, closer *parser.Delimiter) bool {
+	return opener.Char == closer.Char
+}
+
+func (p *highlightDelimiterProcessor) OnMatch(consumes int) ast.Node {
+	return &Highlight{}
+}
+
+var defaultHighlightDelimiterProcessor = &highlightDelimiterProcessor{}
+
+// Custom parser for highlights ==text==, delimited as the strikethrough of GFM
+type highlightParser struct{}
+
+var HighlightParser = &highlightParser{}
+
+func (p *highlightParser) Trigger() []byte {
+	return []byte{'='}
+}
+
+func (p *highlightParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	before := block.PrecendingCharacter()
+	line, segment := block.PeekLine()
+	node := parser.ScanDelimiter(line, before, 2, defaultHighlightDelimiterProcessor)
+	if node == nil || node.OriginalLength != 2 || before == '=' {
+		return nil
+	}
+
+	node.Segment = segment.WithStop(segment.Start + node.OriginalLength)
+	block.Advance(node.OriginalLength)
+	pc.PushDelimiter(node)
+	return node
+}
+
+func (p *highlightParser) CloseBlock(parent ast.Node, pc parser.Context) {}
+
+// Create a renderer for highlights
+type highlightRenderer struct{}
+
+func HighlightHtmlRenderer() renderer.NodeRenderer {
+	return &highlightRenderer{}
+}
+
+func (r *highlightRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindHighlight, r.renderHighlight)
+}
+
+func (r *highlightRenderer) renderHighlight(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<mark>")
+	} else {
+		_, _ = w.WriteString("</mark>")
+	}
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,179 @@
+package extension
+
+import (
+	"bytes"
+	"html"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var mathDelimiter = []byte("$$")
+
+// MathBlock is a $$display formula$$ of LaTeX, on its own lines. The formula is in its lines
+type MathBlock struct {
+	ast.BaseBlock
+	closed bool // The closing $$ was read
+}
+
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// IsRaw tells the formula is not parsed as markdown
+func (n *MathBlock) IsRaw() bool {
+	return true
+}
+
+var KindMathBlock = ast.NewNodeKind("MathBlock")
+
+func (n *MathBlock) Kind() ast.NodeKind { return KindMathBlock }
+
+// InlineMath is a $formula$ of LaTeX in a line of text, or a $$formula$$ in the middle of it
+type InlineMath struct {
+	ast.BaseInline
+	Formula string
+}
+
+func (n *InlineMath) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Formula": n.Formula}, nil)
+}
+
+var KindInlineMath = ast.NewNodeKind("InlineMath")
+
+func (n *InlineMath) Kind() ast.NodeKind { return KindInlineMath }
+
+// Custom parser for the formulas starting a line with $$, up to the line with the closing $$
+type mathBlockParser struct{}
+
+var MathBlockParser = &mathBlockParser{}
+
+func (p *mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	w, pos := util.IndentWidth(line, reader.LineOffset())
+	if w > 3 || !bytes.HasPrefix(line[pos:], mathDelimiter) {
+		return nil, parser.NoChildren
+	}
+	start := pos + len(mathDelimiter)
+	rest := line[start:]
+	node := &MathBlock{}
+	end := len(util.TrimRightSpace(rest))
+	if closed := bytes.Index(rest, mathDelimiter); closed != -1 {
+		if len(util.TrimRightSpace(rest[closed+len(mathDelimiter):])) > 0 {
+			// Text after the formula, it is inline
+			return nil, parser.NoChildren
+		}
+		node.closed = true
+		end = closed
+	}
+	if len(bytes.TrimSpace(rest[:end])) > 0 {
+		node.Lines().Append(text.NewSegment(segment.Start+start, segment.Start+start+end))
+	}
+	reader.Advance(segment.Len() - util.TrimRightSpaceLength(line))
+	return node, parser.NoChildren
+}
+
+func (p *mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	n := node.(*MathBlock)
+	line, segment := reader.PeekLine()
+	if line == nil || n.closed {
+		return parser.Close
+	}
+	reader.Advance(segment.Len() - util.TrimRightSpaceLength(line))
+	if closed := bytes.Index(line, mathDelimiter); closed != -1 {
+		n.closed = true
+		if len(bytes.TrimSpace(line[:closed])) > 0 {
+			n.Lines().Append(text.NewSegment(segment.Start, segment.Start+closed))
+		}
+		return parser.Close
+	}
+	n.Lines().Append(segment)
+	return parser.Continue | parser.NoChildren
+}
+
+func (p *mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p *mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// Custom parser for the formulas in a line: the $ are not followed or preceded by a space inside the
+// formula, nor followed by a digit after it, so that amounts as $5 and $10 are not formulas
+type inlineMathParser struct{}
+
+var InlineMathParser = &inlineMathParser{}
+
+func (p *inlineMathParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *inlineMathParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if bytes.HasPrefix(line, mathDelimiter) {
+		end := bytes.Index(line[len(mathDelimiter):], mathDelimiter)
+		if end <= 0 {
+			return nil
+		}
+		block.Advance(end + 2*len(mathDelimiter))
+		return &InlineMath{Formula: string(line[len(mathDelimiter) : len(mathDelimiter)+end])}
+	}
+
+	if len(line) < 3 || util.IsSpace(line[1]) || line[1] == '$' {
+		return nil
+	}
+	for i := 2; i < len(line); i++ {
+		switch {
+		case line[i] == '\\':
+			i++
+		case line[i] == '$':
+			if util.IsSpace(line[i-1]) || (i+1 < len(line) && line[i+1] >= '0' && line[i+1] <= '9') {
+				continue
+			}
+			block.Advance(i + 1)
+			return &InlineMath{Formula: string(line[1:i])}
+		}
+	}
+	return nil
+}
+
+// Create a renderer for formulas, left as LaTeX for the browser
+type mathRenderer struct{}
+
+func MathHtmlRenderer() renderer.NodeRenderer {
+	return &mathRenderer{}
+}
+
+func (r *mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMathBlock, r.renderMathBlock)
+	reg.Register(KindInlineMath, r.renderInlineMath)
+}
+
+func (r *mathRenderer) renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<div class="math">\[`)
+		for i := 0; i < node.Lines().Len(); i++ {
+			line := node.Lines().At(i)
+			_, _ = w.WriteString(html.EscapeString(string(line.Value(source))))
+		}
+		_, _ = w.WriteString(`\]</div>`)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *mathRenderer) renderInlineMath(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<span class="math">\(` + html.EscapeString(node.(*InlineMath).Formula) + `\)</span>`)
+	}
+	return ast.WalkSkipChildren, nil
+}
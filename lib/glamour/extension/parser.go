@@ -11,13 +11,26 @@ type ExtendedParser struct{}
 
 func (e *ExtendedParser) Extend(m goldmark.Markdown) {
 	m.Parser().AddOptions(
+		parser.WithBlockParsers(
+			// Before the blockquotes
+			util.Prioritized(CalloutParser, 799),
+			util.Prioritized(CommentParser, 101),
+			util.Prioritized(MathBlockParser, 101),
+		),
 		parser.WithInlineParsers(
 			util.Prioritized(WikiLinkParser, 101),
+			util.Prioritized(HighlightParser, 500),
+			util.Prioritized(InlineCommentParser, 101),
+			util.Prioritized(InlineMathParser, 101),
 		),
 	)
 	m.Renderer().AddOptions(
 		renderer.WithNodeRenderers(
 			util.Prioritized(WikiLinkHtmlRenderer(), 101),
+			util.Prioritized(CalloutHtmlRenderer(), 101),
+			util.Prioritized(HighlightHtmlRenderer(), 101),
+			util.Prioritized(CommentHtmlRenderer(), 101),
+			util.Prioritized(MathHtmlRenderer(), 101),
 		),
 	)
 }
@@ -76,6 +76,7 @@ func NewTermRenderer(options ...TermRendererOption) (*TermRenderer, error) {
 			goldmark.WithExtensions(
 				extension.GFM,
 				extension.DefinitionList,
+				extension.Footnote,
 				&ext.ExtendedParser{},
 			),
 			goldmark.WithParserOptions(
@@ -316,6 +317,21 @@ func (tr *TermRenderer) ClearSelector() {
 	tr.rendererContext.Selector.ResetState()
 }
 
+// Headings returns the headings of the last rendered markdown, with their line in the output
+func (tr *TermRenderer) Headings() []ansi.Heading {
+	return tr.rendererContext.Outline.Headings()
+}
+
+// FootnoteDefinition returns the line of the definition of the footnote in the last rendered markdown, -1 if none
+func (tr *TermRenderer) FootnoteDefinition(index int) int {
+	return tr.rendererContext.Footnotes.Definition(index)
+}
+
+// FootnoteReference returns the line of the refIndex reference to the footnote in the last rendered markdown, -1 if none
+func (tr *TermRenderer) FootnoteReference(index, refIndex int) int {
+	return tr.rendererContext.Footnotes.Reference(index, refIndex)
+}
+
 func (tr *TermRenderer) SetWidth(wordWrap int) {
 	tr.ansiOptions.WordWrap = wordWrap
 	tr.rendererContext.SetWordWrap(wordWrap)
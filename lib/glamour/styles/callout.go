@@ -0,0 +1,67 @@
+package styles
+
+import "github.com/latentdream/merlion/lib/glamour/ansi"
+
+var (
+	// CalloutIcons are the icons of the callout types of Obsidian.
+	CalloutIcons = map[string]string{
+		"note":     "✎",
+		"abstract": "≡",
+		"info":     "ℹ",
+		"todo":     "☐",
+		"tip":      "✦",
+		"success":  "✓",
+		"question": "?",
+		"warning":  "⚠",
+		"failure":  "✗",
+		"danger":   "‼",
+		"bug":      "⚙",
+		"example":  "◆",
+		"quote":    "❝",
+	}
+	// asciiCalloutIcons are the icons of the callout types with only ASCII characters.
+	asciiCalloutIcons = map[string]string{
+		"note":     "*",
+		"abstract": "=",
+		"info":     "i",
+		"todo":     "[ ]",
+		"tip":      "+",
+		"success":  "v",
+		"question": "?",
+		"warning":  "!",
+		"failure":  "x",
+		"danger":   "!!",
+		"bug":      "#",
+		"example":  ">",
+		"quote":    "\"",
+	}
+)
+
+// calloutStyle returns the style of the callouts with the colors of their types, the
+// types without a color using the one of the text.
+func calloutStyle(ascii bool, colors map[string]string) ansi.StyleCallout {
+	icons, indentToken, folded, unfolded := CalloutIcons, "│ ", " ▸", " ▾"
+	title := ansi.StylePrimitive{Bold: boolPtr(true)}
+	if ascii {
+		icons, indentToken, folded, unfolded = asciiCalloutIcons, "| ", " >", " v"
+		title = ansi.StylePrimitive{}
+	}
+	types := make(map[string]ansi.StyleCalloutType, len(icons))
+	for name, icon := range icons {
+		t := ansi.StyleCalloutType{Icon: icon}
+		if color, ok := colors[name]; ok {
+			t.Color = stringPtr(color)
+		}
+		types[name] = t
+	}
+	return ansi.StyleCallout{
+		StyleBlock: ansi.StyleBlock{
+			Indent:      uintPtr(1),
+			IndentToken: stringPtr(indentToken),
+		},
+		Title:    title,
+		Folded:   folded,
+		Unfolded: unfolded,
+		Types:    types,
+	}
+}
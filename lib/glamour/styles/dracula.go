@@ -19,6 +19,21 @@ var DraculaStyleConfig = ansi.StyleConfig{
 		},
 		Indent: uintPtr(defaultMargin),
 	},
+	Callout: calloutStyle(false, map[string]string{
+		"note":     "#8be9fd",
+		"abstract": "#8be9fd",
+		"info":     "#8be9fd",
+		"todo":     "#8be9fd",
+		"tip":      "#50fa7b",
+		"success":  "#50fa7b",
+		"question": "#f1fa8c",
+		"warning":  "#ffb86c",
+		"failure":  "#ff5555",
+		"danger":   "#ff5555",
+		"bug":      "#ff5555",
+		"example":  "#bd93f9",
+		"quote":    "#6272a4",
+	}),
 	List: ansi.StyleList{
 		LevelIndent: defaultMargin,
 		StyleBlock: ansi.StyleBlock{
@@ -75,6 +90,10 @@ var DraculaStyleConfig = ansi.StyleConfig{
 		Bold:  boolPtr(true),
 		Color: stringPtr("#ffb86c"),
 	},
+	Highlight: ansi.StylePrimitive{
+		Color:           stringPtr("#282a36"),
+		BackgroundColor: stringPtr("#f1fa8c"),
+	},
 	HorizontalRule: ansi.StylePrimitive{
 		Color:  stringPtr("#6272A4"),
 		Format: "\n--------\n",
@@ -205,12 +224,40 @@ var DraculaStyleConfig = ansi.StyleConfig{
 			},
 		},
 	},
+	Math: ansi.StyleBlock{
+		StylePrimitive: ansi.StylePrimitive{
+			Color: stringPtr("#bd93f9"),
+		},
+		Indent: uintPtr(defaultMargin),
+	},
+	MathInline: ansi.StylePrimitive{
+		Color: stringPtr("#bd93f9"),
+	},
+	MathRaw: ansi.StylePrimitive{
+		Color: stringPtr("#ff5555"),
+	},
 	Table: ansi.StyleTable{
 		StyleBlock: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{},
 		},
 	},
-	DefinitionDescription: ansi.StylePrimitive{
-		BlockPrefix: "\n🠶 ",
+	DefinitionTerm: ansi.StylePrimitive{
+		Bold: boolPtr(true),
+	},
+	DefinitionDescription: ansi.StyleBlock{
+		Indent: uintPtr(4),
+	},
+	FootnoteLink: ansi.StylePrimitive{
+		Color:  stringPtr("#8be9fd"),
+		Format: "{{Superscript .text}}",
+	},
+	FootnoteList: ansi.StyleBlock{},
+	Footnote: ansi.StylePrimitive{
+		BlockSuffix: ". ",
+		Color:       stringPtr("#8be9fd"),
+	},
+	FootnoteBacklink: ansi.StylePrimitive{
+		BlockPrefix: " ",
+		Color:       stringPtr("#8be9fd"),
 	},
 }
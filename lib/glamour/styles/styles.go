@@ -40,6 +40,7 @@ var (
 			Indent:         uintPtr(1),
 			IndentToken:    stringPtr("| "),
 		},
+		Callout: calloutStyle(true, nil),
 		Paragraph: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{},
 		},
@@ -96,6 +97,10 @@ var (
 			BlockPrefix: "**",
 			BlockSuffix: "**",
 		},
+		Highlight: ansi.StylePrimitive{
+			BlockPrefix: "==",
+			BlockSuffix: "==",
+		},
 		HorizontalRule: ansi.StylePrimitive{
 			Format: "\n--------\n",
 		},
@@ -123,13 +128,30 @@ var (
 				Margin: uintPtr(defaultMargin),
 			},
 		},
+		Math: ansi.StyleBlock{
+			Indent: uintPtr(defaultMargin),
+		},
+		MathInline: ansi.StylePrimitive{},
+		MathRaw:    ansi.StylePrimitive{},
 		Table: ansi.StyleTable{
 			CenterSeparator: stringPtr("|"),
 			ColumnSeparator: stringPtr("|"),
 			RowSeparator:    stringPtr("-"),
 		},
-		DefinitionDescription: ansi.StylePrimitive{
-			BlockPrefix: "\n* ",
+		DefinitionTerm: ansi.StylePrimitive{},
+		DefinitionDescription: ansi.StyleBlock{
+			Indent: uintPtr(4),
+		},
+		FootnoteLink: ansi.StylePrimitive{
+			Format: "[{{.text}}]",
+		},
+		FootnoteList: ansi.StyleBlock{},
+		Footnote: ansi.StylePrimitive{
+			BlockSuffix: ". ",
+		},
+		FootnoteBacklink: ansi.StylePrimitive{
+			BlockPrefix: " ",
+			Format:      "^",
 		},
 	}
 
@@ -148,6 +170,21 @@ var (
 			Indent:         uintPtr(1),
 			IndentToken:    stringPtr("│ "),
 		},
+		Callout: calloutStyle(false, map[string]string{
+			"note":     "39",
+			"abstract": "44",
+			"info":     "39",
+			"todo":     "39",
+			"tip":      "44",
+			"success":  "35",
+			"question": "214",
+			"warning":  "208",
+			"failure":  "203",
+			"danger":   "196",
+			"bug":      "203",
+			"example":  "141",
+			"quote":    "245",
+		}),
 		List: ansi.StyleList{
 			LevelIndent: defaultListIndent,
 		},
@@ -203,6 +240,10 @@ var (
 		Strong: ansi.StylePrimitive{
 			Bold: boolPtr(true),
 		},
+		Highlight: ansi.StylePrimitive{
+			Color:           stringPtr("0"),
+			BackgroundColor: stringPtr("220"),
+		},
 		HorizontalRule: ansi.StylePrimitive{
 			Color:  stringPtr("240"),
 			Format: "\n--------\n",
@@ -333,13 +374,41 @@ var (
 				},
 			},
 		},
+		Math: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Color: stringPtr("141"),
+			},
+			Indent: uintPtr(defaultMargin),
+		},
+		MathInline: ansi.StylePrimitive{
+			Color: stringPtr("141"),
+		},
+		MathRaw: ansi.StylePrimitive{
+			Color: stringPtr("203"),
+		},
 		Table: ansi.StyleTable{
 			StyleBlock: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{},
 			},
 		},
-		DefinitionDescription: ansi.StylePrimitive{
-			BlockPrefix: "\n🠶 ",
+		DefinitionTerm: ansi.StylePrimitive{
+			Bold: boolPtr(true),
+		},
+		DefinitionDescription: ansi.StyleBlock{
+			Indent: uintPtr(4),
+		},
+		FootnoteLink: ansi.StylePrimitive{
+			Color:  stringPtr("30"),
+			Format: "{{Superscript .text}}",
+		},
+		FootnoteList: ansi.StyleBlock{},
+		Footnote: ansi.StylePrimitive{
+			BlockSuffix: ". ",
+			Color:       stringPtr("30"),
+		},
+		FootnoteBacklink: ansi.StylePrimitive{
+			BlockPrefix: " ",
+			Color:       stringPtr("30"),
 		},
 	}
 
@@ -358,6 +427,21 @@ var (
 			Indent:         uintPtr(1),
 			IndentToken:    stringPtr("│ "),
 		},
+		Callout: calloutStyle(false, map[string]string{
+			"note":     "27",
+			"abstract": "30",
+			"info":     "27",
+			"todo":     "27",
+			"tip":      "30",
+			"success":  "28",
+			"question": "130",
+			"warning":  "166",
+			"failure":  "160",
+			"danger":   "124",
+			"bug":      "160",
+			"example":  "91",
+			"quote":    "242",
+		}),
 		List: ansi.StyleList{
 			LevelIndent: defaultListIndent,
 		},
@@ -412,6 +496,10 @@ var (
 		Strong: ansi.StylePrimitive{
 			Bold: boolPtr(true),
 		},
+		Highlight: ansi.StylePrimitive{
+			Color:           stringPtr("0"),
+			BackgroundColor: stringPtr("228"),
+		},
 		HorizontalRule: ansi.StylePrimitive{
 			Color:  stringPtr("249"),
 			Format: "\n--------\n",
@@ -542,13 +630,41 @@ var (
 				},
 			},
 		},
+		Math: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Color: stringPtr("91"),
+			},
+			Indent: uintPtr(defaultMargin),
+		},
+		MathInline: ansi.StylePrimitive{
+			Color: stringPtr("91"),
+		},
+		MathRaw: ansi.StylePrimitive{
+			Color: stringPtr("160"),
+		},
 		Table: ansi.StyleTable{
 			StyleBlock: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{},
 			},
 		},
-		DefinitionDescription: ansi.StylePrimitive{
-			BlockPrefix: "\n🠶 ",
+		DefinitionTerm: ansi.StylePrimitive{
+			Bold: boolPtr(true),
+		},
+		DefinitionDescription: ansi.StyleBlock{
+			Indent: uintPtr(4),
+		},
+		FootnoteLink: ansi.StylePrimitive{
+			Color:  stringPtr("36"),
+			Format: "{{Superscript .text}}",
+		},
+		FootnoteList: ansi.StyleBlock{},
+		Footnote: ansi.StylePrimitive{
+			BlockSuffix: ". ",
+			Color:       stringPtr("36"),
+		},
+		FootnoteBacklink: ansi.StylePrimitive{
+			BlockPrefix: " ",
+			Color:       stringPtr("36"),
 		},
 	}
 
@@ -561,6 +677,21 @@ var (
 			Indent:      uintPtr(1),
 			IndentToken: stringPtr("│ "),
 		},
+		Callout: calloutStyle(false, map[string]string{
+			"note":     "212",
+			"abstract": "219",
+			"info":     "212",
+			"todo":     "212",
+			"tip":      "219",
+			"success":  "218",
+			"question": "213",
+			"warning":  "211",
+			"failure":  "204",
+			"danger":   "197",
+			"bug":      "204",
+			"example":  "177",
+			"quote":    "245",
+		}),
 		List: ansi.StyleList{
 			LevelIndent: defaultListIndent,
 		},
@@ -614,6 +745,10 @@ var (
 		Strong: ansi.StylePrimitive{
 			Bold: boolPtr(true),
 		},
+		Highlight: ansi.StylePrimitive{
+			Color:           stringPtr("0"),
+			BackgroundColor: stringPtr("212"),
+		},
 		HorizontalRule: ansi.StylePrimitive{
 			Color:  stringPtr("212"),
 			Format: "\n──────\n",
@@ -649,11 +784,38 @@ var (
 				Suffix:          " ",
 			},
 		},
+		Math: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Color: stringPtr("212"),
+			},
+			Indent: uintPtr(defaultMargin),
+		},
+		MathInline: ansi.StylePrimitive{
+			Color: stringPtr("212"),
+		},
+		MathRaw: ansi.StylePrimitive{
+			Color: stringPtr("203"),
+		},
 		Table:          ansi.StyleTable{},
 		DefinitionList: ansi.StyleBlock{},
-		DefinitionTerm: ansi.StylePrimitive{},
-		DefinitionDescription: ansi.StylePrimitive{
-			BlockPrefix: "\n🠶 ",
+		DefinitionTerm: ansi.StylePrimitive{
+			Bold: boolPtr(true),
+		},
+		DefinitionDescription: ansi.StyleBlock{
+			Indent: uintPtr(4),
+		},
+		FootnoteLink: ansi.StylePrimitive{
+			Color:  stringPtr("99"),
+			Format: "{{Superscript .text}}",
+		},
+		FootnoteList: ansi.StyleBlock{},
+		Footnote: ansi.StylePrimitive{
+			BlockSuffix: ". ",
+			Color:       stringPtr("99"),
+		},
+		FootnoteBacklink: ansi.StylePrimitive{
+			BlockPrefix: " ",
+			Color:       stringPtr("99"),
 		},
 		HTMLBlock: ansi.StyleBlock{},
 		HTMLSpan:  ansi.StyleBlock{},
@@ -17,6 +17,21 @@ var TokyoNightStyleConfig = ansi.StyleConfig{
 		Indent:         uintPtr(1),
 		IndentToken:    stringPtr("│ "),
 	},
+	Callout: calloutStyle(false, map[string]string{
+		"note":     "#7aa2f7",
+		"abstract": "#7dcfff",
+		"info":     "#7aa2f7",
+		"todo":     "#7aa2f7",
+		"tip":      "#7dcfff",
+		"success":  "#9ece6a",
+		"question": "#e0af68",
+		"warning":  "#ff9e64",
+		"failure":  "#f7768e",
+		"danger":   "#f7768e",
+		"bug":      "#f7768e",
+		"example":  "#bb9af7",
+		"quote":    "#565f89",
+	}),
 	List: ansi.StyleList{
 		StyleBlock: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
@@ -72,6 +87,10 @@ var TokyoNightStyleConfig = ansi.StyleConfig{
 	Strong: ansi.StylePrimitive{
 		Bold: boolPtr(true),
 	},
+	Highlight: ansi.StylePrimitive{
+		Color:           stringPtr("#1a1b26"),
+		BackgroundColor: stringPtr("#e0af68"),
+	},
 	HorizontalRule: ansi.StylePrimitive{
 		Color:  stringPtr("#565f89"),
 		Format: "\n--------\n",
@@ -198,12 +217,40 @@ var TokyoNightStyleConfig = ansi.StyleConfig{
 			},
 		},
 	},
+	Math: ansi.StyleBlock{
+		StylePrimitive: ansi.StylePrimitive{
+			Color: stringPtr("#bb9af7"),
+		},
+		Indent: uintPtr(defaultMargin),
+	},
+	MathInline: ansi.StylePrimitive{
+		Color: stringPtr("#bb9af7"),
+	},
+	MathRaw: ansi.StylePrimitive{
+		Color: stringPtr("#f7768e"),
+	},
 	Table: ansi.StyleTable{
 		StyleBlock: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{},
 		},
 	},
-	DefinitionDescription: ansi.StylePrimitive{
-		BlockPrefix: "\n🠶 ",
+	DefinitionTerm: ansi.StylePrimitive{
+		Bold: boolPtr(true),
+	},
+	DefinitionDescription: ansi.StyleBlock{
+		Indent: uintPtr(4),
+	},
+	FootnoteLink: ansi.StylePrimitive{
+		Color:  stringPtr("#7aa2f7"),
+		Format: "{{Superscript .text}}",
+	},
+	FootnoteList: ansi.StyleBlock{},
+	Footnote: ansi.StylePrimitive{
+		BlockSuffix: ". ",
+		Color:       stringPtr("#7aa2f7"),
+	},
+	FootnoteBacklink: ansi.StylePrimitive{
+		BlockPrefix: " ",
+		Color:       stringPtr("#7aa2f7"),
 	},
 }